@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/gjbae1212/grpc-vpn/internal"
@@ -13,6 +14,12 @@ import (
 
 const (
 	UserCtxName = "user"
+
+	// optional values which auth method injects with user.
+	PrincipalCtxName  = "principal"  // authenticated principal(string, e.g. aws iam arn)
+	GroupsCtxName     = "groups"     // vpn groups which principal is mapped to([]string, e.g. aws role_groups, ldap and openid group_mapping)
+	RolesCtxName      = "roles"      // roles of principal([]string, e.g. aws iam role)
+	AttributesCtxName = "attributes" // attributes of principal(map[string]string, e.g. google hd)
 )
 
 const (
@@ -20,11 +27,18 @@ const (
 	AuthorizationHeader = "authorization"
 	Basic               = "basic"
 	Bearer              = "bearer"
+
+	// session id which vpn client wants to resume.
+	SessionIDHeader = "vpn-session-id"
 )
 
 type ClientAuthMethod func(conn protocol.VPNClient) (jwt string, err error)
 type ServerAuthMethod grpc.UnaryServerInterceptor
 
+// RefreshingClientAuthMethod is ClientAuthMethod which also returns refresh token issued by vpn server.
+// vpn client renews jwt with refresh token before it's expired, without reconnecting.
+type RefreshingClientAuthMethod func(conn protocol.VPNClient) (jwt string, refreshToken string, err error)
+
 type ServerManager interface {
 	ServerAuth() (ServerAuthMethod, bool)
 }
@@ -33,6 +47,13 @@ type ClientManager interface {
 	ClientAuth() (ClientAuthMethod, bool)
 }
 
+// RefreshingClientManager is ClientManager which returns RefreshingClientAuthMethod too.
+// ClientManagers of this package implement it.
+type RefreshingClientManager interface {
+	ClientManager
+	RefreshingClientAuth() (RefreshingClientAuthMethod, bool)
+}
+
 type defaultConfig struct{}
 
 // AuthMethod returns ServerAuth and bool value(whether exist or not).
@@ -51,9 +72,14 @@ func (c *defaultConfig) ServerAuth() (ServerAuthMethod, bool) {
 	}, true
 }
 
-// AuthMethod is returns ClientAuth for Test.
+// ClientAuth is returns ClientAuthMethod for Test.
 func (c *defaultConfig) ClientAuth() (ClientAuthMethod, bool) {
-	return func(conn protocol.VPNClient) (jwt string, err error) {
+	return clientAuth(c)
+}
+
+// RefreshingClientAuth is returns RefreshingClientAuthMethod for Test.
+func (c *defaultConfig) RefreshingClientAuth() (RefreshingClientAuthMethod, bool) {
+	return func(conn protocol.VPNClient) (jwt string, refreshToken string, err error) {
 		// Timeout 30 seconds
 		ctx := context.Background()
 		timeout := 30 * time.Second
@@ -65,19 +91,65 @@ func (c *defaultConfig) ClientAuth() (ClientAuthMethod, bool) {
 			AuthType: protocol.AuthType_AT_TEST,
 		})
 		if err != nil {
-			return "", err
+			return "", "", err
 		}
 
 		// extract JWT
 		switch result.ErrorCode {
 		case protocol.ErrorCode_EC_SUCCESS:
-			return result.Jwt, nil
+			return result.Jwt, result.RefreshToken, nil
 		default:
-			return "", internal.ErrorUnauthorized
+			return "", "", internal.ErrorUnauthorized
 		}
 	}, true
 }
 
+// clientAuth returns ClientAuthMethod of manager, which drops refresh token.
+func clientAuth(m RefreshingClientManager) (ClientAuthMethod, bool) {
+	method, ok := m.RefreshingClientAuth()
+	if !ok {
+		return nil, false
+	}
+	return func(conn protocol.VPNClient) (string, error) {
+		jwt, _, err := method(conn)
+		return jwt, err
+	}, true
+}
+
+// injectIdentity injects user and optional values which auth method gives, empty values aren't injected.
+func injectIdentity(ctx context.Context, user, principal string, groups, roles []string, attributes map[string]string) context.Context {
+	newCtx := context.WithValue(ctx, UserCtxName, user)
+	if principal != "" {
+		newCtx = context.WithValue(newCtx, PrincipalCtxName, principal)
+	}
+	if len(groups) > 0 {
+		newCtx = context.WithValue(newCtx, GroupsCtxName, groups)
+	}
+	if len(roles) > 0 {
+		newCtx = context.WithValue(newCtx, RolesCtxName, roles)
+	}
+	if len(attributes) > 0 {
+		newCtx = context.WithValue(newCtx, AttributesCtxName, attributes)
+	}
+	return newCtx
+}
+
+// mappedGroups returns sorted vpn groups of mapping(map[vpn group][]member) which have a matched member.
+// groups of identity providers aren't given to vpn by themselves, they must be mapped explicitly.
+func mappedGroups(mapping map[string][]string, match func(member string) bool) []string {
+	var groups []string
+	for group, members := range mapping {
+		for _, member := range members {
+			if match(member) {
+				groups = append(groups, group)
+				break
+			}
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
 // NewServerManagerForTest returns ServerManager for test.
 func NewServerManagerForTest() (ServerManager, error) {
 	return &defaultConfig{}, nil
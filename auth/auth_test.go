@@ -68,6 +68,39 @@ func TestDefaultConfig_ClientAuth(t *testing.T) {
 	}
 }
 
+func TestDefaultConfig_RefreshingClientAuth(t *testing.T) {
+	assert := assert.New(t)
+
+	// client managers of this package return refresh token too.
+	managers := map[string]ClientManager{
+		"test":          &defaultConfig{},
+		"google_openid": &GoogleOpenIDConfig{},
+		"aws_iam":       &AwsIamConfig{},
+		"ldap":          &LdapConfig{},
+		"openid":        &OpenIDConfig{},
+		"mtls":          &MTLSConfig{},
+	}
+	for _, m := range managers {
+		_, ok := m.(RefreshingClientManager)
+		assert.True(ok)
+	}
+
+	s, _ := NewClientManagerForTest()
+	refreshing, ok := s.(RefreshingClientManager).RefreshingClientAuth()
+	assert.True(ok)
+	jwt, refreshToken, err := refreshing(&fakeAuthServer{})
+	assert.NoError(err)
+	assert.Equal("jwt", jwt)
+	assert.Equal("refresh", refreshToken)
+
+	// ClientAuthMethod drops refresh token.
+	method, ok := s.ClientAuth()
+	assert.True(ok)
+	jwt, err = method(&fakeAuthServer{})
+	assert.NoError(err)
+	assert.Equal("jwt", jwt)
+}
+
 func TestJWTAuthHeaderForGRPC(t *testing.T) {
 	assert := assert.New(t)
 
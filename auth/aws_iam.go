@@ -2,6 +2,11 @@ package auth
 
 import (
 	"context"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
@@ -18,17 +23,56 @@ import (
 	"google.golang.org/grpc"
 )
 
+const (
+	defaultAwsStsEndpoint = "https://sts.amazonaws.com"
+	defaultAwsStsRegion   = "us-east-1"
+	awsServerIdHeader     = "X-Grpc-Vpn-Server-ID"
+)
+
+// AwsIamConfig is a config for aws iam authentication.
+// vpn client signs sts:GetCallerIdentity request locally and sends only the signed request(not credentials),
+// and vpn server forwards it to sts endpoint and gets identity of the client.
+// signed request includes X-Grpc-Vpn-Server-ID header, so it can't be replayed to other servers.
 type AwsIamConfig struct {
-	ClientAccessKey       string
+	ClientAccessKey       string // optional, if empty, default credential chain(env, shared config, instance role) is used.
 	ClientSecretAccessKey string
+	ClientSessionToken    string // optional, session token of temporary credentials
+	ClientProfile         string // optional, profile of shared config
+	ClientStsEndpoint     string // optional, it must be equal to vpn server's sts endpoint
+	ClientStsRegion       string // optional, signing region of sts endpoint
+	ClientServerId        string // server id of vpn server, it is signed in X-Grpc-Vpn-Server-ID header
+
+	ServerAccountId   string              // server allow account
+	ServerId          string              // server id which clients must sign in X-Grpc-Vpn-Server-ID header
+	ServerAllowUsers  []string            // allow iam users(name)
+	ServerAllowRoles  []string            // allow roles(name), sessions of assumed role are allowed
+	ServerAllowPaths  []string            // allow path prefixes of iam users(e.g. /engineering/)
+	ServerAllowArns   []string            // allow arn patterns, '*' matches any characters
+	ServerRoleGroups  map[string][]string // vpn groups of roles(map[group][]role)
+	ServerStsEndpoint string              // optional, sts endpoint which signed requests are forwarded to
+}
+
+// awsPrincipal is a principal parsed from arn of sts:GetCallerIdentity.
+type awsPrincipal struct {
+	arn     string
+	account string
+	kind    string // user, assumed-role, federated-user, root
+	path    string // path of iam user
+	name    string // name of iam user or federated user
+	role    string // role name of assumed role
+	session string // session name of assumed role
+}
 
-	ServerAllowUsers []string // allow users
-	ServerAccountId  string   // server allow users
+// stsIdentity is a result of sts:GetCallerIdentity.
+type stsIdentity struct {
+	Arn     string `xml:"GetCallerIdentityResult>Arn"`
+	UserId  string `xml:"GetCallerIdentityResult>UserId"`
+	Account string `xml:"GetCallerIdentityResult>Account"`
 }
 
 // ServerAuth returns ServerAuthMethod and bool value(whether exist or not).
 func (c *AwsIamConfig) ServerAuth() (ServerAuthMethod, bool) {
-	if c.ServerAccountId == "" {
+	if c == nil || c.ServerAccountId == "" || c.ServerId == "" {
 		return nil, false
 	}
 	return ServerAuthMethod(c.unaryServerInterceptor()), true
@@ -36,7 +80,16 @@ func (c *AwsIamConfig) ServerAuth() (ServerAuthMethod, bool) {
 
 // ClientAuth is returns ClientAuthMethod for AWS IAM.
 func (c *AwsIamConfig) ClientAuth() (ClientAuthMethod, bool) {
-	if c.ClientAccessKey == "" || c.ClientSecretAccessKey == "" {
+	return clientAuth(c)
+}
+
+// RefreshingClientAuth is returns RefreshingClientAuthMethod for AWS IAM.
+func (c *AwsIamConfig) RefreshingClientAuth() (RefreshingClientAuthMethod, bool) {
+	if c == nil || c.ClientServerId == "" {
+		return nil, false
+	}
+	// access key and secret access key must be set together.
+	if (c.ClientAccessKey == "") != (c.ClientSecretAccessKey == "") {
 		return nil, false
 	}
 	return c.clientAuthMethod(), true
@@ -53,51 +106,280 @@ func (c *AwsIamConfig) unaryServerInterceptor() grpc.UnaryServerInterceptor {
 			return handler(ctx, req)
 		}
 
-		accountId := c.ServerAccountId
-		allowUsers := c.ServerAllowUsers
-
-		sess, err := session.NewSession(&aws.Config{
-			Credentials: credentials.NewStaticCredentials(auth.AwsIam.AccessKey, auth.AwsIam.SecretAccessKey, ""),
-		})
+		identity, err := c.getCallerIdentity(ctx, auth.AwsIam)
 		if err != nil {
 			return nil, internal.ErrorUnauthorized
 		}
 
-		identity, err := sts.New(sess).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+		principal, err := parseAwsArn(identity.Arn)
 		if err != nil {
 			return nil, internal.ErrorUnauthorized
 		}
 
 		// must be equal to account
-		if accountId != *identity.Account {
+		if c.ServerAccountId != identity.Account || c.ServerAccountId != principal.account {
+			return nil, internal.ErrorUnauthorized
+		}
+		if !c.isAllowedPrincipal(principal) {
 			return nil, internal.ErrorUnauthorized
 		}
 
-		args := strings.Split(*identity.Arn, "/")
-		user := args[len(args)-1]
-		if len(allowUsers) != 0 {
-			if !internal.IsMatchedStringFromSlice(user, allowUsers) {
-				return nil, internal.ErrorUnauthorized
+		// inject user, matched principal, role and groups of role
+		var roles []string
+		if principal.role != "" {
+			roles = []string{principal.role}
+		}
+		newCtx := injectIdentity(ctx, principal.user(), principal.arn, c.roleGroups(principal), roles,
+			map[string]string{"account_id": principal.account})
+		return handler(newCtx, req)
+	}
+}
+
+// isAllowedPrincipal checks whether principal is matched with one of allow rules.
+// if there are no allow rules, all principals of account are allowed.
+func (c *AwsIamConfig) isAllowedPrincipal(p *awsPrincipal) bool {
+	if len(c.ServerAllowUsers) == 0 && len(c.ServerAllowRoles) == 0 &&
+		len(c.ServerAllowPaths) == 0 && len(c.ServerAllowArns) == 0 {
+		return true
+	}
+
+	switch p.kind {
+	case "user":
+		if internal.IsMatchedStringFromSlice(p.name, c.ServerAllowUsers) {
+			return true
+		}
+		for _, prefix := range c.ServerAllowPaths {
+			// empty prefix is ignored, because it matches every path.
+			if prefix != "" && strings.HasPrefix(p.path, prefix) {
+				return true
 			}
 		}
+	case "assumed-role":
+		if internal.IsMatchedStringFromSlice(p.role, c.ServerAllowRoles) {
+			return true
+		}
+	}
 
-		// inject user
-		newCtx := context.WithValue(ctx, UserCtxName, user)
-		return handler(newCtx, req)
+	for _, pattern := range c.ServerAllowArns {
+		if pattern != "" && matchArnPattern(pattern, p.arn) {
+			return true
+		}
+	}
+	return false
+}
+
+// roleGroups returns sorted vpn groups which role of principal is mapped to.
+func (c *AwsIamConfig) roleGroups(p *awsPrincipal) []string {
+	if p.kind != "assumed-role" {
+		return nil
+	}
+	return mappedGroups(c.ServerRoleGroups, func(role string) bool { return role == p.role })
+}
+
+// user returns vpn user of principal.
+// user of assumed role is role/session, because session name is chosen by whoever assumes the role.
+func (p *awsPrincipal) user() string {
+	if p.kind == "assumed-role" {
+		return p.role + "/" + p.session
+	}
+	return p.name
+}
+
+// parseAwsArn parses arn of sts:GetCallerIdentity.
+// (e.g. arn:aws:iam::123456789012:user/path/name, arn:aws:sts::123456789012:assumed-role/role/session)
+func parseAwsArn(arn string) (*awsPrincipal, error) {
+	args := strings.SplitN(arn, ":", 6)
+	if len(args) != 6 || args[0] != "arn" || args[4] == "" {
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: parseAwsArn")
+	}
+
+	p := &awsPrincipal{arn: arn, account: args[4]}
+	resource := strings.Split(args[5], "/")
+	p.kind = resource[0]
+	switch {
+	case p.kind == "root" && len(resource) == 1:
+		p.name = "root"
+	case p.kind == "user" && len(resource) >= 2:
+		p.path = "/" + strings.Join(resource[1:len(resource)-1], "/")
+		if len(resource) > 2 {
+			p.path += "/"
+		}
+		p.name = resource[len(resource)-1]
+	case p.kind == "assumed-role" && len(resource) == 3 && resource[1] != "" && resource[2] != "":
+		p.role = resource[1]
+		p.session = resource[2]
+	case p.kind == "federated-user" && len(resource) == 2:
+		p.name = resource[1]
+	default:
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: parseAwsArn")
+	}
+	if p.user() == "" {
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: parseAwsArn")
 	}
+	return p, nil
 }
 
-func (c *AwsIamConfig) clientAuthMethod() ClientAuthMethod {
-	return func(conn protocol.VPNClient) (jwt string, err error) {
+// matchArnPattern checks whether arn is matched with pattern, '*' matches any characters.
+func matchArnPattern(pattern, arn string) bool {
+	expr := "^" + strings.Replace(regexp.QuoteMeta(pattern), `\*`, ".*", -1) + "$"
+	matched, err := regexp.MatchString(expr, arn)
+	return err == nil && matched
+}
+
+// getCallerIdentity forwards signed sts:GetCallerIdentity request to sts endpoint, and returns identity.
+// only the request to server's sts endpoint is forwarded, because vpn server must not send requests to any url.
+func (c *AwsIamConfig) getCallerIdentity(ctx context.Context, signed *protocol.AuthRequest_AwsIam) (*stsIdentity, error) {
+	if signed == nil || signed.Method != http.MethodPost {
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: getCallerIdentity")
+	}
+
+	endpoint := c.ServerStsEndpoint
+	if endpoint == "" {
+		endpoint = defaultAwsStsEndpoint
+	}
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: getCallerIdentity")
+	}
+
+	// signature includes host, so signed url must be for server's sts endpoint.
+	signedURL, err := url.Parse(signed.Url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: getCallerIdentity")
+	}
+	if signedURL.Host != endpointURL.Host || signedURL.Scheme != endpointURL.Scheme {
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: getCallerIdentity")
+	}
+
+	// body must be only sts:GetCallerIdentity action.
+	values, err := url.ParseQuery(signed.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: getCallerIdentity")
+	}
+	for k := range values {
+		if k != "Action" && k != "Version" {
+			return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: getCallerIdentity")
+		}
+	}
+	if values.Get("Action") != "GetCallerIdentity" {
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: getCallerIdentity")
+	}
+
+	stsReq, err := http.NewRequest(http.MethodPost, endpointURL.String(), strings.NewReader(signed.Body))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: getCallerIdentity")
+	}
+	for k, v := range signed.Headers {
+		stsReq.Header.Set(k, v)
+	}
+	if !c.isSignedForServer(stsReq.Header) {
+		return nil, errors.Wrapf(internal.ErrorUnauthorized, "Method: getCallerIdentity")
+	}
+
+	stsCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	stsResp, err := http.DefaultClient.Do(stsReq.WithContext(stsCtx))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: getCallerIdentity")
+	}
+	defer stsResp.Body.Close()
+
+	buf, err := ioutil.ReadAll(stsResp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: getCallerIdentity")
+	}
+	if stsResp.StatusCode != http.StatusOK {
+		return nil, errors.Wrapf(internal.ErrorUnauthorized, "Method: getCallerIdentity")
+	}
+
+	identity := &stsIdentity{}
+	if err := xml.Unmarshal(buf, identity); err != nil {
+		return nil, errors.Wrapf(err, "Method: getCallerIdentity")
+	}
+	if identity.Arn == "" || identity.Account == "" {
+		return nil, errors.Wrapf(internal.ErrorUnauthorized, "Method: getCallerIdentity")
+	}
+	return identity, nil
+}
+
+// isSignedForServer checks whether signed request is for this server.
+// server id header must be equal to server id and must be included in SignedHeaders of signature.
+func (c *AwsIamConfig) isSignedForServer(header http.Header) bool {
+	if c.ServerId == "" || header.Get(awsServerIdHeader) != c.ServerId {
+		return false
+	}
+
+	// Authorization: AWS4-HMAC-SHA256 Credential=..., SignedHeaders=host;x-amz-date;..., Signature=...
+	for _, part := range strings.Split(header.Get("Authorization"), ",") {
+		part = strings.TrimSpace(part)
+		if !strings.HasPrefix(part, "SignedHeaders=") {
+			continue
+		}
+		for _, name := range strings.Split(strings.TrimPrefix(part, "SignedHeaders="), ";") {
+			if name == strings.ToLower(awsServerIdHeader) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// signGetCallerIdentity signs sts:GetCallerIdentity request with client credentials.
+func (c *AwsIamConfig) signGetCallerIdentity() (*protocol.AuthRequest_AwsIam, error) {
+	endpoint := c.ClientStsEndpoint
+	if endpoint == "" {
+		endpoint = defaultAwsStsEndpoint
+	}
+	region := c.ClientStsRegion
+	if region == "" {
+		region = defaultAwsStsRegion
+	}
+
+	cfg := aws.Config{Endpoint: aws.String(endpoint), Region: aws.String(region)}
+	if c.ClientAccessKey != "" {
+		cfg.Credentials = credentials.NewStaticCredentials(c.ClientAccessKey, c.ClientSecretAccessKey, c.ClientSessionToken)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            cfg,
+		Profile:           c.ClientProfile,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: signGetCallerIdentity")
+	}
+
+	stsReq, _ := sts.New(sess).GetCallerIdentityRequest(&sts.GetCallerIdentityInput{})
+	stsReq.HTTPRequest.Header.Set(awsServerIdHeader, c.ClientServerId)
+	if err := stsReq.Sign(); err != nil {
+		return nil, errors.Wrapf(err, "Method: signGetCallerIdentity")
+	}
+	body, err := ioutil.ReadAll(stsReq.HTTPRequest.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: signGetCallerIdentity")
+	}
+
+	headers := map[string]string{}
+	for k, v := range stsReq.HTTPRequest.Header {
+		headers[k] = strings.Join(v, ",")
+	}
+	return &protocol.AuthRequest_AwsIam{
+		Method:  stsReq.HTTPRequest.Method,
+		Url:     stsReq.HTTPRequest.URL.String(),
+		Headers: headers,
+		Body:    string(body),
+	}, nil
+}
+
+func (c *AwsIamConfig) clientAuthMethod() RefreshingClientAuthMethod {
+	return func(conn protocol.VPNClient) (jwt string, refreshToken string, err error) {
 		if conn == nil {
-			return "", errors.Wrapf(internal.ErrorInvalidParams, "AWS IAM  ClientAuthMethod")
+			return "", "", errors.Wrapf(internal.ErrorInvalidParams, "AWS IAM  ClientAuthMethod")
 		}
 
-		// extract information
-		accessKey := c.ClientAccessKey
-		secretAccessKey := c.ClientSecretAccessKey
-		if accessKey == "" || secretAccessKey == "" {
-			return "", errors.Wrapf(internal.ErrorInvalidParams, "AWS IAM  ClientAuthMethod")
+		// sign request locally, credentials are never sent.
+		signed, err := c.signGetCallerIdentity()
+		if err != nil {
+			return "", "", errors.Wrapf(err, "AWS IAM  ClientAuthMethod")
 		}
 
 		// call authentication request to VPN server.
@@ -105,25 +387,23 @@ func (c *AwsIamConfig) clientAuthMethod() ClientAuthMethod {
 		defer authCancel()
 		response, err := conn.Auth(authCtx, &protocol.AuthRequest{
 			AuthType: protocol.AuthType_AT_AWS_IAM,
-			AwsIam: &protocol.AuthRequest_AwsIam{
-				AccessKey:       accessKey,
-				SecretAccessKey: secretAccessKey,
-			},
+			AwsIam:   signed,
 		})
 		if err != nil {
-			return "", errors.Wrapf(internal.ErrorInvalidParams, "AWS IAM  ClientAuthMethod")
+			return "", "", errors.Wrapf(internal.ErrorInvalidParams, "AWS IAM  ClientAuthMethod")
 		}
 		if response.ErrorCode != protocol.ErrorCode_EC_SUCCESS || response.Jwt == "" {
-			return "", errors.Wrapf(internal.ErrorInvalidParams, "AWS IAM  ClientAuthMethod")
+			return "", "", errors.Wrapf(internal.ErrorInvalidParams, "AWS IAM  ClientAuthMethod")
 		}
 
-		return response.Jwt, nil
+		return response.Jwt, response.RefreshToken, nil
 	}
 }
 
 // NewServerManagerForAwsIAM returns ServerManager implementing awsIam.
-func NewServerManagerForAwsIAM(accountId string, allowUsers []string) (ServerManager, error) {
-	if accountId == "" {
+// serverId must be same with server id of clients.
+func NewServerManagerForAwsIAM(accountId, serverId string, allowUsers []string) (ServerManager, error) {
+	if accountId == "" || serverId == "" {
 		return nil, internal.ErrorInvalidParams
 	}
 
@@ -133,18 +413,21 @@ func NewServerManagerForAwsIAM(accountId string, allowUsers []string) (ServerMan
 
 	return &AwsIamConfig{
 		ServerAccountId:  accountId,
+		ServerId:         serverId,
 		ServerAllowUsers: allowUsers,
 	}, nil
 }
 
 // NewClientManagerForAwsIAM returns ClientManager implementing awsIam.
-func NewClientManagerForAwsIAM(accessKey, accessSecret string) (ClientManager, error) {
-	if accessKey == "" || accessSecret == "" {
+// if accessKey and accessSecret are empty, default credential chain is used.
+func NewClientManagerForAwsIAM(accessKey, accessSecret, serverId string) (ClientManager, error) {
+	if (accessKey == "") != (accessSecret == "") || serverId == "" {
 		return nil, internal.ErrorInvalidParams
 	}
 
 	return &AwsIamConfig{
 		ClientAccessKey:       accessKey,
 		ClientSecretAccessKey: accessSecret,
+		ClientServerId:        serverId,
 	}, nil
 }
@@ -1,9 +1,17 @@
 package auth
 
 import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
 )
 
 func TestNewServerManagerForAwsIAM(t *testing.T) {
@@ -11,21 +19,25 @@ func TestNewServerManagerForAwsIAM(t *testing.T) {
 
 	tests := map[string]struct {
 		accountId  string
+		serverId   string
 		allowUsers []string
 		isErr      bool
 	}{
-		"fail": {isErr: true},
+		"fail":           {isErr: true},
+		"empty-serverid": {accountId: "testid", isErr: true},
 		"success": {
 			accountId:  "testid",
+			serverId:   "vpn.example.com",
 			allowUsers: []string{"gjbae1212", "test"},
 		},
 	}
 
 	for _, t := range tests {
-		s, err := NewServerManagerForAwsIAM(t.accountId, t.allowUsers)
+		s, err := NewServerManagerForAwsIAM(t.accountId, t.serverId, t.allowUsers)
 		assert.Equal(t.isErr, err != nil)
 		if err == nil {
 			assert.Equal(t.accountId, s.(*AwsIamConfig).ServerAccountId)
+			assert.Equal(t.serverId, s.(*AwsIamConfig).ServerId)
 			assert.Equal(t.allowUsers, s.(*AwsIamConfig).ServerAllowUsers)
 		}
 	}
@@ -38,21 +50,26 @@ func TestNewClientManagerForAwsIAM(t *testing.T) {
 	tests := map[string]struct {
 		accessKey    string
 		accessSecret string
+		serverId     string
 		isErr        bool
 	}{
-		"fail": {isErr: true},
+		"fail":           {accessKey: "test-key", serverId: "vpn.example.com", isErr: true},
+		"empty-serverid": {accessKey: "test-key", accessSecret: "test-secret", isErr: true},
+		"default-chain":  {serverId: "vpn.example.com"},
 		"success": {
 			accessKey:    "test-key",
 			accessSecret: "test-secret",
+			serverId:     "vpn.example.com",
 		},
 	}
 
 	for _, t := range tests {
-		s, err := NewClientManagerForAwsIAM(t.accessKey, t.accessSecret)
+		s, err := NewClientManagerForAwsIAM(t.accessKey, t.accessSecret, t.serverId)
 		assert.Equal(t.isErr, err != nil)
 		if err == nil {
 			assert.Equal(t.accessKey, s.(*AwsIamConfig).ClientAccessKey)
 			assert.Equal(t.accessSecret, s.(*AwsIamConfig).ClientSecretAccessKey)
+			assert.Equal(t.serverId, s.(*AwsIamConfig).ClientServerId)
 		}
 	}
 }
@@ -71,7 +88,7 @@ func TestAwsIamConfig_ServerAuth(t *testing.T) {
 	}
 
 	for _, t := range tests {
-		s, err := NewServerManagerForAwsIAM(t.accountId, nil)
+		s, err := NewServerManagerForAwsIAM(t.accountId, "vpn.example.com", nil)
 		assert.NoError(err)
 		_, ok := s.ServerAuth()
 		assert.Equal(t.ok, ok)
@@ -94,10 +111,176 @@ func TestAwsIamConfig_ClientAuth(t *testing.T) {
 	}
 
 	for _, t := range tests {
-		s, err := NewClientManagerForAwsIAM(t.accessKey, t.accessSecret)
+		s, err := NewClientManagerForAwsIAM(t.accessKey, t.accessSecret, "vpn.example.com")
 		assert.NoError(err)
 		_, ok := s.ClientAuth()
 		assert.Equal(t.ok, ok)
 	}
 
 }
+
+// mockSts is a local stand-in of sts endpoint, it returns identity of access key in signed request.
+func mockSts(identities map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method != http.MethodPost || !strings.Contains(string(body), "Action=GetCallerIdentity") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for accessKey, arn := range identities {
+			if strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential="+accessKey+"/") {
+				fmt.Fprintf(w, `<GetCallerIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+<GetCallerIdentityResult><Arn>%s</Arn><UserId>AIDA</UserId><Account>%s</Account></GetCallerIdentityResult>
+</GetCallerIdentityResponse>`, arn, strings.Split(arn, ":")[4])
+				return
+			}
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+}
+
+func TestAwsIamConfig_UnaryServerInterceptor(t *testing.T) {
+	assert := assert.New(t)
+
+	sts := mockSts(map[string]string{
+		"allan-key":     "arn:aws:iam::123456789012:user/allan",
+		"other-key":     "arn:aws:iam::210987654321:user/allan",
+		"guest-key":     "arn:aws:iam::123456789012:user/guest",
+		"developer-key": "arn:aws:sts::123456789012:assumed-role/developer/allan",
+	})
+	defer sts.Close()
+
+	server := &AwsIamConfig{ServerAccountId: "123456789012", ServerAllowUsers: []string{"allan"},
+		ServerAllowRoles: []string{"developer"}, ServerRoleGroups: map[string][]string{"dev": {"developer"}},
+		ServerId: "vpn.example.com", ServerStsEndpoint: sts.URL}
+	interceptor := server.unaryServerInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return []interface{}{ctx.Value(UserCtxName), ctx.Value(PrincipalCtxName), ctx.Value(GroupsCtxName),
+			ctx.Value(RolesCtxName), ctx.Value(AttributesCtxName)}, nil
+	}
+
+	tests := map[string]struct {
+		client *AwsIamConfig
+		tamper func(signed *protocol.AuthRequest_AwsIam)
+		output interface{}
+		isErr  bool
+	}{
+		"other-endpoint": {client: &AwsIamConfig{ClientAccessKey: "allan-key", ClientSecretAccessKey: "secret", ClientServerId: "vpn.example.com"}, isErr: true},
+		"other-server":   {client: &AwsIamConfig{ClientAccessKey: "allan-key", ClientSecretAccessKey: "secret", ClientStsEndpoint: sts.URL, ClientServerId: "other.example.com"}, isErr: true},
+		"unsigned-server": {
+			client: &AwsIamConfig{ClientAccessKey: "allan-key", ClientSecretAccessKey: "secret", ClientStsEndpoint: sts.URL, ClientServerId: "vpn.example.com"},
+			tamper: func(signed *protocol.AuthRequest_AwsIam) {
+				signed.Headers["Authorization"] = strings.Replace(signed.Headers["Authorization"], ";x-grpc-vpn-server-id", "", 1)
+			},
+			isErr: true,
+		},
+		"other-action": {
+			client: &AwsIamConfig{ClientAccessKey: "allan-key", ClientSecretAccessKey: "secret", ClientStsEndpoint: sts.URL, ClientServerId: "vpn.example.com"},
+			tamper: func(signed *protocol.AuthRequest_AwsIam) { signed.Body = "Action=AssumeRole&Version=2011-06-15" },
+			isErr:  true,
+		},
+		"unknown-key":   {client: &AwsIamConfig{ClientAccessKey: "unknown-key", ClientSecretAccessKey: "secret", ClientStsEndpoint: sts.URL, ClientServerId: "vpn.example.com"}, isErr: true},
+		"other-account": {client: &AwsIamConfig{ClientAccessKey: "other-key", ClientSecretAccessKey: "secret", ClientStsEndpoint: sts.URL, ClientServerId: "vpn.example.com"}, isErr: true},
+		"not-allowed":   {client: &AwsIamConfig{ClientAccessKey: "guest-key", ClientSecretAccessKey: "secret", ClientStsEndpoint: sts.URL, ClientServerId: "vpn.example.com"}, isErr: true},
+		"success": {
+			client: &AwsIamConfig{ClientAccessKey: "allan-key", ClientSecretAccessKey: "secret", ClientSessionToken: "token", ClientStsEndpoint: sts.URL, ClientServerId: "vpn.example.com"},
+			output: []interface{}{"allan", "arn:aws:iam::123456789012:user/allan", nil, nil,
+				map[string]string{"account_id": "123456789012"}},
+		},
+		"success-role": {
+			client: &AwsIamConfig{ClientAccessKey: "developer-key", ClientSecretAccessKey: "secret", ClientStsEndpoint: sts.URL, ClientServerId: "vpn.example.com"},
+			output: []interface{}{"developer/allan", "arn:aws:sts::123456789012:assumed-role/developer/allan", []string{"dev"},
+				[]string{"developer"}, map[string]string{"account_id": "123456789012"}},
+		},
+	}
+
+	for _, t := range tests {
+		signed, err := t.client.signGetCallerIdentity()
+		assert.NoError(err)
+		assert.NotContains(fmt.Sprintf("%v", signed), "secret")
+		if t.tamper != nil {
+			t.tamper(signed)
+		}
+
+		output, err := interceptor(context.Background(), &protocol.AuthRequest{AuthType: protocol.AuthType_AT_AWS_IAM, AwsIam: signed},
+			&grpc.UnaryServerInfo{}, handler)
+		assert.Equal(t.isErr, err != nil)
+		assert.Equal(t.output, output)
+	}
+}
+
+func TestParseAwsArn(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input  string
+		output *awsPrincipal
+		user   string
+		isErr  bool
+	}{
+		"invalid":       {input: "allan", isErr: true},
+		"empty-session": {input: "arn:aws:sts::123456789012:assumed-role/developer/", isErr: true},
+		"unknown":       {input: "arn:aws:iam::123456789012:group/developers", isErr: true},
+		"user": {
+			input:  "arn:aws:iam::123456789012:user/allan",
+			output: &awsPrincipal{arn: "arn:aws:iam::123456789012:user/allan", account: "123456789012", kind: "user", path: "/", name: "allan"},
+			user:   "allan",
+		},
+		"user-path": {
+			input: "arn:aws:iam::123456789012:user/engineering/backend/allan",
+			output: &awsPrincipal{arn: "arn:aws:iam::123456789012:user/engineering/backend/allan", account: "123456789012",
+				kind: "user", path: "/engineering/backend/", name: "allan"},
+			user: "allan",
+		},
+		"assumed-role": {
+			input: "arn:aws:sts::123456789012:assumed-role/developer/allan@example.com",
+			output: &awsPrincipal{arn: "arn:aws:sts::123456789012:assumed-role/developer/allan@example.com", account: "123456789012",
+				kind: "assumed-role", role: "developer", session: "allan@example.com"},
+			user: "developer/allan@example.com",
+		},
+		"root": {
+			input:  "arn:aws:iam::123456789012:root",
+			output: &awsPrincipal{arn: "arn:aws:iam::123456789012:root", account: "123456789012", kind: "root", name: "root"},
+			user:   "root",
+		},
+	}
+
+	for _, t := range tests {
+		p, err := parseAwsArn(t.input)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Equal(t.output, p)
+			assert.Equal(t.user, p.user())
+		}
+	}
+}
+
+func TestAwsIamConfig_IsAllowedPrincipal(t *testing.T) {
+	assert := assert.New(t)
+
+	user, _ := parseAwsArn("arn:aws:iam::123456789012:user/engineering/allan")
+	role, _ := parseAwsArn("arn:aws:sts::123456789012:assumed-role/developer/allan")
+	admin, _ := parseAwsArn("arn:aws:sts::123456789012:assumed-role/admin/allan")
+
+	tests := map[string]struct {
+		config *AwsIamConfig
+		input  *awsPrincipal
+		output bool
+	}{
+		"no-rules":         {config: &AwsIamConfig{}, input: role, output: true},
+		"user":             {config: &AwsIamConfig{ServerAllowUsers: []string{"allan"}}, input: user, output: true},
+		"user-not-session": {config: &AwsIamConfig{ServerAllowUsers: []string{"allan"}}, input: role},
+		"role":             {config: &AwsIamConfig{ServerAllowRoles: []string{"developer"}}, input: role, output: true},
+		"other-role":       {config: &AwsIamConfig{ServerAllowRoles: []string{"developer"}}, input: admin},
+		"path":             {config: &AwsIamConfig{ServerAllowPaths: []string{"/engineering/"}}, input: user, output: true},
+		"other-path":       {config: &AwsIamConfig{ServerAllowPaths: []string{"/sales/"}}, input: user},
+		"empty-path":       {config: &AwsIamConfig{ServerAllowPaths: []string{""}}, input: user},
+		"arn":              {config: &AwsIamConfig{ServerAllowArns: []string{"arn:aws:sts::123456789012:assumed-role/dev*/*"}}, input: role, output: true},
+		"other-arn":        {config: &AwsIamConfig{ServerAllowArns: []string{"arn:aws:sts::123456789012:assumed-role/dev*/*"}}, input: admin},
+		"empty-arn":        {config: &AwsIamConfig{ServerAllowArns: []string{""}}, input: admin},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.output, t.config.isAllowedPrincipal(t.input))
+	}
+}
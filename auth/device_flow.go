@@ -0,0 +1,185 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-oidc"
+	"github.com/fatih/color"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+// RFC 8628 OAuth 2.0 Device Authorization Grant
+const (
+	deviceCodeGrantType       = "urn:ietf:params:oauth:grant-type:device_code"
+	defaultDevicePollInterval = 5 * time.Second
+	defaultDeviceExpiration   = 10 * time.Minute
+)
+
+// deviceAuthorization is a response of device authorization endpoint.
+type deviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURL         string `json:"verification_url"` // google uses verification_url instead of verification_uri.
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+// deviceToken is a response of token endpoint for device code.
+type deviceToken struct {
+	IdToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// deviceAuthorizationEndpoint returns device authorization endpoint in discovery document of provider.
+func deviceAuthorizationEndpoint(provider *oidc.Provider) (string, error) {
+	var claims struct {
+		DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	}
+	if err := provider.Claims(&claims); err != nil {
+		return "", errors.Wrapf(err, "Method: deviceAuthorizationEndpoint")
+	}
+	if claims.DeviceAuthorizationEndpoint == "" {
+		return "", errors.Wrapf(internal.ErrorInvalidParams, "Method: deviceAuthorizationEndpoint")
+	}
+	return claims.DeviceAuthorizationEndpoint, nil
+}
+
+// receiveDeviceIDToken prints verification url and user code, and polls token endpoint until user authorizes it.
+// it's for headless clients which can't open browser or listen redirect url.
+func receiveDeviceIDToken(ctx context.Context, endpoint string, conf oauth2.Config, name string) (string, error) {
+	auth, err := requestDeviceAuthorization(ctx, endpoint, conf)
+	if err != nil {
+		return "", errors.Wrapf(err, "Method: receiveDeviceIDToken")
+	}
+
+	verificationURI := auth.VerificationURI
+	if verificationURI == "" {
+		verificationURI = auth.VerificationURL
+	}
+	fmt.Println(color.GreenString("[WAIT] YOUR %s AUTHENTICATION, OPEN %s AND ENTER CODE %s",
+		name, verificationURI, auth.UserCode))
+	if auth.VerificationURIComplete != "" {
+		fmt.Println(color.GreenString("[WAIT] OR OPEN %s", auth.VerificationURIComplete))
+	}
+
+	idToken, err := pollDeviceToken(ctx, conf, auth)
+	if err != nil {
+		return "", errors.Wrapf(err, "Method: receiveDeviceIDToken")
+	}
+	return idToken, nil
+}
+
+// requestDeviceAuthorization requests device code and user code.
+func requestDeviceAuthorization(ctx context.Context, endpoint string, conf oauth2.Config) (*deviceAuthorization, error) {
+	values := url.Values{
+		"client_id": {conf.ClientID},
+		"scope":     {strings.Join(conf.Scopes, " ")},
+	}
+	if conf.ClientSecret != "" {
+		values.Set("client_secret", conf.ClientSecret)
+	}
+
+	buf, status, err := postForm(ctx, endpoint, values)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: requestDeviceAuthorization")
+	}
+	if status != http.StatusOK {
+		return nil, errors.Wrapf(internal.ErrorUnauthorized, "Method: requestDeviceAuthorization")
+	}
+
+	auth := &deviceAuthorization{}
+	if err := json.Unmarshal(buf, auth); err != nil {
+		return nil, errors.Wrapf(err, "Method: requestDeviceAuthorization")
+	}
+	if auth.DeviceCode == "" || auth.UserCode == "" {
+		return nil, errors.Wrapf(internal.ErrorUnauthorized, "Method: requestDeviceAuthorization")
+	}
+	return auth, nil
+}
+
+// pollDeviceToken polls token endpoint with device code, and returns id token.
+func pollDeviceToken(ctx context.Context, conf oauth2.Config, auth *deviceAuthorization) (string, error) {
+	interval := time.Duration(auth.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultDevicePollInterval
+	}
+	expiration := time.Duration(auth.ExpiresIn) * time.Second
+	if expiration <= 0 {
+		expiration = defaultDeviceExpiration
+	}
+	pollCtx, cancel := context.WithTimeout(ctx, expiration)
+	defer cancel()
+
+	values := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {auth.DeviceCode},
+		"client_id":   {conf.ClientID},
+	}
+	if conf.ClientSecret != "" {
+		values.Set("client_secret", conf.ClientSecret)
+	}
+
+	for {
+		select {
+		case <-pollCtx.Done():
+			return "", errors.Wrapf(internal.ErrorUnauthorized, "Method: pollDeviceToken")
+		case <-time.After(interval):
+		}
+
+		buf, _, err := postForm(pollCtx, conf.Endpoint.TokenURL, values)
+		if err != nil {
+			return "", errors.Wrapf(err, "Method: pollDeviceToken")
+		}
+
+		token := &deviceToken{}
+		if err := json.Unmarshal(buf, token); err != nil {
+			return "", errors.Wrapf(err, "Method: pollDeviceToken")
+		}
+
+		switch token.Error {
+		case "":
+			if token.IdToken == "" {
+				return "", errors.Wrapf(internal.ErrorUnauthorized, "Method: pollDeviceToken")
+			}
+			return token.IdToken, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		default: // access_denied, expired_token
+			return "", errors.Wrapf(internal.ErrorUnauthorized, "Method: pollDeviceToken %s", token.Error)
+		}
+	}
+}
+
+// postForm posts form values, and returns body and status code.
+func postForm(ctx context.Context, endpoint string, values url.Values) ([]byte, int, error) {
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return buf, resp.StatusCode, nil
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coreos/go-oidc"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceiveDeviceIDToken(t *testing.T) {
+	assert := assert.New(t)
+
+	issuer := newMockOpenIDIssuer(t, "client-id")
+	defer issuer.Close()
+	issuer.claims["allan"] = jwt.MapClaims{"email": "allan@example.com"}
+
+	provider, err := oidc.NewProvider(context.Background(), issuer.URL)
+	assert.NoError(err)
+	endpoint, err := deviceAuthorizationEndpoint(provider)
+	assert.NoError(err)
+	assert.Equal(issuer.URL+"/device", endpoint)
+
+	tests := map[string]struct {
+		cfg   *OpenIDConfig
+		user  string
+		isErr bool
+	}{
+		"invalid-client": {cfg: &OpenIDConfig{ClientId: "other-client", Scopes: []string{"allan"}}, isErr: true},
+		"access-denied":  {cfg: &OpenIDConfig{ClientId: "client-id", Scopes: []string{"bob"}}, isErr: true},
+		"success":        {cfg: &OpenIDConfig{ClientId: "client-id", Scopes: []string{"allan"}}, user: "allan@example.com"},
+	}
+
+	for _, t := range tests {
+		idToken, err := receiveDeviceIDToken(context.Background(), endpoint, t.cfg.oauth2Config(provider), "TEST")
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			claims, err := verifyOpenIDToken(context.Background(), provider, idToken, t.cfg.ClientId)
+			assert.NoError(err)
+			assert.Equal(t.user, claims["email"])
+		}
+	}
+}
@@ -2,28 +2,19 @@ package auth
 
 import (
 	"context"
-	"fmt"
-	"net/http"
 	"time"
 
-	"github.com/fatih/color"
-
 	"github.com/coreos/go-oidc"
 	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
 	"github.com/gjbae1212/grpc-vpn/internal"
-	"github.com/pkg/browser"
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
 	"google.golang.org/grpc"
 )
 
-// Only Support Redirect URL http://localhost:10000/code
 const (
-	googleOpenIDProvider = "https://accounts.google.com"
-	redirectServer       = "http://localhost:10000"
-	redirectPath         = "/code"
-	redirectPort         = "10000"
-	redirectURL          = "http://localhost:10000/code"
+	googleOpenIDProvider              = "https://accounts.google.com"
+	googleDeviceAuthorizationEndpoint = "https://oauth2.googleapis.com/device/code"
 )
 
 // https://developers.google.com/identity/protocols/oauth2/native-app
@@ -31,9 +22,13 @@ const (
 type GoogleOpenIDConfig struct {
 	ClientId     string // google client id
 	ClientSecret string // google secret
+	DeviceFlow   bool   // use device authorization grant instead of browser for headless client (only vpn-client)
+	RedirectPort string // port of loopback redirect url, if empty, a free port is used (only vpn-client)
 
 	HD          string   // gsuite domain (only vpn-server)
 	AllowEmails []string // allow emails (only vpn-server)
+
+	DeviceClientId string // client id of "TVs and Limited Input devices" type for device flow (only vpn-server)
 }
 
 // ServerAuth returns ServerAuthMethod and bool value(whether exist or not).
@@ -44,8 +39,13 @@ func (c *GoogleOpenIDConfig) ServerAuth() (ServerAuthMethod, bool) {
 	return ServerAuthMethod(c.unaryServerInterceptor()), true
 }
 
-// ClientAuth is returns  ClientAuthMethod for Google Open ID.
+// ClientAuth is returns ClientAuthMethod for Google Open ID.
 func (c *GoogleOpenIDConfig) ClientAuth() (ClientAuthMethod, bool) {
+	return clientAuth(c)
+}
+
+// RefreshingClientAuth is returns RefreshingClientAuthMethod for Google Open ID.
+func (c *GoogleOpenIDConfig) RefreshingClientAuth() (RefreshingClientAuthMethod, bool) {
 	if c.ClientId == "" || c.ClientSecret == "" {
 		return nil, false
 	}
@@ -63,7 +63,11 @@ func (c *GoogleOpenIDConfig) unaryServerInterceptor() grpc.UnaryServerIntercepto
 			return handler(ctx, req)
 		}
 
-		if auth.GoogleOpenId.Code == "" {
+		if auth.GoogleOpenId == nil || (auth.GoogleOpenId.Code == "" && auth.GoogleOpenId.IdToken == "") {
+			return nil, internal.ErrorUnauthorized
+		}
+		// id token is received only by device flow, so it isn't accepted unless device flow is configured.
+		if auth.GoogleOpenId.IdToken != "" && c.DeviceClientId == "" {
 			return nil, internal.ErrorUnauthorized
 		}
 
@@ -80,39 +84,22 @@ func (c *GoogleOpenIDConfig) unaryServerInterceptor() grpc.UnaryServerIntercepto
 		oauthConf := oauth2.Config{
 			ClientID:     clientID,
 			ClientSecret: clientSecret,
-			RedirectURL:  redirectURL,
 			Endpoint:     provider.Endpoint(),
 			Scopes:       []string{oidc.ScopeOpenID, "profile", "email"},
 		}
 
-		exCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-		defer cancel()
-
-		token, err := oauthConf.Exchange(exCtx, auth.GoogleOpenId.Code)
-		if err != nil {
-			return nil, internal.ErrorUnauthorized
-		}
-
-		expiresIn := token.Extra("expires_in").(float64)
-		if expiresIn <= 10 { // Fail when remaining lifetime is less than 10 seconds.
-			return nil, internal.ErrorUnauthorized
-		}
-
-		rawIdToken, ok := token.Extra("id_token").(string)
-		if !ok {
-			return nil, internal.ErrorUnauthorized
+		// id token received by device flow must be issued to client for device flow.
+		var claims map[string]interface{}
+		if auth.GoogleOpenId.IdToken != "" {
+			claims, err = verifyOpenIDToken(ctx, provider, auth.GoogleOpenId.IdToken, c.DeviceClientId)
+		} else {
+			claims, err = exchangeOpenIDCode(ctx, provider, oauthConf, auth.GoogleOpenId.Code,
+				auth.GoogleOpenId.RedirectUri, auth.GoogleOpenId.CodeVerifier)
 		}
-
-		idToken, err := provider.Verifier(&oidc.Config{ClientID: clientID}).Verify(ctx, rawIdToken)
 		if err != nil {
 			return nil, internal.ErrorUnauthorized
 		}
 
-		claims := map[string]interface{}{}
-		if err := idToken.Claims(&claims); err != nil {
-			return nil, internal.ErrorUnauthorized
-		}
-
 		// check gsuite domain(matched)
 		// if hd is empty, don't check hd
 		if hd != "" {
@@ -129,96 +116,71 @@ func (c *GoogleOpenIDConfig) unaryServerInterceptor() grpc.UnaryServerIntercepto
 			}
 		}
 
-		// inject user
-		newCtx := context.WithValue(ctx, UserCtxName, claims["email"].(string))
+		// inject user and gsuite domain
+		var attributes map[string]string
+		if domain, ok := claims["hd"].(string); ok && domain != "" {
+			attributes = map[string]string{"hd": domain}
+		}
+		newCtx := injectIdentity(ctx, claims["email"].(string), "", nil, nil, attributes)
 		return handler(newCtx, req)
 	}
 }
 
 // ClientAuthMethod returns auth method for client.
-func (c *GoogleOpenIDConfig) clientAuthMethod() ClientAuthMethod {
-	return func(conn protocol.VPNClient) (jwt string, err error) {
+func (c *GoogleOpenIDConfig) clientAuthMethod() RefreshingClientAuthMethod {
+	return func(conn protocol.VPNClient) (jwt string, refreshToken string, err error) {
 		if conn == nil {
-			return "", errors.Wrapf(internal.ErrorInvalidParams, "Google OpenID ClientAuthMethod")
+			return "", "", errors.Wrapf(internal.ErrorInvalidParams, "Google OpenID ClientAuthMethod")
 		}
 
 		// extract information for oauth2
 		clientID := c.ClientId
 		clientSecret := c.ClientSecret
 		if clientID == "" || clientSecret == "" {
-			return "", errors.Wrapf(internal.ErrorInvalidParams, "Google OpenID ClientAuthMethod")
+			return "", "", errors.Wrapf(internal.ErrorInvalidParams, "Google OpenID ClientAuthMethod")
 		}
 
 		provider, err := oidc.NewProvider(context.Background(), googleOpenIDProvider)
 		if err != nil {
-			return "", errors.Wrapf(err, "Google OpenID ClientAuthMethod")
-		}
-
-		// start http server
-		serverCtx, cancel := context.WithCancel(context.Background())
-		defer cancel()
-		var code string
-		mux := http.NewServeMux()
-		server := http.Server{Addr: fmt.Sprintf(":%s", redirectPort), Handler: mux}
-		mux.HandleFunc(redirectPath, func(w http.ResponseWriter, req *http.Request) {
-			// extract code
-			if req.URL.Query()["code"] != nil && len(req.URL.Query()["code"]) > 0 {
-				code = req.URL.Query()["code"][0]
-				http.Redirect(w, req, redirectServer+"/success", 301)
-			} else {
-				http.Redirect(w, req, redirectServer+"/fail", 301)
-			}
-		})
-		mux.HandleFunc("/success", func(w http.ResponseWriter, req *http.Request) {
-			w.Write([]byte("[REDIRECT] Close this page."))
-			cancel()
-		})
-		mux.HandleFunc("/fail", func(w http.ResponseWriter, req *http.Request) {
-			w.Write([]byte("[REDIRECT] Close this page."))
-			cancel()
-		})
-
-		go func() {
-			server.ListenAndServe()
-		}()
+			return "", "", errors.Wrapf(err, "Google OpenID ClientAuthMethod")
+		}
 
-		state := internal.GenerateRandomString(10)
 		conf := oauth2.Config{
 			ClientID:     clientID,
 			ClientSecret: clientSecret,
-			RedirectURL:  redirectURL,
 			Endpoint:     provider.Endpoint(),
 			Scopes:       []string{oidc.ScopeOpenID, "profile", "email"},
 		}
-		if err := browser.OpenURL(conf.AuthCodeURL(state)); err != nil {
-			return "", errors.Wrapf(err, "Google OpenID ClientAuthMethod")
-		}
-		fmt.Println(color.GreenString("[WAIT] YOUR GOOGLE OPENID AUTHENTICATION"))
-		select {
-		case <-serverCtx.Done():
-			server.Shutdown(serverCtx)
-		}
-		if code == "" {
-			return "", errors.Wrapf(internal.ErrorUnauthorized, "Google OpenID ClientAuthMethod")
+		googleOpenID := &protocol.AuthRequest_GoogleOpenID{}
+		if c.DeviceFlow {
+			googleOpenID.IdToken, err = receiveDeviceIDToken(context.Background(), googleDeviceAuthorizationEndpoint,
+				conf, "GOOGLE OPENID")
+			if err != nil {
+				return "", "", errors.Wrapf(err, "Google OpenID ClientAuthMethod")
+			}
+		} else {
+			result, err := receiveAuthorizationCode(conf, "GOOGLE OPENID", c.RedirectPort)
+			if err != nil {
+				return "", "", errors.Wrapf(err, "Google OpenID ClientAuthMethod")
+			}
+			googleOpenID.Code, googleOpenID.CodeVerifier, googleOpenID.RedirectUri = result.code, result.codeVerifier, result.redirectURL
 		}
 
 		// call authentication request to VPN server.
 		authCtx, authCancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer authCancel()
 		response, err := conn.Auth(authCtx, &protocol.AuthRequest{
-			AuthType: protocol.AuthType_AT_GOOGLE_OPEN_ID,
-			GoogleOpenId: &protocol.AuthRequest_GoogleOpenID{
-				Code: code,
-			},
+			AuthType:     protocol.AuthType_AT_GOOGLE_OPEN_ID,
+			GoogleOpenId: googleOpenID,
 		})
 		if err != nil {
-			return "", errors.Wrapf(internal.ErrorUnauthorized, "Google OpenID ClientAuthMethod")
+			return "", "", errors.Wrapf(internal.ErrorUnauthorized, "Google OpenID ClientAuthMethod")
 		}
 		if response.ErrorCode != protocol.ErrorCode_EC_SUCCESS || response.Jwt == "" {
-			return "", errors.Wrapf(internal.ErrorUnauthorized, "Google OpenID ClientAuthMethod")
+			return "", "", errors.Wrapf(internal.ErrorUnauthorized, "Google OpenID ClientAuthMethod")
 		}
 
-		return response.Jwt, nil
+		return response.Jwt, response.RefreshToken, nil
 	}
 }
 
@@ -1,8 +1,11 @@
 package auth
 
 import (
-	"github.com/stretchr/testify/assert"
+	"context"
 	"testing"
+
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewServerManagerForGoogleOpenID(t *testing.T) {
@@ -70,6 +73,36 @@ func TestGoogleOpenIDConfig_ServerAuth(t *testing.T) {
 	}
 }
 
+func TestGoogleOpenIDConfig_UnaryServerInterceptor(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		cfg *GoogleOpenIDConfig
+		req *protocol.AuthRequest
+	}{
+		"empty": {
+			cfg: &GoogleOpenIDConfig{ClientId: "id", ClientSecret: "secret"},
+			req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_GOOGLE_OPEN_ID},
+		},
+		"id-token-without-device-flow": {
+			cfg: &GoogleOpenIDConfig{ClientId: "id", ClientSecret: "secret"},
+			req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_GOOGLE_OPEN_ID,
+				GoogleOpenId: &protocol.AuthRequest_GoogleOpenID{IdToken: "id-token"}},
+		},
+	}
+
+	for _, t := range tests {
+		called := false
+		_, err := t.cfg.unaryServerInterceptor()(context.Background(), t.req, nil,
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return nil, nil
+			})
+		assert.Error(err)
+		assert.False(called)
+	}
+}
+
 func TestGoogleOpenIDConfig_ClientAuth(t *testing.T) {
 	assert := assert.New(t)
 
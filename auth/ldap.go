@@ -0,0 +1,303 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh/terminal"
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultLdapUserFilter  = "(uid=%s)"
+	defaultLdapGroupFilter = "(|(member=%s)(uniqueMember=%s))"
+	ldapTimeout            = 10 * time.Second
+)
+
+// ldapConn is a connection to ldap server.
+type ldapConn interface {
+	Bind(username, password string) error
+	Search(req *ldap.SearchRequest) (*ldap.SearchResult, error)
+	Close()
+}
+
+// LdapConfig is a config for LDAP(Active Directory) authentication.
+// server searches user with bind dn, and binds as found user dn with password to verify it.
+type LdapConfig struct {
+	ClientUsername string // ldap username
+	ClientPassword string // ldap password(if empty, it's prompted)
+
+	ServerAddr               string              // ldap url (e.g. ldap://ldap.example.com:389, ldaps://ldap.example.com:636)
+	ServerStartTLS           bool                // upgrade ldap:// connection with StartTLS
+	ServerInsecureSkipVerify bool                // skip verifying certification of ldap server
+	ServerBindDN             string              // dn for searching users(if empty, anonymous search)
+	ServerBindPassword       string              // password of bind dn
+	ServerBaseDN             string              // base dn for searching users and groups
+	ServerUserFilter         string              // user filter, %s is replaced with username (default (uid=%s))
+	ServerGroupFilter        string              // group filter, %s is replaced with user dn (default (|(member=%s)(uniqueMember=%s)))
+	ServerAllowUsers         []string            // allow users
+	ServerAllowGroups        []string            // allow groups(cn or dn of group)
+	ServerGroupMapping       map[string][]string // vpn groups of ldap groups(map[vpn group][]cn or dn of ldap group)
+
+	dial func() (ldapConn, error) // dial ldap server
+}
+
+// ServerAuth returns ServerAuthMethod and bool value(whether exist or not).
+func (c *LdapConfig) ServerAuth() (ServerAuthMethod, bool) {
+	if c == nil || c.ServerAddr == "" || c.ServerBaseDN == "" {
+		return nil, false
+	}
+	return ServerAuthMethod(c.unaryServerInterceptor()), true
+}
+
+// ClientAuth is returns ClientAuthMethod for LDAP.
+func (c *LdapConfig) ClientAuth() (ClientAuthMethod, bool) {
+	return clientAuth(c)
+}
+
+// RefreshingClientAuth is returns RefreshingClientAuthMethod for LDAP.
+func (c *LdapConfig) RefreshingClientAuth() (RefreshingClientAuthMethod, bool) {
+	if c == nil || c.ClientUsername == "" {
+		return nil, false
+	}
+	return c.clientAuthMethod(), true
+}
+
+// unaryServerInterceptor returns new unary server interceptor that checks an authorization with ldap.
+func (c *LdapConfig) unaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		auth, ok := req.(*protocol.AuthRequest)
+		if !ok {
+			return handler(ctx, req)
+		}
+		if auth.AuthType != protocol.AuthType_AT_LDAP {
+			return handler(ctx, req)
+		}
+
+		// empty password must be refused, because ldap server regards it as anonymous bind.
+		if auth.Ldap == nil || auth.Ldap.Username == "" || auth.Ldap.Password == "" {
+			return nil, internal.ErrorUnauthorized
+		}
+		user := auth.Ldap.Username
+
+		if len(c.ServerAllowUsers) != 0 {
+			if !internal.IsMatchedStringFromSlice(user, c.ServerAllowUsers) {
+				return nil, internal.ErrorUnauthorized
+			}
+		}
+
+		conn, err := c.dialLdap()
+		if err != nil {
+			return nil, internal.ErrorUnknown
+		}
+		defer conn.Close()
+
+		if c.ServerBindDN != "" {
+			if err := conn.Bind(c.ServerBindDN, c.ServerBindPassword); err != nil {
+				return nil, internal.ErrorUnknown
+			}
+		}
+
+		userDN, err := c.searchUser(conn, user)
+		if err != nil {
+			return nil, internal.ErrorUnauthorized
+		}
+
+		// search groups before binding as user, because user may not have permission to search groups.
+		// if allowGroups is empty, don't check groups, and user is authenticated without groups when searching fails.
+		groupNames, groupDNs, err := c.searchGroups(conn, userDN)
+		if len(c.ServerAllowGroups) != 0 {
+			if err != nil {
+				return nil, internal.ErrorUnauthorized
+			}
+			if !isAllowedLdapGroup(append(groupNames, groupDNs...), c.ServerAllowGroups) {
+				return nil, internal.ErrorUnauthorized
+			}
+		}
+
+		// verify password
+		if err := conn.Bind(userDN, auth.Ldap.Password); err != nil {
+			return nil, internal.ErrorUnauthorized
+		}
+
+		// inject user, dn and vpn groups which ldap groups are mapped to
+		ldapGroups := append(groupNames, groupDNs...)
+		groups := mappedGroups(c.ServerGroupMapping, func(group string) bool {
+			return isAllowedLdapGroup(ldapGroups, []string{group})
+		})
+		newCtx := injectIdentity(ctx, user, userDN, groups, nil, nil)
+		return handler(newCtx, req)
+	}
+}
+
+// dialLdap connects to ldap server, and upgrades connection with StartTLS if it's set.
+func (c *LdapConfig) dialLdap() (ldapConn, error) {
+	if c.dial != nil {
+		return c.dial()
+	}
+
+	u, err := url.Parse(c.ServerAddr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: dialLdap")
+	}
+	tlsConfig := &tls.Config{ServerName: u.Hostname(), InsecureSkipVerify: c.ServerInsecureSkipVerify}
+
+	conn, err := ldap.DialURL(c.ServerAddr, ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: dialLdap")
+	}
+	conn.SetTimeout(ldapTimeout)
+
+	if c.ServerStartTLS && u.Scheme == "ldap" {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, errors.Wrapf(err, "Method: dialLdap")
+		}
+	}
+	return conn, nil
+}
+
+// searchUser returns dn of user, user must be matched with only one entry.
+func (c *LdapConfig) searchUser(conn ldapConn, user string) (string, error) {
+	filter := c.ServerUserFilter
+	if filter == "" {
+		filter = defaultLdapUserFilter
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(c.ServerBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		2, int(ldapTimeout/time.Second), false, replaceLdapFilter(filter, user), []string{"dn"}, nil))
+	if err != nil {
+		return "", errors.Wrapf(err, "Method: searchUser")
+	}
+	if len(result.Entries) != 1 {
+		return "", errors.Wrapf(internal.ErrorUnauthorized, "Method: searchUser")
+	}
+	return result.Entries[0].DN, nil
+}
+
+// searchGroups returns cn(names) and dn of groups which user is a member of.
+func (c *LdapConfig) searchGroups(conn ldapConn, userDN string) ([]string, []string, error) {
+	filter := c.ServerGroupFilter
+	if filter == "" {
+		filter = defaultLdapGroupFilter
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(c.ServerBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, int(ldapTimeout/time.Second), false, replaceLdapFilter(filter, userDN), []string{"cn"}, nil))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Method: searchGroups")
+	}
+
+	var names, dns []string
+	for _, entry := range result.Entries {
+		dns = append(dns, entry.DN)
+		names = append(names, entry.GetAttributeValues("cn")...)
+	}
+	return names, dns, nil
+}
+
+// clientAuthMethod returns auth method for client.
+func (c *LdapConfig) clientAuthMethod() RefreshingClientAuthMethod {
+	return func(conn protocol.VPNClient) (jwt string, refreshToken string, err error) {
+		if conn == nil {
+			return "", "", errors.Wrapf(internal.ErrorInvalidParams, "LDAP ClientAuthMethod")
+		}
+
+		username := c.ClientUsername
+		password := c.ClientPassword
+		if username == "" {
+			return "", "", errors.Wrapf(internal.ErrorInvalidParams, "LDAP ClientAuthMethod")
+		}
+
+		// prompt password if it isn't in config.
+		if password == "" {
+			fmt.Printf("[LDAP] %s password: ", username)
+			buf, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+			fmt.Println()
+			if err != nil {
+				return "", "", errors.Wrapf(err, "LDAP ClientAuthMethod")
+			}
+			password = string(buf)
+		}
+
+		// call authentication request to VPN server.
+		authCtx, authCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer authCancel()
+		response, err := conn.Auth(authCtx, &protocol.AuthRequest{
+			AuthType: protocol.AuthType_AT_LDAP,
+			Ldap: &protocol.AuthRequest_Ldap{
+				Username: username,
+				Password: password,
+			},
+		})
+		if err != nil {
+			return "", "", errors.Wrapf(internal.ErrorUnauthorized, "LDAP ClientAuthMethod")
+		}
+		if response.ErrorCode != protocol.ErrorCode_EC_SUCCESS || response.Jwt == "" {
+			return "", "", errors.Wrapf(internal.ErrorUnauthorized, "LDAP ClientAuthMethod")
+		}
+
+		return response.Jwt, response.RefreshToken, nil
+	}
+}
+
+// replaceLdapFilter replaces all %s in filter with escaped value.
+func replaceLdapFilter(filter, value string) string {
+	return strings.Replace(filter, "%s", ldap.EscapeFilter(value), -1)
+}
+
+// isAllowedLdapGroup checks whether one of groups is allowed or not, dn of group is compared case-insensitively.
+func isAllowedLdapGroup(groups []string, allowGroups []string) bool {
+	for _, group := range groups {
+		for _, allow := range allowGroups {
+			if strings.EqualFold(group, allow) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NewServerManagerForLdap returns ServerManager implementing ldap.
+// bind dn, StartTLS and filters are optional, they can be set to fields of LdapConfig.
+func NewServerManagerForLdap(addr, baseDN string, allowUsers, allowGroups []string) (ServerManager, error) {
+	if addr == "" || baseDN == "" {
+		return nil, internal.ErrorInvalidParams
+	}
+
+	if allowUsers == nil {
+		allowUsers = []string{}
+	}
+	if allowGroups == nil {
+		allowGroups = []string{}
+	}
+
+	return &LdapConfig{
+		ServerAddr:        addr,
+		ServerBaseDN:      baseDN,
+		ServerAllowUsers:  allowUsers,
+		ServerAllowGroups: allowGroups,
+	}, nil
+}
+
+// NewClientManagerForLdap returns ClientManager implementing ldap.
+// if password is empty, it's prompted when authenticating.
+func NewClientManagerForLdap(username, password string) (ClientManager, error) {
+	if username == "" {
+		return nil, internal.ErrorInvalidParams
+	}
+
+	return &LdapConfig{
+		ClientUsername: username,
+		ClientPassword: password,
+	}, nil
+}
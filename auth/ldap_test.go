@@ -0,0 +1,239 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLdap is an in-process ldap directory for test.
+type fakeLdap struct {
+	passwords map[string]string   // map[dn]password
+	users     map[string]string   // map[uid]dn
+	groups    map[string][]string // map[group dn]member dns
+}
+
+func (f *fakeLdap) Bind(username, password string) error {
+	if pw, ok := f.passwords[username]; !ok || pw != password {
+		return ldap.NewError(ldap.LDAPResultInvalidCredentials, fmt.Errorf("invalid credentials"))
+	}
+	return nil
+}
+
+func (f *fakeLdap) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	result := &ldap.SearchResult{}
+	for uid, dn := range f.users {
+		if req.Filter == fmt.Sprintf("(uid=%s)", ldap.EscapeFilter(uid)) {
+			result.Entries = append(result.Entries, ldap.NewEntry(dn, nil))
+		}
+	}
+	for group, members := range f.groups {
+		for _, member := range members {
+			if req.Filter == replaceLdapFilter(defaultLdapGroupFilter, member) {
+				dn, _ := ldap.ParseDN(group)
+				cn := dn.RDNs[0].Attributes[0].Value
+				result.Entries = append(result.Entries, ldap.NewEntry(group, map[string][]string{"cn": {cn}}))
+			}
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeLdap) Close() {}
+
+func newFakeLdap() *fakeLdap {
+	return &fakeLdap{
+		passwords: map[string]string{
+			"cn=admin,dc=example,dc=com":            "admin",
+			"uid=allan,ou=people,dc=example,dc=com": "allan-pw",
+			"uid=bob,ou=people,dc=example,dc=com":   "bob-pw",
+		},
+		users: map[string]string{
+			"allan": "uid=allan,ou=people,dc=example,dc=com",
+			"bob":   "uid=bob,ou=people,dc=example,dc=com",
+		},
+		groups: map[string][]string{
+			"cn=vpn,ou=groups,dc=example,dc=com": {"uid=allan,ou=people,dc=example,dc=com"},
+		},
+	}
+}
+
+func TestNewServerManagerForLdap(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		addr        string
+		baseDN      string
+		allowUsers  []string
+		allowGroups []string
+		isErr       bool
+	}{
+		"fail": {addr: "ldap://localhost:389", isErr: true},
+		"success": {
+			addr:        "ldap://localhost:389",
+			baseDN:      "dc=example,dc=com",
+			allowUsers:  []string{"allan"},
+			allowGroups: []string{"vpn"},
+		},
+	}
+
+	for _, t := range tests {
+		s, err := NewServerManagerForLdap(t.addr, t.baseDN, t.allowUsers, t.allowGroups)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Equal(t.addr, s.(*LdapConfig).ServerAddr)
+			assert.Equal(t.baseDN, s.(*LdapConfig).ServerBaseDN)
+			assert.Equal(t.allowUsers, s.(*LdapConfig).ServerAllowUsers)
+			assert.Equal(t.allowGroups, s.(*LdapConfig).ServerAllowGroups)
+			_, ok := s.ServerAuth()
+			assert.True(ok)
+		}
+	}
+}
+
+func TestNewClientManagerForLdap(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		username string
+		password string
+		isErr    bool
+	}{
+		"fail":     {isErr: true},
+		"success":  {username: "allan", password: "allan-pw"},
+		"prompted": {username: "allan"},
+	}
+
+	for _, t := range tests {
+		s, err := NewClientManagerForLdap(t.username, t.password)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Equal(t.username, s.(*LdapConfig).ClientUsername)
+			assert.Equal(t.password, s.(*LdapConfig).ClientPassword)
+			_, ok := s.ClientAuth()
+			assert.True(ok)
+		}
+	}
+}
+
+func TestLdapConfig_ServerAuth(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		bindDN       string
+		allowUsers   []string
+		allowGroups  []string
+		groupMapping map[string][]string
+		req          interface{}
+		user         string
+		groups       interface{}
+		isErr        bool
+	}{
+		"not-auth-request": {req: "ping"},
+		"other-auth-type":  {req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_TEST}},
+		"empty-password": {
+			req:   &protocol.AuthRequest{AuthType: protocol.AuthType_AT_LDAP, Ldap: &protocol.AuthRequest_Ldap{Username: "allan"}},
+			isErr: true,
+		},
+		"invalid-password": {
+			bindDN: "cn=admin,dc=example,dc=com",
+			req:    &protocol.AuthRequest{AuthType: protocol.AuthType_AT_LDAP, Ldap: &protocol.AuthRequest_Ldap{Username: "allan", Password: "bob-pw"}},
+			isErr:  true,
+		},
+		"unknown-user": {
+			bindDN: "cn=admin,dc=example,dc=com",
+			req:    &protocol.AuthRequest{AuthType: protocol.AuthType_AT_LDAP, Ldap: &protocol.AuthRequest_Ldap{Username: "carl", Password: "carl-pw"}},
+			isErr:  true,
+		},
+		"invalid-bind-dn": {
+			bindDN: "cn=unknown,dc=example,dc=com",
+			req:    &protocol.AuthRequest{AuthType: protocol.AuthType_AT_LDAP, Ldap: &protocol.AuthRequest_Ldap{Username: "allan", Password: "allan-pw"}},
+			isErr:  true,
+		},
+		"not-allowed-user": {
+			allowUsers: []string{"bob"},
+			req:        &protocol.AuthRequest{AuthType: protocol.AuthType_AT_LDAP, Ldap: &protocol.AuthRequest_Ldap{Username: "allan", Password: "allan-pw"}},
+			isErr:      true,
+		},
+		"not-allowed-group": {
+			bindDN:      "cn=admin,dc=example,dc=com",
+			allowGroups: []string{"vpn"},
+			req:         &protocol.AuthRequest{AuthType: protocol.AuthType_AT_LDAP, Ldap: &protocol.AuthRequest_Ldap{Username: "bob", Password: "bob-pw"}},
+			isErr:       true,
+		},
+		"success-anonymous-search": {
+			req:  &protocol.AuthRequest{AuthType: protocol.AuthType_AT_LDAP, Ldap: &protocol.AuthRequest_Ldap{Username: "bob", Password: "bob-pw"}},
+			user: "bob",
+		},
+		"success-unmapped-group": {
+			bindDN:      "cn=admin,dc=example,dc=com",
+			allowGroups: []string{"vpn"},
+			req:         &protocol.AuthRequest{AuthType: protocol.AuthType_AT_LDAP, Ldap: &protocol.AuthRequest_Ldap{Username: "allan", Password: "allan-pw"}},
+			user:        "allan",
+		},
+		"success-group-cn": {
+			bindDN:       "cn=admin,dc=example,dc=com",
+			allowUsers:   []string{"allan"},
+			allowGroups:  []string{"vpn"},
+			groupMapping: map[string][]string{"vpn-users": {"vpn"}, "admins": {"admin"}},
+			req:          &protocol.AuthRequest{AuthType: protocol.AuthType_AT_LDAP, Ldap: &protocol.AuthRequest_Ldap{Username: "allan", Password: "allan-pw"}},
+			user:         "allan",
+			groups:       []string{"vpn-users"},
+		},
+		"success-group-dn": {
+			bindDN:       "cn=admin,dc=example,dc=com",
+			allowGroups:  []string{"CN=vpn,OU=groups,DC=example,DC=com"},
+			groupMapping: map[string][]string{"vpn-users": {"CN=vpn,OU=groups,DC=example,DC=com"}},
+			req:          &protocol.AuthRequest{AuthType: protocol.AuthType_AT_LDAP, Ldap: &protocol.AuthRequest_Ldap{Username: "allan", Password: "allan-pw"}},
+			user:         "allan",
+			groups:       []string{"vpn-users"},
+		},
+	}
+
+	for _, t := range tests {
+		s, err := NewServerManagerForLdap("ldap://localhost:389", "dc=example,dc=com", t.allowUsers, t.allowGroups)
+		assert.NoError(err)
+		conf := s.(*LdapConfig)
+		conf.ServerBindDN = t.bindDN
+		conf.ServerBindPassword = "admin"
+		conf.ServerGroupMapping = t.groupMapping
+		conf.dial = func() (ldapConn, error) { return newFakeLdap(), nil }
+
+		method, ok := s.ServerAuth()
+		assert.True(ok)
+
+		var user, principal, groups interface{}
+		_, err = method(context.Background(), t.req, nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+			user, principal, groups = ctx.Value(UserCtxName), ctx.Value(PrincipalCtxName), ctx.Value(GroupsCtxName)
+			return nil, nil
+		})
+		assert.Equal(t.isErr, err != nil)
+		if t.user != "" {
+			assert.Equal(t.user, user)
+			assert.Equal(fmt.Sprintf("uid=%s,ou=people,dc=example,dc=com", t.user), principal)
+			assert.Equal(t.groups, groups)
+		}
+	}
+}
+
+func TestReplaceLdapFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		filter string
+		value  string
+		output string
+	}{
+		"single":   {filter: "(uid=%s)", value: "allan", output: "(uid=allan)"},
+		"multiple": {filter: "(|(member=%s)(uniqueMember=%s))", value: "uid=allan", output: "(|(member=uid=allan)(uniqueMember=uid=allan))"},
+		"escape":   {filter: "(uid=%s)", value: "*)(uid=*", output: `(uid=\2a\29\28uid=\2a)`},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.output, replaceLdapFilter(t.filter, t.value))
+	}
+}
@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/pkg/browser"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+// https://tools.ietf.org/html/rfc8252 OAuth 2.0 for Native Apps
+// https://tools.ietf.org/html/rfc7636 Proof Key for Code Exchange(PKCE)
+const (
+	redirectPath = "/code"
+
+	// redirect url of old clients which don't send redirect url.
+	defaultRedirectURL = "http://localhost:10000/code"
+)
+
+// openURL opens url on browser.
+var openURL = browser.OpenURL
+
+// loopbackTimeout is how long loopback redirect server waits for authorization code.
+var loopbackTimeout = 5 * time.Minute
+
+var resultPage = template.Must(template.New("result").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>GRPC-VPN</title></head>
+<body style="font-family: sans-serif; text-align: center; margin-top: 15%;">
+<h2 style="color: {{if .Success}}#2e7d32{{else}}#c62828{{end}};">{{.Title}}</h2>
+<p>{{.Message}}</p>
+</body>
+</html>`))
+
+// authorizationCode is a result of authorization code flow, vpn server exchanges it with code verifier and redirect url.
+type authorizationCode struct {
+	code         string // authorization code
+	codeVerifier string // PKCE code verifier
+	redirectURL  string // redirect url which is used for authorization request
+}
+
+// receiveAuthorizationCode opens authorization url on browser, and receives authorization code by loopback redirect server.
+// if port is empty, a free port is used.
+func receiveAuthorizationCode(conf oauth2.Config, name string, port string) (*authorizationCode, error) {
+	if port == "" {
+		port = "0"
+	}
+	listener, err := net.Listen("tcp", net.JoinHostPort("localhost", port))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: receiveAuthorizationCode")
+	}
+
+	result := &authorizationCode{
+		codeVerifier: newCodeVerifier(),
+		redirectURL:  fmt.Sprintf("http://localhost:%d%s", listener.Addr().(*net.TCPAddr).Port, redirectPath),
+	}
+	conf.RedirectURL = result.redirectURL
+	state := internal.GenerateRandomString(16)
+
+	// start http server
+	serverCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mux := http.NewServeMux()
+	server := http.Server{Handler: mux}
+	mux.HandleFunc(redirectPath, func(w http.ResponseWriter, req *http.Request) {
+		// request without valid state isn't the redirect of authorization(e.g. other local process, browser prefetch),
+		// so it's refused and the redirect is waited until timeout.
+		query := req.URL.Query()
+		if query.Get("state") != state {
+			renderResultPage(w, false, "Invalid state.")
+			return
+		}
+
+		defer cancel()
+		switch {
+		case query.Get("error") != "":
+			renderResultPage(w, false, fmt.Sprintf("%s %s", query.Get("error"), query.Get("error_description")))
+		case query.Get("code") == "":
+			renderResultPage(w, false, "Authorization code is empty, try to connect again.")
+		default:
+			result.code = query.Get("code")
+			renderResultPage(w, true, "You can close this page.")
+		}
+	})
+
+	go func() {
+		server.Serve(listener)
+	}()
+
+	authURL := conf.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("code_challenge", codeChallenge(result.codeVerifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"))
+	if err := openURL(authURL); err != nil {
+		server.Close()
+		return nil, errors.Wrapf(err, "Method: receiveAuthorizationCode")
+	}
+	fmt.Println(color.GreenString("[WAIT] YOUR %s AUTHENTICATION", name))
+	timer := time.NewTimer(loopbackTimeout)
+	defer timer.Stop()
+	select {
+	case <-serverCtx.Done():
+	case <-timer.C:
+	}
+	server.Shutdown(context.Background())
+	if result.code == "" {
+		return nil, errors.Wrapf(internal.ErrorUnauthorized, "Method: receiveAuthorizationCode")
+	}
+	return result, nil
+}
+
+// renderResultPage renders a page showing result of authentication.
+func renderResultPage(w http.ResponseWriter, success bool, message string) {
+	title := "Authentication Succeeded"
+	if !success {
+		title = "Authentication Failed"
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	resultPage.Execute(w, map[string]interface{}{"Success": success, "Title": title, "Message": message})
+}
+
+// exchangeOptions returns options for exchanging authorization code.
+// redirect url must be loopback url which client listens on, and it's default url if client doesn't send it.
+func exchangeOptions(conf *oauth2.Config, redirectURL, codeVerifier string) ([]oauth2.AuthCodeOption, error) {
+	if redirectURL == "" {
+		redirectURL = defaultRedirectURL
+	}
+	if !isLoopbackRedirectURL(redirectURL) {
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: exchangeOptions")
+	}
+	conf.RedirectURL = redirectURL
+
+	var opts []oauth2.AuthCodeOption
+	if codeVerifier != "" {
+		opts = append(opts, oauth2.SetAuthURLParam("code_verifier", codeVerifier))
+	}
+	return opts, nil
+}
+
+// isLoopbackRedirectURL checks whether url is http loopback url for redirect path.
+func isLoopbackRedirectURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "http" || u.Path != redirectPath || u.Port() == "" {
+		return false
+	}
+	switch u.Hostname() {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	default:
+		return false
+	}
+}
+
+// newCodeVerifier returns a random PKCE code verifier.
+func newCodeVerifier() string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return internal.GenerateRandomString(43)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// codeChallenge returns S256 PKCE code challenge of code verifier.
+func codeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+func TestReceiveAuthorizationCode(t *testing.T) {
+	assert := assert.New(t)
+	defer func(open func(string) error) { openURL = open }(openURL)
+	defer func(timeout time.Duration) { loopbackTimeout = timeout }(loopbackTimeout)
+	loopbackTimeout = 500 * time.Millisecond
+
+	conf := oauth2.Config{ClientID: "client-id", Endpoint: oauth2.Endpoint{AuthURL: "https://example.com/auth"}}
+
+	tests := map[string]struct {
+		port   string
+		prefix bool // request with invalid state is sent before redirect
+		query  func(state string) url.Values
+		status int
+		code   string
+		isErr  bool
+	}{
+		"invalid-state": {
+			query:  func(state string) url.Values { return url.Values{"state": {"other"}, "code": {"code"}} },
+			status: http.StatusBadRequest,
+			isErr:  true,
+		},
+		"invalid-state-before-redirect": {
+			prefix: true,
+			query:  func(state string) url.Values { return url.Values{"state": {state}, "code": {"code"}} },
+			status: http.StatusOK,
+			code:   "code",
+		},
+		"denied": {
+			query:  func(state string) url.Values { return url.Values{"state": {state}, "error": {"access_denied"}} },
+			status: http.StatusBadRequest,
+			isErr:  true,
+		},
+		"success": {
+			query:  func(state string) url.Values { return url.Values{"state": {state}, "code": {"code"}} },
+			status: http.StatusOK,
+			code:   "code",
+		},
+		"success-port": {
+			port:   "18765",
+			query:  func(state string) url.Values { return url.Values{"state": {state}, "code": {"code"}} },
+			status: http.StatusOK,
+			code:   "code",
+		},
+	}
+
+	for _, t := range tests {
+		var challenge, redirect string
+		sent := make(chan bool, 1)
+		openURL = func(raw string) error {
+			u, err := url.Parse(raw)
+			if err != nil {
+				return err
+			}
+			challenge = u.Query().Get("code_challenge")
+			redirect = u.Query().Get("redirect_uri")
+			go func() {
+				defer func() { sent <- true }()
+				if t.prefix {
+					resp, err := http.Get(redirect + "?" + url.Values{"state": {"other"}}.Encode())
+					if assert.NoError(err) {
+						assert.Equal(http.StatusBadRequest, resp.StatusCode)
+						resp.Body.Close()
+					}
+				}
+				resp, err := http.Get(redirect + "?" + t.query(u.Query().Get("state")).Encode())
+				if assert.NoError(err) {
+					assert.Equal(t.status, resp.StatusCode)
+					resp.Body.Close()
+				}
+			}()
+			return nil
+		}
+
+		result, err := receiveAuthorizationCode(conf, "TEST", t.port)
+		<-sent
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Equal(t.code, result.code)
+			assert.Equal(redirect, result.redirectURL)
+			assert.Equal(challenge, codeChallenge(result.codeVerifier))
+			assert.True(isLoopbackRedirectURL(result.redirectURL))
+			if t.port != "" {
+				assert.Equal("http://localhost:"+t.port+redirectPath, result.redirectURL)
+			}
+		}
+	}
+}
+
+func TestExchangeOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		redirectURL  string
+		codeVerifier string
+		output       string
+		opts         int
+		isErr        bool
+	}{
+		"invalid":  {redirectURL: "https://evil.com/code", isErr: true},
+		"default":  {output: defaultRedirectURL},
+		"loopback": {redirectURL: "http://localhost:53123/code", codeVerifier: "verifier", output: "http://localhost:53123/code", opts: 1},
+	}
+
+	for _, t := range tests {
+		conf := &oauth2.Config{}
+		opts, err := exchangeOptions(conf, t.redirectURL, t.codeVerifier)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Equal(t.output, conf.RedirectURL)
+			assert.Len(opts, t.opts)
+		}
+	}
+}
+
+func TestIsLoopbackRedirectURL(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input  string
+		output bool
+	}{
+		"empty":      {},
+		"https":      {input: "https://localhost:10000/code"},
+		"other-host": {input: "http://example.com:10000/code"},
+		"other-path": {input: "http://localhost:10000/callback"},
+		"no-port":    {input: "http://localhost/code"},
+		"localhost":  {input: "http://localhost:10000/code", output: true},
+		"ipv4":       {input: "http://127.0.0.1:53123/code", output: true},
+		"ipv6":       {input: "http://[::1]:53123/code", output: true},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.output, isLoopbackRedirectURL(t.input))
+	}
+}
+
+func TestCodeChallenge(t *testing.T) {
+	assert := assert.New(t)
+
+	verifier := newCodeVerifier()
+	assert.Len(verifier, 43)
+	assert.NotEqual(verifier, newCodeVerifier())
+	assert.Equal("z75WxsKxxxlaAonhcoO74A_F7KLaRIkVcJKSD4JYZYA", codeChallenge("dBjftJeZ4CVP-mJ92K4cAwj0q6vMrGMDVCVX46N1R3Q"))
+}
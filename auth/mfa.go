@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh/terminal"
+	"google.golang.org/grpc"
+)
+
+// MFAPrompt returns totp code which user inputs for second factor.
+type MFAPrompt func() (code string, err error)
+
+// PromptTOTPCode reads totp code from terminal.
+func PromptTOTPCode() (string, error) {
+	fmt.Print("[MFA] totp code: ")
+	buf, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return "", errors.Wrapf(err, "Method: PromptTOTPCode")
+	}
+	return strings.TrimSpace(string(buf)), nil
+}
+
+// mfaClient is a VPNClient which passes second factor when vpn server requires it.
+type mfaClient struct {
+	protocol.VPNClient
+	prompt MFAPrompt
+}
+
+// Auth calls authentication request, and sends totp code if vpn server returns mfa challenge.
+func (m *mfaClient) Auth(ctx context.Context, in *protocol.AuthRequest, opts ...grpc.CallOption) (*protocol.AuthResponse, error) {
+	response, err := m.VPNClient.Auth(ctx, in, opts...)
+	if err != nil || response.ErrorCode != protocol.ErrorCode_EC_MFA_REQUIRED {
+		return response, err
+	}
+	if m.prompt == nil || response.MfaChallengeId == "" {
+		return nil, errors.Wrapf(internal.ErrorUnauthorized, "Method: Auth")
+	}
+
+	code, err := m.prompt()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: Auth")
+	}
+
+	// primary auth request could use its timeout while user inputs code.
+	mfaCtx, mfaCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer mfaCancel()
+	return m.VPNClient.Auth(mfaCtx, &protocol.AuthRequest{
+		AuthType: protocol.AuthType_AT_MFA,
+		Mfa: &protocol.AuthRequest_Mfa{
+			ChallengeId: response.MfaChallengeId,
+			Code:        code,
+		},
+	}, opts...)
+}
+
+// NewMFAClient returns VPNClient which prompts totp code when vpn server requires second factor.
+// ClientAuthMethod is called with it, so any auth method can pass second factor.
+func NewMFAClient(conn protocol.VPNClient, prompt MFAPrompt) protocol.VPNClient {
+	if conn == nil {
+		return nil
+	}
+	return &mfaClient{VPNClient: conn, prompt: prompt}
+}
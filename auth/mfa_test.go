@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// fakeMFAServer returns mfa challenge for primary auth request, and jwt for valid totp code.
+type fakeMFAServer struct {
+	protocol.VPNClient
+	requests []*protocol.AuthRequest
+	mfa      bool
+}
+
+func (f *fakeMFAServer) Auth(ctx context.Context, in *protocol.AuthRequest, opts ...grpc.CallOption) (*protocol.AuthResponse, error) {
+	f.requests = append(f.requests, in)
+	switch {
+	case in.AuthType != protocol.AuthType_AT_MFA && f.mfa:
+		return &protocol.AuthResponse{ErrorCode: protocol.ErrorCode_EC_MFA_REQUIRED, MfaChallengeId: "challenge"}, nil
+	case in.AuthType != protocol.AuthType_AT_MFA:
+		return &protocol.AuthResponse{ErrorCode: protocol.ErrorCode_EC_SUCCESS, Jwt: "jwt"}, nil
+	case in.Mfa.ChallengeId == "challenge" && in.Mfa.Code == "123456":
+		return &protocol.AuthResponse{ErrorCode: protocol.ErrorCode_EC_SUCCESS, Jwt: "jwt"}, nil
+	default:
+		return &protocol.AuthResponse{ErrorCode: protocol.ErrorCode_EC_INVALID_AUTHORIZATION}, nil
+	}
+}
+
+func TestNewMFAClient(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(NewMFAClient(nil, PromptTOTPCode))
+
+	prompt := func(code string, err error) MFAPrompt {
+		return func() (string, error) { return code, err }
+	}
+
+	tests := map[string]struct {
+		mfa      bool
+		prompt   MFAPrompt
+		requests int
+		output   string
+		isErr    bool
+	}{
+		"without-mfa":    {prompt: prompt("", errors.New("not called")), requests: 1, output: "jwt"},
+		"without-prompt": {mfa: true, requests: 1, isErr: true},
+		"prompt-error":   {mfa: true, prompt: prompt("", errors.New("canceled")), requests: 1, isErr: true},
+		"invalid-code":   {mfa: true, prompt: prompt("000000", nil), requests: 2},
+		"success":        {mfa: true, prompt: prompt("123456", nil), requests: 2, output: "jwt"},
+	}
+
+	for _, t := range tests {
+		server := &fakeMFAServer{mfa: t.mfa}
+		conn := NewMFAClient(server, t.prompt)
+		resp, err := conn.Auth(context.Background(), &protocol.AuthRequest{AuthType: protocol.AuthType_AT_TEST})
+		assert.Equal(t.isErr, err != nil)
+		assert.Len(server.requests, t.requests)
+		if err == nil {
+			assert.Equal(t.output, resp.Jwt)
+		}
+	}
+}
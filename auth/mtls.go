@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// MTLSConfig is a config for mutual tls authentication.
+// vpn client presents a client certificate in tls handshake, and vpn server derives user from verified certificate.
+// vpn server must be run with client CA, and vpn client must be run with client certificate.
+type MTLSConfig struct{}
+
+// ServerAuth returns ServerAuthMethod and bool value(whether exist or not).
+func (c *MTLSConfig) ServerAuth() (ServerAuthMethod, bool) {
+	if c == nil {
+		return nil, false
+	}
+	return ServerAuthMethod(c.unaryServerInterceptor()), true
+}
+
+// ClientAuth is returns ClientAuthMethod for mutual tls.
+func (c *MTLSConfig) ClientAuth() (ClientAuthMethod, bool) {
+	return clientAuth(c)
+}
+
+// RefreshingClientAuth is returns RefreshingClientAuthMethod for mutual tls.
+func (c *MTLSConfig) RefreshingClientAuth() (RefreshingClientAuthMethod, bool) {
+	if c == nil {
+		return nil, false
+	}
+	return c.clientAuthMethod(), true
+}
+
+// unaryServerInterceptor returns new unary server interceptor that checks a client certificate verified by client CA.
+func (c *MTLSConfig) unaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		auth, ok := req.(*protocol.AuthRequest)
+		if !ok {
+			return handler(ctx, req)
+		}
+		if auth.AuthType != protocol.AuthType_AT_MTLS {
+			return handler(ctx, req)
+		}
+
+		user := peerCertificateUser(ctx)
+		if user == "" {
+			return nil, internal.ErrorUnauthorized
+		}
+
+		// inject user
+		newCtx := context.WithValue(ctx, UserCtxName, user)
+		return handler(newCtx, req)
+	}
+}
+
+// clientAuthMethod returns auth method for client.
+func (c *MTLSConfig) clientAuthMethod() RefreshingClientAuthMethod {
+	return func(conn protocol.VPNClient) (jwt string, refreshToken string, err error) {
+		if conn == nil {
+			return "", "", errors.Wrapf(internal.ErrorInvalidParams, "MTLS ClientAuthMethod")
+		}
+
+		// call authentication request to VPN server.
+		authCtx, authCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer authCancel()
+		response, err := conn.Auth(authCtx, &protocol.AuthRequest{
+			AuthType: protocol.AuthType_AT_MTLS,
+		})
+		if err != nil {
+			return "", "", errors.Wrapf(internal.ErrorUnauthorized, "MTLS ClientAuthMethod")
+		}
+		if response.ErrorCode != protocol.ErrorCode_EC_SUCCESS || response.Jwt == "" {
+			return "", "", errors.Wrapf(internal.ErrorUnauthorized, "MTLS ClientAuthMethod")
+		}
+
+		return response.Jwt, response.RefreshToken, nil
+	}
+}
+
+// peerCertificateUser returns user of client certificate which is verified by client CA.
+// user is common name of certificate, if it's empty, the first SAN(dns, email, uri) is used.
+func peerCertificateUser(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	switch {
+	case cert.Subject.CommonName != "":
+		return cert.Subject.CommonName
+	case len(cert.DNSNames) > 0:
+		return cert.DNSNames[0]
+	case len(cert.EmailAddresses) > 0:
+		return cert.EmailAddresses[0]
+	case len(cert.URIs) > 0:
+		return cert.URIs[0].String()
+	default:
+		return ""
+	}
+}
+
+// NewServerManagerForMTLS returns ServerManager implementing mutual tls.
+// vpn server must be run with client CA.
+func NewServerManagerForMTLS() (ServerManager, error) {
+	return &MTLSConfig{}, nil
+}
+
+// NewClientManagerForMTLS returns ClientManager implementing mutual tls.
+func NewClientManagerForMTLS() (ClientManager, error) {
+	return &MTLSConfig{}, nil
+}
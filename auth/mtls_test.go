@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal/testcert"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/peer"
+)
+
+func TestPeerCertificateUser(t *testing.T) {
+	assert := assert.New(t)
+
+	ca, err := testcert.New(&x509.Certificate{Subject: pkix.Name{CommonName: "ca"}}, nil)
+	assert.NoError(err)
+	cn, err := testcert.New(&x509.Certificate{Subject: pkix.Name{CommonName: "allan"}}, ca)
+	assert.NoError(err)
+	dns, err := testcert.New(&x509.Certificate{DNSNames: []string{"build-agent.example.com"}}, ca)
+	assert.NoError(err)
+	email, err := testcert.New(&x509.Certificate{EmailAddresses: []string{"kiosk@example.com"}}, ca)
+	assert.NoError(err)
+
+	tests := map[string]struct {
+		ctx    context.Context
+		output string
+	}{
+		"no-peer":    {ctx: context.Background()},
+		"insecure":   {ctx: peer.NewContext(context.Background(), &peer.Peer{})},
+		"unverified": {ctx: testcert.PeerContext()},
+		"cn":         {ctx: testcert.PeerContext(cn, ca), output: "allan"},
+		"dns":        {ctx: testcert.PeerContext(dns, ca), output: "build-agent.example.com"},
+		"email":      {ctx: testcert.PeerContext(email, ca), output: "kiosk@example.com"},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.output, peerCertificateUser(t.ctx))
+	}
+}
+
+func TestMTLSConfig_ServerAuth(t *testing.T) {
+	assert := assert.New(t)
+
+	var nilConfig *MTLSConfig
+	_, ok := nilConfig.ServerAuth()
+	assert.False(ok)
+
+	ca, err := testcert.New(&x509.Certificate{Subject: pkix.Name{CommonName: "ca"}}, nil)
+	assert.NoError(err)
+	cert, err := testcert.New(&x509.Certificate{Subject: pkix.Name{CommonName: "allan"}}, ca)
+	assert.NoError(err)
+
+	tests := map[string]struct {
+		ctx      context.Context
+		authType protocol.AuthType
+		user     interface{}
+		isErr    bool
+	}{
+		"other-type":   {ctx: testcert.PeerContext(cert, ca), authType: protocol.AuthType_AT_TEST},
+		"without-cert": {ctx: testcert.PeerContext(), authType: protocol.AuthType_AT_MTLS, isErr: true},
+		"success":      {ctx: testcert.PeerContext(cert, ca), authType: protocol.AuthType_AT_MTLS, user: "allan"},
+	}
+
+	s, err := NewServerManagerForMTLS()
+	assert.NoError(err)
+	method, ok := s.ServerAuth()
+	assert.True(ok)
+	for _, t := range tests {
+		var user interface{}
+		_, err := method(t.ctx, &protocol.AuthRequest{AuthType: t.authType}, nil,
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				user = ctx.Value(UserCtxName)
+				return nil, nil
+			})
+		assert.Equal(t.isErr, err != nil)
+		assert.Equal(t.user, user)
+	}
+}
+
+func TestNewClientManagerForMTLS(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := NewClientManagerForMTLS()
+	assert.NoError(err)
+	_, ok := s.ClientAuth()
+	assert.True(ok)
+}
@@ -0,0 +1,320 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/coreos/go-oidc"
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultOpenIDUsernameClaim = "email"
+)
+
+// OpenIDConfig is a config for generic OpenID Connect providers(Okta, Keycloak, ...).
+// it uses authorization code flow like google openid, vpn client receives code and vpn server exchanges it.
+type OpenIDConfig struct {
+	Issuer       string   // issuer url (e.g. https://example.okta.com, https://keycloak.example.com/auth/realms/example)
+	ClientId     string   // client id
+	ClientSecret string   // client secret
+	Scopes       []string // scopes (default openid, profile, email)
+	DeviceFlow   bool     // use device authorization grant instead of browser for headless client (only vpn-client)
+	RedirectPort string   // port of loopback redirect url, if empty, a free port is used (only vpn-client)
+
+	UsernameClaim  string              // claim to use as username (default email) (only vpn-server)
+	RequiredClaims map[string]string   // claims which must have value (only vpn-server)
+	GroupsClaim    string              // claim having groups (only vpn-server)
+	AllowUsers     []string            // allow users (only vpn-server)
+	AllowGroups    []string            // allow groups, GroupsClaim must be set (only vpn-server)
+	GroupMapping   map[string][]string // vpn groups of provider groups(map[vpn group][]group), GroupsClaim must be set (only vpn-server)
+}
+
+// ServerAuth returns ServerAuthMethod and bool value(whether exist or not).
+func (c *OpenIDConfig) ServerAuth() (ServerAuthMethod, bool) {
+	if c == nil || c.Issuer == "" || c.ClientId == "" {
+		return nil, false
+	}
+	return ServerAuthMethod(c.unaryServerInterceptor()), true
+}
+
+// ClientAuth is returns ClientAuthMethod for OpenID Connect.
+func (c *OpenIDConfig) ClientAuth() (ClientAuthMethod, bool) {
+	return clientAuth(c)
+}
+
+// RefreshingClientAuth is returns RefreshingClientAuthMethod for OpenID Connect.
+func (c *OpenIDConfig) RefreshingClientAuth() (RefreshingClientAuthMethod, bool) {
+	if c == nil || c.Issuer == "" || c.ClientId == "" {
+		return nil, false
+	}
+	return c.clientAuthMethod(), true
+}
+
+// unaryServerInterceptor returns new unary server interceptor that checks an authorization with openID.
+func (c *OpenIDConfig) unaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		auth, ok := req.(*protocol.AuthRequest)
+		if !ok {
+			return handler(ctx, req)
+		}
+		if auth.AuthType != protocol.AuthType_AT_OPEN_ID {
+			return handler(ctx, req)
+		}
+
+		if auth.OpenId == nil || (auth.OpenId.Code == "" && auth.OpenId.IdToken == "") {
+			return nil, internal.ErrorUnauthorized
+		}
+
+		provider, err := oidc.NewProvider(ctx, c.Issuer)
+		if err != nil {
+			return nil, internal.ErrorUnknown
+		}
+
+		var claims map[string]interface{}
+		if auth.OpenId.IdToken != "" {
+			claims, err = verifyOpenIDToken(ctx, provider, auth.OpenId.IdToken, c.ClientId)
+		} else {
+			claims, err = exchangeOpenIDCode(ctx, provider, c.oauth2Config(provider), auth.OpenId.Code,
+				auth.OpenId.RedirectUri, auth.OpenId.CodeVerifier)
+		}
+		if err != nil {
+			return nil, internal.ErrorUnauthorized
+		}
+
+		user, err := c.verifyClaims(claims)
+		if err != nil {
+			return nil, internal.ErrorUnauthorized
+		}
+
+		// inject user, subject and vpn groups which groups of provider are mapped to
+		var groups []string
+		if c.GroupsClaim != "" {
+			providerGroups := claimStrings(claims[c.GroupsClaim])
+			groups = mappedGroups(c.GroupMapping, func(group string) bool {
+				return internal.IsMatchedStringFromSlice(group, providerGroups)
+			})
+		}
+		subject, _ := claims["sub"].(string)
+		newCtx := injectIdentity(ctx, user, subject, groups, nil, map[string]string{"issuer": c.Issuer})
+		return handler(newCtx, req)
+	}
+}
+
+// verifyClaims checks required claims, allow users and allow groups, and returns username.
+func (c *OpenIDConfig) verifyClaims(claims map[string]interface{}) (string, error) {
+	usernameClaim := c.UsernameClaim
+	if usernameClaim == "" {
+		usernameClaim = defaultOpenIDUsernameClaim
+	}
+	user, ok := claims[usernameClaim].(string)
+	if !ok || user == "" {
+		return "", errors.Wrapf(internal.ErrorUnauthorized, "Method: verifyClaims")
+	}
+
+	// check required claims
+	for name, value := range c.RequiredClaims {
+		if !internal.IsMatchedStringFromSlice(value, claimStrings(claims[name])) {
+			return "", errors.Wrapf(internal.ErrorUnauthorized, "Method: verifyClaims")
+		}
+	}
+
+	// check user
+	// if allowUsers is empty, don't check user
+	if len(c.AllowUsers) != 0 {
+		if !internal.IsMatchedStringFromSlice(user, c.AllowUsers) {
+			return "", errors.Wrapf(internal.ErrorUnauthorized, "Method: verifyClaims")
+		}
+	}
+
+	// check groups
+	// if allowGroups is empty, don't check groups
+	if len(c.AllowGroups) != 0 {
+		var allowed bool
+		for _, group := range claimStrings(claims[c.GroupsClaim]) {
+			if internal.IsMatchedStringFromSlice(group, c.AllowGroups) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return "", errors.Wrapf(internal.ErrorUnauthorized, "Method: verifyClaims")
+		}
+	}
+	return user, nil
+}
+
+// oauth2Config returns oauth2 config for provider.
+func (c *OpenIDConfig) oauth2Config(provider *oidc.Provider) oauth2.Config {
+	scopes := c.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{oidc.ScopeOpenID, "profile", "email"}
+	}
+	return oauth2.Config{
+		ClientID:     c.ClientId,
+		ClientSecret: c.ClientSecret,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       scopes,
+	}
+}
+
+// clientAuthMethod returns auth method for client.
+func (c *OpenIDConfig) clientAuthMethod() RefreshingClientAuthMethod {
+	return func(conn protocol.VPNClient) (jwt string, refreshToken string, err error) {
+		if conn == nil {
+			return "", "", errors.Wrapf(internal.ErrorInvalidParams, "OpenID ClientAuthMethod")
+		}
+
+		provider, err := oidc.NewProvider(context.Background(), c.Issuer)
+		if err != nil {
+			return "", "", errors.Wrapf(err, "OpenID ClientAuthMethod")
+		}
+
+		openID := &protocol.AuthRequest_OpenID{}
+		if c.DeviceFlow {
+			endpoint, err := deviceAuthorizationEndpoint(provider)
+			if err != nil {
+				return "", "", errors.Wrapf(err, "OpenID ClientAuthMethod")
+			}
+			openID.IdToken, err = receiveDeviceIDToken(context.Background(), endpoint, c.oauth2Config(provider), "OPENID")
+			if err != nil {
+				return "", "", errors.Wrapf(err, "OpenID ClientAuthMethod")
+			}
+		} else {
+			result, err := receiveAuthorizationCode(c.oauth2Config(provider), "OPENID", c.RedirectPort)
+			if err != nil {
+				return "", "", errors.Wrapf(err, "OpenID ClientAuthMethod")
+			}
+			openID.Code, openID.CodeVerifier, openID.RedirectUri = result.code, result.codeVerifier, result.redirectURL
+		}
+
+		// call authentication request to VPN server.
+		authCtx, authCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer authCancel()
+		response, err := conn.Auth(authCtx, &protocol.AuthRequest{
+			AuthType: protocol.AuthType_AT_OPEN_ID,
+			OpenId:   openID,
+		})
+		if err != nil {
+			return "", "", errors.Wrapf(internal.ErrorUnauthorized, "OpenID ClientAuthMethod")
+		}
+		if response.ErrorCode != protocol.ErrorCode_EC_SUCCESS || response.Jwt == "" {
+			return "", "", errors.Wrapf(internal.ErrorUnauthorized, "OpenID ClientAuthMethod")
+		}
+
+		return response.Jwt, response.RefreshToken, nil
+	}
+}
+
+// exchangeOpenIDCode exchanges authorization code for id token, and returns verified claims of id token.
+// redirectURL and codeVerifier are sent by client, they may be empty if client is old.
+func exchangeOpenIDCode(ctx context.Context, provider *oidc.Provider, conf oauth2.Config, code, redirectURL, codeVerifier string) (map[string]interface{}, error) {
+	opts, err := exchangeOptions(&conf, redirectURL, codeVerifier)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: exchangeOpenIDCode")
+	}
+
+	exCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	token, err := conf.Exchange(exCtx, code, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: exchangeOpenIDCode")
+	}
+
+	// Fail when remaining lifetime is less than 10 seconds.
+	if !token.Expiry.IsZero() && time.Until(token.Expiry) <= 10*time.Second {
+		return nil, errors.Wrapf(internal.ErrorUnauthorized, "Method: exchangeOpenIDCode")
+	}
+
+	rawIdToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.Wrapf(internal.ErrorUnauthorized, "Method: exchangeOpenIDCode")
+	}
+
+	claims, err := verifyOpenIDToken(ctx, provider, rawIdToken, conf.ClientID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: exchangeOpenIDCode")
+	}
+	return claims, nil
+}
+
+// verifyOpenIDToken verifies id token which must be issued to one of client ids, and returns claims of id token.
+func verifyOpenIDToken(ctx context.Context, provider *oidc.Provider, rawIdToken string, clientIds ...string) (map[string]interface{}, error) {
+	idToken, err := provider.Verifier(&oidc.Config{SkipClientIDCheck: true}).Verify(ctx, rawIdToken)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: verifyOpenIDToken")
+	}
+
+	var matched bool
+	for _, aud := range idToken.Audience {
+		if aud != "" && internal.IsMatchedStringFromSlice(aud, clientIds) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return nil, errors.Wrapf(internal.ErrorUnauthorized, "Method: verifyOpenIDToken")
+	}
+
+	claims := map[string]interface{}{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, errors.Wrapf(err, "Method: verifyOpenIDToken")
+	}
+	return claims, nil
+}
+
+// claimStrings converts claim value(string, number, bool or array of them) to string slice.
+func claimStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case []interface{}:
+		var values []string
+		for _, vv := range v {
+			values = append(values, claimStrings(vv)...)
+		}
+		return values
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// NewServerManagerForOpenID returns ServerManager implementing openID.
+// username claim, required claims and groups are optional, they can be set to fields of OpenIDConfig.
+func NewServerManagerForOpenID(issuer, clientId, clientSecret string, allowUsers []string) (ServerManager, error) {
+	if issuer == "" || clientId == "" {
+		return nil, internal.ErrorInvalidParams
+	}
+
+	if allowUsers == nil {
+		allowUsers = []string{}
+	}
+
+	return &OpenIDConfig{
+		Issuer:       issuer,
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		AllowUsers:   allowUsers,
+	}, nil
+}
+
+// NewClientManagerForOpenID returns ClientManager implementing openID.
+func NewClientManagerForOpenID(issuer, clientId string, scopes []string) (ClientManager, error) {
+	if issuer == "" || clientId == "" {
+		return nil, internal.ErrorInvalidParams
+	}
+
+	return &OpenIDConfig{
+		Issuer:   issuer,
+		ClientId: clientId,
+		Scopes:   scopes,
+	}, nil
+}
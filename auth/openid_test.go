@@ -0,0 +1,301 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/square/go-jose.v2"
+)
+
+// mockOpenIDIssuer is a local openid connect issuer for test.
+// it issues id token having claims of the code, and device code is authorized after first polling.
+type mockOpenIDIssuer struct {
+	*httptest.Server
+	key      *rsa.PrivateKey
+	clientId string
+	claims   map[string]jwt.MapClaims // map[code]claims
+	polled   map[string]bool          // map[device code]polled
+}
+
+func newMockOpenIDIssuer(t *testing.T, clientId string) *mockOpenIDIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := &mockOpenIDIssuer{key: key, clientId: clientId, claims: map[string]jwt.MapClaims{}, polled: map[string]bool{}}
+	mux := http.NewServeMux()
+	issuer.Server = httptest.NewServer(mux)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                issuer.URL,
+			"authorization_endpoint":                issuer.URL + "/auth",
+			"token_endpoint":                        issuer.URL + "/token",
+			"jwks_uri":                              issuer.URL + "/keys",
+			"device_authorization_endpoint":         issuer.URL + "/device",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+			{Key: &key.PublicKey, KeyID: "test", Algorithm: "RS256", Use: "sig"},
+		}})
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("client_id") != clientId {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"device_code":      r.FormValue("scope"), // test uses scope as device code.
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": issuer.URL + "/activate",
+			"expires_in":       10,
+			"interval":         1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		code := r.FormValue("code")
+		if r.FormValue("grant_type") == deviceCodeGrantType {
+			code = r.FormValue("device_code")
+			if !issuer.polled[code] {
+				issuer.polled[code] = true
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": "authorization_pending"})
+				return
+			}
+		}
+
+		claims, ok := issuer.claims[code]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "access_denied"})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     issuer.idToken(clientId, claims),
+		})
+	})
+	return issuer
+}
+
+// idToken returns id token issued to client.
+func (m *mockOpenIDIssuer) idToken(clientId string, claims jwt.MapClaims) string {
+	idClaims := jwt.MapClaims{"iss": m.URL, "aud": clientId, "sub": "test",
+		"iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix()}
+	for k, v := range claims {
+		idClaims[k] = v
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, idClaims)
+	token.Header["kid"] = "test"
+	idToken, _ := token.SignedString(m.key)
+	return idToken
+}
+
+func TestNewServerManagerForOpenID(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		issuer       string
+		clientId     string
+		clientSecret string
+		allowUsers   []string
+		isErr        bool
+	}{
+		"fail":    {issuer: "https://example.okta.com", isErr: true},
+		"success": {issuer: "https://example.okta.com", clientId: "client-id", clientSecret: "client-secret", allowUsers: []string{"allan@example.com"}},
+	}
+
+	for _, t := range tests {
+		s, err := NewServerManagerForOpenID(t.issuer, t.clientId, t.clientSecret, t.allowUsers)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Equal(t.issuer, s.(*OpenIDConfig).Issuer)
+			assert.Equal(t.clientId, s.(*OpenIDConfig).ClientId)
+			assert.Equal(t.clientSecret, s.(*OpenIDConfig).ClientSecret)
+			assert.Equal(t.allowUsers, s.(*OpenIDConfig).AllowUsers)
+			_, ok := s.ServerAuth()
+			assert.True(ok)
+		}
+	}
+}
+
+func TestNewClientManagerForOpenID(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		issuer   string
+		clientId string
+		scopes   []string
+		isErr    bool
+	}{
+		"fail":    {clientId: "client-id", isErr: true},
+		"success": {issuer: "https://example.okta.com", clientId: "client-id", scopes: []string{"openid", "groups"}},
+	}
+
+	for _, t := range tests {
+		s, err := NewClientManagerForOpenID(t.issuer, t.clientId, t.scopes)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Equal(t.issuer, s.(*OpenIDConfig).Issuer)
+			assert.Equal(t.clientId, s.(*OpenIDConfig).ClientId)
+			assert.Equal(t.scopes, s.(*OpenIDConfig).Scopes)
+			_, ok := s.ClientAuth()
+			assert.True(ok)
+		}
+	}
+}
+
+func TestOpenIDConfig_ServerAuth(t *testing.T) {
+	assert := assert.New(t)
+
+	issuer := newMockOpenIDIssuer(t, "client-id")
+	defer issuer.Close()
+	issuer.claims["allan"] = jwt.MapClaims{"email": "allan@example.com", "preferred_username": "allan",
+		"email_verified": true, "groups": []string{"vpn", "dev"}}
+	issuer.claims["bob"] = jwt.MapClaims{"email": "bob@example.com", "email_verified": false, "groups": "dev"}
+
+	tests := map[string]struct {
+		cfg    *OpenIDConfig
+		req    interface{}
+		user   string
+		groups interface{}
+		err    bool
+	}{
+		"not-auth-request": {cfg: &OpenIDConfig{}, req: "ping"},
+		"other-auth-type":  {cfg: &OpenIDConfig{}, req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_TEST}},
+		"empty-code": {
+			cfg: &OpenIDConfig{},
+			req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_OPEN_ID, OpenId: &protocol.AuthRequest_OpenID{}},
+			err: true,
+		},
+		"invalid-code": {
+			cfg: &OpenIDConfig{},
+			req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_OPEN_ID, OpenId: &protocol.AuthRequest_OpenID{Code: "unknown"}},
+			err: true,
+		},
+		"invalid-redirect-uri": {
+			cfg: &OpenIDConfig{},
+			req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_OPEN_ID, OpenId: &protocol.AuthRequest_OpenID{
+				Code: "allan", RedirectUri: "https://evil.com/code", CodeVerifier: "verifier"}},
+			err: true,
+		},
+		"success-pkce": {
+			cfg: &OpenIDConfig{},
+			req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_OPEN_ID, OpenId: &protocol.AuthRequest_OpenID{
+				Code: "bob", RedirectUri: "http://localhost:53123/code", CodeVerifier: "verifier"}},
+			user: "bob@example.com",
+		},
+		"invalid-client": {
+			cfg: &OpenIDConfig{ClientId: "other-client"},
+			req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_OPEN_ID, OpenId: &protocol.AuthRequest_OpenID{Code: "allan"}},
+			err: true,
+		},
+		"missing-username-claim": {
+			cfg: &OpenIDConfig{UsernameClaim: "preferred_username"},
+			req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_OPEN_ID, OpenId: &protocol.AuthRequest_OpenID{Code: "bob"}},
+			err: true,
+		},
+		"not-matched-required-claim": {
+			cfg: &OpenIDConfig{RequiredClaims: map[string]string{"email_verified": "true"}},
+			req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_OPEN_ID, OpenId: &protocol.AuthRequest_OpenID{Code: "bob"}},
+			err: true,
+		},
+		"not-allowed-user": {
+			cfg: &OpenIDConfig{AllowUsers: []string{"allan@example.com"}},
+			req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_OPEN_ID, OpenId: &protocol.AuthRequest_OpenID{Code: "bob"}},
+			err: true,
+		},
+		"not-allowed-group": {
+			cfg: &OpenIDConfig{GroupsClaim: "groups", AllowGroups: []string{"vpn"}},
+			req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_OPEN_ID, OpenId: &protocol.AuthRequest_OpenID{Code: "bob"}},
+			err: true,
+		},
+		"invalid-id-token": {
+			cfg: &OpenIDConfig{},
+			req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_OPEN_ID, OpenId: &protocol.AuthRequest_OpenID{
+				IdToken: issuer.idToken("other-client", issuer.claims["bob"])}},
+			err: true,
+		},
+		"success-id-token": {
+			cfg: &OpenIDConfig{},
+			req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_OPEN_ID, OpenId: &protocol.AuthRequest_OpenID{
+				IdToken: issuer.idToken("client-id", issuer.claims["bob"])}},
+			user: "bob@example.com",
+		},
+		"success-email": {
+			cfg:  &OpenIDConfig{},
+			req:  &protocol.AuthRequest{AuthType: protocol.AuthType_AT_OPEN_ID, OpenId: &protocol.AuthRequest_OpenID{Code: "bob"}},
+			user: "bob@example.com",
+		},
+		"success-claims": {
+			cfg: &OpenIDConfig{UsernameClaim: "preferred_username", RequiredClaims: map[string]string{"email_verified": "true", "groups": "dev"},
+				GroupsClaim: "groups", AllowUsers: []string{"allan"}, AllowGroups: []string{"vpn"},
+				GroupMapping: map[string][]string{"developers": {"dev"}, "admins": {"admin"}}},
+			req:    &protocol.AuthRequest{AuthType: protocol.AuthType_AT_OPEN_ID, OpenId: &protocol.AuthRequest_OpenID{Code: "allan"}},
+			user:   "allan",
+			groups: []string{"developers"},
+		},
+		"success-unmapped-groups": {
+			cfg:  &OpenIDConfig{UsernameClaim: "preferred_username", GroupsClaim: "groups", AllowGroups: []string{"vpn"}},
+			req:  &protocol.AuthRequest{AuthType: protocol.AuthType_AT_OPEN_ID, OpenId: &protocol.AuthRequest_OpenID{Code: "allan"}},
+			user: "allan",
+		},
+	}
+
+	for _, t := range tests {
+		t.cfg.Issuer = issuer.URL
+		if t.cfg.ClientId == "" {
+			t.cfg.ClientId = "client-id"
+		}
+		method, ok := t.cfg.ServerAuth()
+		assert.True(ok)
+
+		var user, groups, attributes interface{}
+		_, err := method(context.Background(), t.req, nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+			user, groups, attributes = ctx.Value(UserCtxName), ctx.Value(GroupsCtxName), ctx.Value(AttributesCtxName)
+			return nil, nil
+		})
+		assert.Equal(t.err, err != nil)
+		if t.user != "" {
+			assert.Equal(t.user, user)
+			assert.Equal(t.groups, groups)
+			assert.Equal(map[string]string{"issuer": issuer.URL}, attributes)
+		}
+	}
+}
+
+func TestClaimStrings(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input  interface{}
+		output []string
+	}{
+		"nil":    {},
+		"string": {input: "vpn", output: []string{"vpn"}},
+		"bool":   {input: true, output: []string{"true"}},
+		"number": {input: float64(10), output: []string{"10"}},
+		"array":  {input: []interface{}{"vpn", "dev"}, output: []string{"vpn", "dev"}},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.output, claimStrings(t.input))
+	}
+}
@@ -0,0 +1,260 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// names of auth types in config.
+var authTypeNames = map[string]protocol.AuthType{
+	"test":          protocol.AuthType_AT_TEST,
+	"google_openid": protocol.AuthType_AT_GOOGLE_OPEN_ID,
+	"aws_iam":       protocol.AuthType_AT_AWS_IAM,
+	"ldap":          protocol.AuthType_AT_LDAP,
+	"openid":        protocol.AuthType_AT_OPEN_ID,
+	"mtls":          protocol.AuthType_AT_MTLS,
+}
+
+// ParseAuthType returns auth type of name(test, google_openid, aws_iam, ldap, openid, mtls).
+func ParseAuthType(name string) (protocol.AuthType, error) {
+	authType, ok := authTypeNames[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return protocol.AuthType_AT_NONE, errors.Wrapf(internal.ErrorInvalidParams, "Auth Type %s Method: ParseAuthType", name)
+	}
+	return authType, nil
+}
+
+// Policy is an explicit authentication policy of vpn server.
+// it decides which auth types are enabled, and which combinations of them authenticate user.
+type Policy struct {
+	methods map[protocol.AuthType]ServerAuthMethod // enabled auth methods
+	anyOf   [][]protocol.AuthType                  // user must pass all of auth types in any of combinations
+}
+
+// ServerAuth returns ServerAuthMethod which authenticates user with policy, and bool value(whether exist or not).
+func (p *Policy) ServerAuth() (ServerAuthMethod, bool) {
+	if p == nil {
+		return nil, false
+	}
+	return ServerAuthMethod(p.unaryServerInterceptor()), true
+}
+
+// AuthTypes returns enabled auth types.
+func (p *Policy) AuthTypes() []protocol.AuthType {
+	if p == nil {
+		return nil
+	}
+	var authTypes []protocol.AuthType
+	for authType := range p.methods {
+		authTypes = append(authTypes, authType)
+	}
+	sort.Slice(authTypes, func(i, j int) bool { return authTypes[i] < authTypes[j] })
+	return authTypes
+}
+
+// String returns combinations of policy, ex) any of [AT_LDAP & AT_AWS_IAM, AT_MTLS]
+func (p *Policy) String() string {
+	if p == nil {
+		return ""
+	}
+	var combinations []string
+	for _, combination := range p.anyOf {
+		var names []string
+		for _, authType := range combination {
+			names = append(names, authType.String())
+		}
+		combinations = append(combinations, strings.Join(names, " & "))
+	}
+	return fmt.Sprintf("any of [%s]", strings.Join(combinations, ", "))
+}
+
+// unaryServerInterceptor returns new unary server interceptor that authenticates user with combination of auth methods.
+// user of the first auth type in combination is vpn user, and groups, roles and attributes of all auth types are merged.
+func (p *Policy) unaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		auth, ok := req.(*protocol.AuthRequest)
+		if !ok {
+			return handler(ctx, req)
+		}
+		// second factor is verified by vpn server with challenge of primary authentication.
+		if auth.AuthType == protocol.AuthType_AT_MFA {
+			return handler(ctx, req)
+		}
+
+		combination, ok := p.match(auth)
+		if !ok {
+			return nil, internal.ErrorUnauthorized
+		}
+
+		var user, principal string
+		var groups, roles []string
+		attributes := map[string]string{}
+		for _, authType := range combination {
+			// each auth method checks its own credentials in request.
+			single := proto.Clone(auth).(*protocol.AuthRequest)
+			single.AuthType = authType
+			single.AuthTypes = nil
+
+			var authCtx context.Context
+			if _, err := p.methods[authType](ctx, single, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				authCtx = ctx
+				return nil, nil
+			}); err != nil {
+				return nil, err
+			}
+			if authCtx == nil {
+				return nil, internal.ErrorUnauthorized
+			}
+
+			methodUser, _ := authCtx.Value(UserCtxName).(string)
+			if methodUser == "" {
+				return nil, internal.ErrorUnauthorized
+			}
+			if user == "" {
+				user = methodUser
+			}
+			if principal == "" {
+				principal, _ = authCtx.Value(PrincipalCtxName).(string)
+			}
+			methodGroups, _ := authCtx.Value(GroupsCtxName).([]string)
+			groups = internal.MergeStrings(groups, methodGroups)
+			methodRoles, _ := authCtx.Value(RolesCtxName).([]string)
+			roles = internal.MergeStrings(roles, methodRoles)
+			methodAttributes, _ := authCtx.Value(AttributesCtxName).(map[string]string)
+			for name, value := range methodAttributes {
+				if _, ok := attributes[name]; !ok {
+					attributes[name] = value
+				}
+			}
+		}
+
+		// inject user
+		return handler(injectIdentity(ctx, user, principal, groups, roles, attributes), req)
+	}
+}
+
+// match returns the first combination whose auth types are all presented in request.
+func (p *Policy) match(req *protocol.AuthRequest) ([]protocol.AuthType, bool) {
+	presented := map[protocol.AuthType]bool{}
+	if len(req.AuthTypes) > 0 {
+		for _, authType := range req.AuthTypes {
+			presented[authType] = true
+		}
+	} else {
+		presented[req.AuthType] = true
+	}
+
+	for _, combination := range p.anyOf {
+		matched := true
+		for _, authType := range combination {
+			if !presented[authType] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return combination, true
+		}
+	}
+	return nil, false
+}
+
+// NewPolicy returns policy of enabled auth methods, user must pass all of auth types in any of combinations.
+// if anyOf is empty, each of enabled auth types authenticates user by itself.
+func NewPolicy(methods map[protocol.AuthType]ServerAuthMethod, anyOf [][]protocol.AuthType) (*Policy, error) {
+	if len(methods) == 0 {
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Empty Auth Methods Method: NewPolicy")
+	}
+
+	policy := &Policy{methods: map[protocol.AuthType]ServerAuthMethod{}}
+	for authType, method := range methods {
+		if method == nil || authType == protocol.AuthType_AT_NONE || authType == protocol.AuthType_AT_MFA {
+			return nil, errors.Wrapf(internal.ErrorInvalidParams, "Auth Type %s Method: NewPolicy", authType)
+		}
+		policy.methods[authType] = method
+	}
+
+	if len(anyOf) == 0 {
+		for _, authType := range policy.AuthTypes() {
+			policy.anyOf = append(policy.anyOf, []protocol.AuthType{authType})
+		}
+		return policy, nil
+	}
+
+	for _, combination := range anyOf {
+		if len(combination) == 0 {
+			return nil, errors.Wrapf(internal.ErrorInvalidParams, "Empty Combination Method: NewPolicy")
+		}
+		exist := map[protocol.AuthType]bool{}
+		for _, authType := range combination {
+			if _, ok := policy.methods[authType]; !ok {
+				return nil, errors.Wrapf(internal.ErrorInvalidParams, "Disabled Auth Type %s Method: NewPolicy", authType)
+			}
+			if exist[authType] {
+				return nil, errors.Wrapf(internal.ErrorInvalidParams, "Duplicated Auth Type %s Method: NewPolicy", authType)
+			}
+			exist[authType] = true
+		}
+		policy.anyOf = append(policy.anyOf, append([]protocol.AuthType{}, combination...))
+	}
+	return policy, nil
+}
+
+// authRequestCollector is a VPNClient which collects auth request of auth method instead of sending it.
+type authRequestCollector struct {
+	protocol.VPNClient
+	request *protocol.AuthRequest
+}
+
+// Auth collects auth request, and auth method regards it as success(its jwt is never used).
+func (c *authRequestCollector) Auth(ctx context.Context, in *protocol.AuthRequest, opts ...grpc.CallOption) (*protocol.AuthResponse, error) {
+	c.request = in
+	return &protocol.AuthResponse{ErrorCode: protocol.ErrorCode_EC_SUCCESS, Jwt: "collected"}, nil
+}
+
+// CombineClientAuth returns RefreshingClientAuthMethod which sends credentials of all auth methods in one request.
+// it's used when auth policy of vpn server requires all of auth types.
+func CombineClientAuth(methods ...RefreshingClientAuthMethod) RefreshingClientAuthMethod {
+	return func(conn protocol.VPNClient) (jwt string, refreshToken string, err error) {
+		if conn == nil || len(methods) == 0 {
+			return "", "", errors.Wrapf(internal.ErrorInvalidParams, "Combined ClientAuthMethod")
+		}
+
+		combined := &protocol.AuthRequest{}
+		for _, method := range methods {
+			collector := &authRequestCollector{}
+			if _, _, err := method(collector); err != nil {
+				return "", "", errors.Wrapf(err, "Combined ClientAuthMethod")
+			}
+			if collector.request == nil {
+				return "", "", errors.Wrapf(internal.ErrorInvalidParams, "Combined ClientAuthMethod")
+			}
+			authTypes := append(combined.AuthTypes, collector.request.AuthType)
+			proto.Merge(combined, collector.request)
+			combined.AuthTypes = authTypes
+		}
+		combined.AuthType = combined.AuthTypes[0]
+
+		// call authentication request to VPN server.
+		authCtx, authCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer authCancel()
+		response, err := conn.Auth(authCtx, combined)
+		if err != nil {
+			return "", "", errors.Wrapf(internal.ErrorUnauthorized, "Combined ClientAuthMethod")
+		}
+		if response.ErrorCode != protocol.ErrorCode_EC_SUCCESS || response.Jwt == "" {
+			return "", "", errors.Wrapf(internal.ErrorUnauthorized, "Combined ClientAuthMethod")
+		}
+
+		return response.Jwt, response.RefreshToken, nil
+	}
+}
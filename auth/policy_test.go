@@ -0,0 +1,195 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// testPolicyMethod returns auth method which authenticates user of auth type if password of ldap credentials is ok.
+func testPolicyMethod(authType protocol.AuthType, user, principal string, groups []string) ServerAuthMethod {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		auth := req.(*protocol.AuthRequest)
+		if auth.AuthType != authType {
+			return handler(ctx, req)
+		}
+		if auth.GetLdap().GetPassword() != "ok" {
+			return nil, internal.ErrorUnauthorized
+		}
+		newCtx := context.WithValue(ctx, UserCtxName, user)
+		if principal != "" {
+			newCtx = context.WithValue(newCtx, PrincipalCtxName, principal)
+		}
+		if len(groups) > 0 {
+			newCtx = context.WithValue(newCtx, GroupsCtxName, groups)
+		}
+		newCtx = context.WithValue(newCtx, AttributesCtxName, map[string]string{"auth_type": authType.String(), authType.String(): user})
+		return handler(newCtx, req)
+	}
+}
+
+func TestParseAuthType(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input  string
+		output protocol.AuthType
+		isErr  bool
+	}{
+		"unknown": {input: "kerberos", isErr: true},
+		"mfa":     {input: "mfa", isErr: true},
+		"ldap":    {input: "ldap", output: protocol.AuthType_AT_LDAP},
+		"upper":   {input: " AWS_IAM ", output: protocol.AuthType_AT_AWS_IAM},
+	}
+
+	for _, t := range tests {
+		authType, err := ParseAuthType(t.input)
+		assert.Equal(t.isErr, err != nil)
+		assert.Equal(t.output, authType)
+	}
+}
+
+func TestNewPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	ldap := testPolicyMethod(protocol.AuthType_AT_LDAP, "allan", "", nil)
+	aws := testPolicyMethod(protocol.AuthType_AT_AWS_IAM, "user/allan", "", nil)
+	methods := map[protocol.AuthType]ServerAuthMethod{protocol.AuthType_AT_LDAP: ldap, protocol.AuthType_AT_AWS_IAM: aws}
+
+	tests := map[string]struct {
+		methods map[protocol.AuthType]ServerAuthMethod
+		anyOf   [][]protocol.AuthType
+		output  string
+		isErr   bool
+	}{
+		"empty":       {isErr: true},
+		"nil-method":  {methods: map[protocol.AuthType]ServerAuthMethod{protocol.AuthType_AT_LDAP: nil}, isErr: true},
+		"mfa":         {methods: map[protocol.AuthType]ServerAuthMethod{protocol.AuthType_AT_MFA: ldap}, isErr: true},
+		"empty-combi": {methods: methods, anyOf: [][]protocol.AuthType{{}}, isErr: true},
+		"disabled":    {methods: methods, anyOf: [][]protocol.AuthType{{protocol.AuthType_AT_MTLS}}, isErr: true},
+		"duplicated":  {methods: methods, anyOf: [][]protocol.AuthType{{protocol.AuthType_AT_LDAP, protocol.AuthType_AT_LDAP}}, isErr: true},
+		"default":     {methods: methods, output: "any of [AT_AWS_IAM, AT_LDAP]"},
+		"all-of": {methods: methods, anyOf: [][]protocol.AuthType{{protocol.AuthType_AT_LDAP, protocol.AuthType_AT_AWS_IAM}},
+			output: "any of [AT_LDAP & AT_AWS_IAM]"},
+	}
+
+	for _, t := range tests {
+		policy, err := NewPolicy(t.methods, t.anyOf)
+		assert.Equal(t.isErr, err != nil)
+		assert.Equal(t.output, policy.String())
+	}
+
+	var nilPolicy *Policy
+	_, ok := nilPolicy.ServerAuth()
+	assert.False(ok)
+}
+
+func TestPolicy_ServerAuth(t *testing.T) {
+	assert := assert.New(t)
+
+	methods := map[protocol.AuthType]ServerAuthMethod{
+		protocol.AuthType_AT_LDAP:    testPolicyMethod(protocol.AuthType_AT_LDAP, "allan", "", []string{"dev", "ops"}),
+		protocol.AuthType_AT_AWS_IAM: testPolicyMethod(protocol.AuthType_AT_AWS_IAM, "user/allan", "arn:aws:iam::1:user/allan", []string{"ops", "aws"}),
+		protocol.AuthType_AT_MTLS:    testPolicyMethod(protocol.AuthType_AT_MTLS, "laptop", "", nil),
+	}
+	policy, err := NewPolicy(methods, [][]protocol.AuthType{
+		{protocol.AuthType_AT_LDAP, protocol.AuthType_AT_AWS_IAM},
+		{protocol.AuthType_AT_MTLS},
+	})
+	assert.NoError(err)
+	method, ok := policy.ServerAuth()
+	assert.True(ok)
+
+	tests := map[string]struct {
+		req       interface{}
+		called    bool
+		user      interface{}
+		principal interface{}
+		groups    interface{}
+		attrs     interface{}
+		isErr     bool
+	}{
+		"other-request": {req: &protocol.ListSessionsRequest{}, called: true},
+		"mfa":           {req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_MFA}, called: true},
+		"disabled":      {req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_TEST}, isErr: true},
+		"partial": {req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_LDAP,
+			Ldap: &protocol.AuthRequest_Ldap{Password: "ok"}}, isErr: true},
+		"one-of-all-fails": {req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_LDAP,
+			AuthTypes: []protocol.AuthType{protocol.AuthType_AT_LDAP, protocol.AuthType_AT_AWS_IAM}}, isErr: true},
+		"all-of": {req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_LDAP,
+			AuthTypes: []protocol.AuthType{protocol.AuthType_AT_LDAP, protocol.AuthType_AT_AWS_IAM},
+			Ldap:      &protocol.AuthRequest_Ldap{Password: "ok"}}, called: true,
+			user: "allan", principal: "arn:aws:iam::1:user/allan", groups: []string{"aws", "dev", "ops"},
+			attrs: map[string]string{"auth_type": "AT_LDAP", "AT_LDAP": "allan", "AT_AWS_IAM": "user/allan"}},
+		"any-of": {req: &protocol.AuthRequest{AuthType: protocol.AuthType_AT_MTLS,
+			Ldap: &protocol.AuthRequest_Ldap{Password: "ok"}}, called: true, user: "laptop",
+			attrs: map[string]string{"auth_type": "AT_MTLS", "AT_MTLS": "laptop"}},
+	}
+
+	for _, t := range tests {
+		var called bool
+		var user, principal, groups, attrs interface{}
+		_, err := method(context.Background(), t.req, nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+			called = true
+			user, principal, groups = ctx.Value(UserCtxName), ctx.Value(PrincipalCtxName), ctx.Value(GroupsCtxName)
+			attrs = ctx.Value(AttributesCtxName)
+			return nil, nil
+		})
+		assert.Equal(t.isErr, err != nil)
+		assert.Equal(t.called, called)
+		assert.Equal(t.user, user)
+		assert.Equal(t.principal, principal)
+		assert.Equal(t.groups, groups)
+		assert.Equal(t.attrs, attrs)
+	}
+}
+
+// fakeAuthServer records auth request, and returns jwt.
+type fakeAuthServer struct {
+	protocol.VPNClient
+	request *protocol.AuthRequest
+}
+
+func (f *fakeAuthServer) Auth(ctx context.Context, in *protocol.AuthRequest, opts ...grpc.CallOption) (*protocol.AuthResponse, error) {
+	f.request = in
+	return &protocol.AuthResponse{ErrorCode: protocol.ErrorCode_EC_SUCCESS, Jwt: "jwt", RefreshToken: "refresh"}, nil
+}
+
+func TestCombineClientAuth(t *testing.T) {
+	assert := assert.New(t)
+
+	ldap := (&LdapConfig{ClientUsername: "allan", ClientPassword: "ok"}).clientAuthMethod()
+	mtls := (&MTLSConfig{}).clientAuthMethod()
+	failed := RefreshingClientAuthMethod(func(conn protocol.VPNClient) (string, string, error) {
+		return "", "", internal.ErrorUnauthorized
+	})
+
+	tests := map[string]struct {
+		methods []RefreshingClientAuthMethod
+		output  *protocol.AuthRequest
+		isErr   bool
+	}{
+		"empty":  {isErr: true},
+		"failed": {methods: []RefreshingClientAuthMethod{ldap, failed}, isErr: true},
+		"success": {methods: []RefreshingClientAuthMethod{ldap, mtls}, output: &protocol.AuthRequest{
+			AuthType:  protocol.AuthType_AT_LDAP,
+			AuthTypes: []protocol.AuthType{protocol.AuthType_AT_LDAP, protocol.AuthType_AT_MTLS},
+			Ldap:      &protocol.AuthRequest_Ldap{Username: "allan", Password: "ok"},
+		}},
+	}
+
+	for _, t := range tests {
+		server := &fakeAuthServer{}
+		jwt, refreshToken, err := CombineClientAuth(t.methods...)(server)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Equal("jwt", jwt)
+			assert.Equal("refresh", refreshToken)
+			assert.Equal(t.output.String(), server.request.String())
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -22,10 +23,10 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/gjbae1212/grpc-vpn/auth"
-	"github.com/songgao/water/waterutil"
 	"google.golang.org/grpc/metadata"
 
 	"github.com/briandowns/spinner"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/gjbae1212/grpc-vpn/internal"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
@@ -38,11 +39,17 @@ import (
 
 const (
 	queueSize = 1000
+
+	renewJwtRetryInterval = time.Minute
+
+	// reconnection tries to resume session up to this count before network is rebuilt.
+	resumeRetryCount = 3
 )
 
 var (
 	defaultOptions = []Option{
 		WithGRPCInsecure(false),
+		WithMFAPrompt(auth.PromptTOTPCode),
 	}
 )
 
@@ -61,20 +68,30 @@ type VpnClient interface {
 type vpnClient struct {
 	cfg      *config
 	dialOpts []grpc.DialOption
-	auth     auth.ClientAuthMethod
-	jwt      string
+	auth     auth.RefreshingClientAuthMethod
+
+	jwt          string       // jwt
+	refreshToken string       // refresh token for renewing jwt
+	jwtLock      sync.RWMutex // jwt lock
+	renewed      chan bool    // response of jwt renewal is received
 
 	tun         *water.Interface
 	tunName     string
 	vpnMyIP     net.IP // vpn my ip
 	vpnSubnet   *net.IPNet
 	vpnGateway  net.IP       // vpn gateway
+	vpnMyIP6    net.IP       // vpn my ipv6 (dual stack)
+	vpnSubnet6  *net.IPNet   // vpn ipv6 subnet (dual stack)
+	vpnGateway6 net.IP       // vpn ipv6 gateway (dual stack)
 	networkLock sync.RWMutex // network lock
 
 	originServerIP   net.IP // origin vpn server ip
 	originGateway    net.IP // origin gateway
 	originDeviceName string // origin device name
 
+	includeRoutes []*net.IPNet // routes which always flow through vpn
+	excludeRoutes []*net.IPNet // routes which never flow through vpn
+
 	conn     protocol.VPNClient          // vpn connection
 	connPipe protocol.VPN_ExchangeClient // vpn connection read, write pipe
 	connLock sync.RWMutex                // conn lock
@@ -84,10 +101,14 @@ type vpnClient struct {
 
 	retryLock         sync.RWMutex // retry lock
 	lastConnectedTime time.Time    // last connected time
+	sessionID         string       // session id for resuming session
+	networkSet        bool         // whether tun device and routes are set or not
 
 	networkRollback *Rollback                   // network rollback
 	backoff         *backoff.ExponentialBackOff // backoff
 	exit            chan bool                   // exit channel
+	done            chan struct{}               // closed when client is closed
+	closeOnce       sync.Once                   // close once
 }
 
 func (vc *vpnClient) Run() error {
@@ -114,15 +135,15 @@ func (vc *vpnClient) Run() error {
 		return errors.Wrapf(err, "Method: Run")
 	}
 
-	// authorization
-	jwt, err := vc.auth(vc.conn)
+	// authorization(totp code is prompted if vpn server requires second factor)
+	token, refreshToken, err := vc.auth(auth.NewMFAClient(vc.conn, vc.cfg.mfaPrompt))
 	if err != nil {
 		return errors.Wrapf(err, "Method: Run")
 	}
-	vc.jwt = jwt
+	vc.setJwt(token, refreshToken)
 
 	// connect VPN
-	vc.vpnConnect(jwt)
+	vc.vpnConnect(token)
 
 	// Read TUN
 	go vc.readTun()
@@ -132,6 +153,8 @@ func (vc *vpnClient) Run() error {
 	go vc.readToGRPC()
 	// write packet to grpc connection
 	go vc.writeToGRPC()
+	// renew jwt before it's expired
+	go vc.renewJwt()
 
 	s.Stop()
 
@@ -144,19 +167,75 @@ func (vc *vpnClient) Run() error {
 }
 
 func (vc *vpnClient) Close() error {
+	vc.closeOnce.Do(func() {
+		close(vc.done)
+	})
 	vc.networkRollback.Close()
-	if runtime.GOOS == "darwin" {
-		internal.SetDeleteDNS()
-	}
 	defaultLogger.Error(color.RedString("[EXIT] BYE"))
 	return nil
 }
 
 // JWT returns jwt string
 func (vc *vpnClient) JWT() string {
+	vc.jwtLock.RLock()
+	defer vc.jwtLock.RUnlock()
 	return vc.jwt
 }
 
+// getRefreshToken returns refresh token.
+func (vc *vpnClient) getRefreshToken() string {
+	vc.jwtLock.RLock()
+	defer vc.jwtLock.RUnlock()
+	return vc.refreshToken
+}
+
+// setJwt replaces jwt and refresh token.
+func (vc *vpnClient) setJwt(token, refreshToken string) {
+	vc.jwtLock.Lock()
+	defer vc.jwtLock.Unlock()
+	vc.jwt = token
+	vc.refreshToken = refreshToken
+}
+
+// renewJwt requests new jwt with refresh token on the connection before jwt is expired.
+// if the server doesn't issue refresh token, jwt isn't renewed. it stops when client is closed.
+func (vc *vpnClient) renewJwt() {
+	for vc.getRefreshToken() != "" {
+		select {
+		case <-time.After(renewJwtAfter(vc.JWT(), time.Now())):
+		case <-vc.done:
+			return
+		}
+
+		select {
+		case vc.out <- &protocol.IPPacket{
+			ErrorCode:  protocol.ErrorCode_EC_SUCCESS,
+			PacketType: protocol.IPPacketType_IPPT_RENEW_JWT,
+			Packet3:    &protocol.IPPacket_Renew{RefreshToken: vc.getRefreshToken()},
+		}:
+		case <-vc.done:
+			return
+		}
+
+		// wait for response before calculating next renewal, request is sent again if response is lost.
+		select {
+		case <-vc.renewed:
+		case <-time.After(renewJwtRetryInterval):
+		case <-vc.done:
+			return
+		}
+	}
+	defaultLogger.Warn(color.YellowString("[WARNING] refresh token doesn't exist, jwt isn't renewed"))
+}
+
+// notifyRenewed signals renewJwt that response of jwt renewal is received.
+func (vc *vpnClient) notifyRenewed() {
+	select {
+	case vc.renewed <- true:
+	default:
+	}
+}
+
 // MyVpnIP returns my vpn ip.
 func (vc *vpnClient) MyVpnIp() string {
 	vc.networkLock.RLock()
@@ -167,8 +246,22 @@ func (vc *vpnClient) MyVpnIp() string {
 	return ""
 }
 
+// isMyVpnIP checks whether ip is one of my vpn ips.
+func (vc *vpnClient) isMyVpnIP(ip net.IP) bool {
+	vc.networkLock.RLock()
+	defer vc.networkLock.RUnlock()
+	if ip == nil {
+		return false
+	}
+	return vc.vpnMyIP.Equal(ip) || vc.vpnMyIP6.Equal(ip)
+}
+
 func (vc *vpnClient) vpnConnect(jwt string) error {
-	ctx := metadata.NewOutgoingContext(context.Background(), auth.JWTAuthHeaderForGRPC(jwt))
+	md := auth.JWTAuthHeaderForGRPC(jwt)
+	if vc.sessionID != "" {
+		md.Set(auth.SessionIDHeader, vc.sessionID)
+	}
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
 	sock, err := vc.conn.Exchange(ctx)
 	if err != nil {
 		return errors.Wrapf(err, "Method: connect")
@@ -191,17 +284,26 @@ func (vc *vpnClient) vpnConnect(jwt string) error {
 		return errors.Wrapf(internal.ErrorReceiveUnknownPacket, "Method: connect")
 	}
 
-	// assign VPN IP
-	vpnIP := net.IP(packet.Packet2.VpnAssignedIp)
-	vpnGateway := net.IP(packet.Packet2.VpnGateway)
-	vpnSubnet := &net.IPNet{
-		IP:   net.IP(packet.Packet2.VpnSubnetIp),
-		Mask: net.IPMask(packet.Packet2.VpnSubnetMask),
+	vc.sessionID = packet.Packet2.SessionId
+	vc.lastConnectedTime = time.Now()
+
+	// resumed session keeps same vpn ips, so tun device and routes are reused.
+	if packet.Packet2.Resumed && vc.networkSet {
+		defaultLogger.Info(color.GreenString("[resume] session on tun device %s", vc.tunName))
+		return nil
 	}
-	if err := vc.setVPN(vpnIP, vpnGateway, vpnSubnet); err != nil {
+
+	// rebuild network for new session.
+	if vc.networkSet {
+		vc.networkRollback.Close()
+		vc.networkSet = false
+	}
+
+	// assign VPN IP
+	if err := vc.setVPN(packet.Packet2); err != nil {
 		return errors.Wrapf(internal.ErrorReceiveUnknownPacket, "Method: connect")
 	}
-	vc.lastConnectedTime = time.Now()
+	vc.networkSet = true
 	return nil
 }
 
@@ -213,8 +315,13 @@ func (vc *vpnClient) retryVpnConnect() error {
 		return nil
 	}
 
-	vc.networkRollback.Close()
 	for i := 0; i < 10; i++ {
+		// network is kept to resume session, but it's rebuilt if session can't be resumed for a while.
+		if vc.networkSet && (vc.sessionID == "" || i == resumeRetryCount) {
+			vc.networkRollback.Close()
+			vc.networkSet = false
+		}
+
 		defaultLogger.Warn(color.YellowString("[RETRY] vpn connect %d", i+1))
 		time.Sleep(vc.backoff.NextBackOff())
 		// connect GRPC
@@ -223,7 +330,7 @@ func (vc *vpnClient) retryVpnConnect() error {
 			continue
 		}
 
-		if err := vc.vpnConnect(vc.jwt); err != nil {
+		if err := vc.vpnConnect(vc.JWT()); err != nil {
 			defaultLogger.Error(color.RedString(err.Error()))
 			continue
 		}
@@ -236,13 +343,33 @@ func (vc *vpnClient) retryVpnConnect() error {
 	return fmt.Errorf("[FAIL] FAIL RETRY")
 }
 
-func (vc *vpnClient) setVPN(vpnIP, vpnGateway net.IP, vpnSubnet *net.IPNet) error {
+func (vc *vpnClient) setVPN(assign *protocol.IPPacket_Vpn) error {
 	vc.networkLock.Lock()
 	defer vc.networkLock.Unlock()
 
-	vc.vpnMyIP = vpnIP
-	vc.vpnGateway = vpnGateway
-	vc.vpnSubnet = vpnSubnet
+	vc.vpnMyIP, vc.vpnGateway, vc.vpnSubnet = nil, nil, nil
+	if len(assign.VpnAssignedIp) != 0 {
+		vc.vpnMyIP = net.IP(assign.VpnAssignedIp)
+		vc.vpnGateway = net.IP(assign.VpnGateway)
+		vc.vpnSubnet = &net.IPNet{
+			IP:   net.IP(assign.VpnSubnetIp),
+			Mask: net.IPMask(assign.VpnSubnetMask),
+		}
+	}
+
+	vc.vpnMyIP6, vc.vpnGateway6, vc.vpnSubnet6 = nil, nil, nil
+	if len(assign.VpnAssignedIp6) != 0 {
+		vc.vpnMyIP6 = net.IP(assign.VpnAssignedIp6)
+		vc.vpnGateway6 = net.IP(assign.VpnGateway6)
+		vc.vpnSubnet6 = &net.IPNet{
+			IP:   net.IP(assign.VpnSubnetIp6),
+			Mask: net.IPMask(assign.VpnSubnetMask6),
+		}
+	}
+
+	if vc.vpnMyIP == nil && vc.vpnMyIP6 == nil {
+		return errors.Wrapf(internal.ErrorInvalidParams, "Method: setVPN")
+	}
 
 	// make tun device
 	tun, err := water.New(water.Config{DeviceType: water.TUN})
@@ -253,16 +380,30 @@ func (vc *vpnClient) setVPN(vpnIP, vpnGateway net.IP, vpnSubnet *net.IPNet) erro
 	vc.tunName = tun.Name()
 	defaultLogger.Info(color.GreenString("[create] tun device %s", tun.Name()))
 
+	// split tunneling, if server pushes routes without full tunnel.
+	fullTunnel := assign.FullTunnel || len(assign.Routes) == 0
+	routes, err := parseRoutes(assign.Routes)
+	if err != nil {
+		return errors.Wrapf(err, "Method: setVPN")
+	}
+
 	if runtime.GOOS == "darwin" {
 		// write reset gateway, if vpn client is closed.
-		vc.networkRollback.ResetGatewayOSX(vc.tun, vc.originGateway.String())
-		// write dns 8.8.8.8 Wi-Fi device
-		internal.SetGoogleDNS()
+		if fullTunnel {
+			vc.networkRollback.ResetGatewayOSX(vc.tun, vc.originGateway.String())
+		}
 	}
 
 	// set ip to tun.
-	if err := internal.SetTunIP(vc.tunName, vc.vpnMyIP, vc.vpnSubnet); err != nil {
-		return errors.Wrapf(err, "Method: setVPN")
+	if vc.vpnMyIP != nil {
+		if err := internal.SetTunIP(vc.tunName, vc.vpnMyIP, vc.vpnSubnet); err != nil {
+			return errors.Wrapf(err, "Method: setVPN")
+		}
+	}
+	if vc.vpnMyIP6 != nil {
+		if err := internal.SetTunIP6(vc.tunName, vc.vpnMyIP6, vc.vpnSubnet6); err != nil {
+			return errors.Wrapf(err, "Method: setVPN")
+		}
 	}
 
 	// route all traffic to the VPN server(real ip) through the current gateway device
@@ -270,16 +411,61 @@ func (vc *vpnClient) setVPN(vpnIP, vpnGateway net.IP, vpnSubnet *net.IPNet) erro
 		return errors.Wrapf(err, "Method: setVPN")
 	}
 
-	// redirect default traffic via our VPN
-	if err := internal.SetDefaultGateway(vc.vpnGateway.String(), vc.tun.Name()); err != nil {
-		return errors.Wrapf(err, "Method: setVPN")
-	}
-
 	// tun up
 	if err := internal.SetTunStatus(vc.tun.Name(), true); err != nil {
 		return fmt.Errorf("[err] Run %w", err)
 	}
 
+	if fullTunnel {
+		// redirect default traffic via our VPN
+		if vc.vpnGateway != nil {
+			if err := internal.SetDefaultGateway(vc.vpnGateway.String(), vc.tun.Name()); err != nil {
+				return errors.Wrapf(err, "Method: setVPN")
+			}
+		}
+		if vc.vpnGateway6 != nil {
+			if err := internal.SetDefaultGateway6(vc.vpnGateway6.String(), vc.tun.Name()); err != nil {
+				return errors.Wrapf(err, "Method: setVPN")
+			}
+		}
+	} else {
+		// redirect only pushed routes via our VPN
+		for _, route := range routes {
+			if err := internal.AddTunRoute(route, vc.tun.Name()); err != nil {
+				return errors.Wrapf(err, "Method: setVPN")
+			}
+			vc.networkRollback.AddTunRoute(route, vc.tun.Name())
+		}
+	}
+
+	// apply client routes after server assignment.
+	for _, route := range vc.includeRoutes {
+		if err := internal.AddTunRoute(route, vc.tun.Name()); err != nil {
+			return errors.Wrapf(err, "Method: setVPN")
+		}
+		vc.networkRollback.AddTunRoute(route, vc.tun.Name())
+	}
+	for _, route := range vc.excludeRoutes {
+		if err := internal.AddNetRoute(route, vc.originGateway, vc.originDeviceName); err != nil {
+			return errors.Wrapf(err, "Method: setVPN")
+		}
+		vc.networkRollback.AddNetRoute(route, vc.originGateway, vc.originDeviceName)
+	}
+
+	// apply dns pushed from server.
+	dnsServers := assign.DnsServers
+	if len(dnsServers) == 0 && fullTunnel && runtime.GOOS == "darwin" {
+		dnsServers = []string{"8.8.8.8"}
+	}
+	if len(dnsServers) > 0 {
+		backup, err := internal.SetDNS(vc.originDeviceName, dnsServers, assign.DnsSearchDomains)
+		if err != nil {
+			return errors.Wrapf(err, "Method: setVPN")
+		}
+		vc.networkRollback.RestoreDNS(backup)
+		defaultLogger.Info(color.GreenString("[dns] %s", strings.Join(dnsServers, ", ")))
+	}
+
 	return nil
 
 }
@@ -344,9 +530,9 @@ func (vc *vpnClient) readTun() {
 		}
 
 		raw := packet[:n]
-		dest := waterutil.IPv4Destination(raw)
-		// bypass multicast
-		if dest.IsMulticast() {
+		dest := internal.PacketDestination(raw)
+		// bypass invalid packet and multicast
+		if dest == nil || dest.IsMulticast() {
 			continue
 		}
 
@@ -425,6 +611,19 @@ ReadGRPC:
 			}
 		}
 
+		// replace jwt with renewed one.
+		if packet.PacketType == protocol.IPPacketType_IPPT_RENEW_JWT {
+			if packet.ErrorCode != protocol.ErrorCode_EC_SUCCESS || packet.Packet3 == nil {
+				defaultLogger.Error(color.RedString("[ERR] readToGRPC JWT Renewal Failed"))
+				vc.notifyRenewed()
+				continue
+			}
+			vc.setJwt(packet.Packet3.Jwt, packet.Packet3.RefreshToken)
+			defaultLogger.Info(color.GreenString("[RENEW] JWT"))
+			vc.notifyRenewed()
+			continue
+		}
+
 		// exit when jwt is expired.
 		if packet.ErrorCode == protocol.ErrorCode_EC_EXPIRED_JWT {
 			defaultLogger.Error(color.RedString("[ERR] readToGRPC JWT Expired"))
@@ -432,6 +631,13 @@ ReadGRPC:
 			break ReadGRPC
 		}
 
+		// exit when jwt is revoked.
+		if packet.ErrorCode == protocol.ErrorCode_EC_REVOKED_JWT {
+			defaultLogger.Error(color.RedString("[ERR] readToGRPC JWT Revoked"))
+			vc.exit <- true
+			break ReadGRPC
+		}
+
 		if packet.ErrorCode != protocol.ErrorCode_EC_SUCCESS {
 			defaultLogger.Error(color.RedString("[ERR] readToGRPC %s", internal.ErrorReceiveUnknownPacket.Error()))
 			continue
@@ -443,8 +649,8 @@ ReadGRPC:
 		}
 
 		// mismatched VPN IP.
-		dest := waterutil.IPv4Destination(packet.Packet1.Raw)
-		if !vc.vpnMyIP.Equal(dest) {
+		dest := internal.PacketDestination(packet.Packet1.Raw)
+		if !vc.isMyVpnIP(dest) {
 			defaultLogger.Error(color.RedString("[ERR] readToGRPC %s", internal.ErrorMismatchVpnIP.Error()))
 			continue
 		}
@@ -480,8 +686,8 @@ func NewVpnClient(opts ...Option) (VpnClient, error) {
 
 	// default auth method(test)
 	authManager, _ := auth.NewClientManagerForTest()
-	authMethod, _ := authManager.ClientAuth()
-	tmpOpts = append(tmpOpts, WithAuthMethod(authMethod))
+	authMethod, _ := authManager.(auth.RefreshingClientManager).RefreshingClientAuth()
+	tmpOpts = append(tmpOpts, WithRefreshingAuthMethod(authMethod))
 
 	// merge custom options
 	tmpOpts = append(tmpOpts, opts...)
@@ -495,6 +701,22 @@ func NewVpnClient(opts ...Option) (VpnClient, error) {
 		return nil, errors.Wrapf(err, "Method: NewVpnClient")
 	}
 
+	// parse client routes
+	includeRoutes, err := parseRoutes(cfg.includeRoutes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: NewVpnClient")
+	}
+	excludeRoutes, err := parseRoutes(cfg.excludeRoutes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: NewVpnClient")
+	}
+	for _, route := range excludeRoutes {
+		// origin gateway is IPv4.
+		if route.IP.To4() == nil {
+			return nil, errors.Wrapf(internal.ErrorInvalidParams, "Exclude Route %s Method: NewVpnClient", route)
+		}
+	}
+
 	// make dial options
 	dialOpts := []grpc.DialOption{
 		grpc.WithKeepaliveParams(keepalive.ClientParameters{
@@ -504,6 +726,19 @@ func NewVpnClient(opts ...Option) (VpnClient, error) {
 	}
 
 	// apply to tls settings.
+	var certificates []tls.Certificate
+	if cfg.clientCertification != "" || cfg.clientPem != "" {
+		// client certificate can't be sent without tls.
+		if cfg.grpcInsecure {
+			return nil, errors.Wrapf(internal.ErrorInvalidParams, "Client Certification With Insecure Method: NewVpnClient")
+		}
+		certificate, err := tls.X509KeyPair([]byte(cfg.clientCertification), []byte(cfg.clientPem))
+		if err != nil {
+			return nil, errors.Wrapf(internal.ErrorInvalidParams, "Client Certification Invalid Method: NewVpnClient")
+		}
+		certificates = append(certificates, certificate)
+	}
+
 	if cfg.grpcInsecure {
 		dialOpts = append(dialOpts, grpc.WithInsecure())
 	} else {
@@ -518,9 +753,11 @@ func NewVpnClient(opts ...Option) (VpnClient, error) {
 				insecureSkipVerify = true
 			}
 			dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(
-				&tls.Config{RootCAs: roots, ServerName: cfg.serverAddr, InsecureSkipVerify: insecureSkipVerify})))
+				&tls.Config{RootCAs: roots, ServerName: cfg.serverAddr, InsecureSkipVerify: insecureSkipVerify,
+					Certificates: certificates})))
 		} else {
-			dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: false})))
+			dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: false,
+				Certificates: certificates})))
 		}
 	}
 
@@ -529,14 +766,46 @@ func NewVpnClient(opts ...Option) (VpnClient, error) {
 		dialOpts:        dialOpts,
 		auth:            cfg.authMethod,
 		originServerIP:  originServerIP,
+		includeRoutes:   includeRoutes,
+		excludeRoutes:   excludeRoutes,
 		networkRollback: &Rollback{},
 		in:              make(chan *protocol.IPPacket, queueSize),
 		out:             make(chan *protocol.IPPacket, queueSize),
 		backoff:         backoff.NewExponentialBackOff(),
 		exit:            make(chan bool, 1),
+		done:            make(chan struct{}),
+		renewed:         make(chan bool, 1),
 	}, nil
 }
 
+// renewJwtAfter returns how long to wait before renewing jwt, jwt is renewed when 4/5 of lifetime is passed.
+func renewJwtAfter(token string, now time.Time) time.Duration {
+	claims := &jwt.StandardClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(token, claims); err != nil || claims.ExpiresAt <= claims.IssuedAt {
+		return renewJwtRetryInterval
+	}
+
+	lifetime := claims.ExpiresAt - claims.IssuedAt
+	after := time.Unix(claims.IssuedAt+lifetime*4/5, 0).Sub(now)
+	if after < renewJwtRetryInterval {
+		return renewJwtRetryInterval
+	}
+	return after
+}
+
+// parseRoutes parses routes(cidr).
+func parseRoutes(routes []string) ([]*net.IPNet, error) {
+	var subnets []*net.IPNet
+	for _, route := range routes {
+		_, subnet, err := net.ParseCIDR(route)
+		if err != nil {
+			return nil, err
+		}
+		subnets = append(subnets, subnet)
+	}
+	return subnets, nil
+}
+
 // SetDefaultLogger is to set logger for vpn client.
 func SetDefaultLogger(logger *logrus.Logger) {
 	defaultLogger = logger
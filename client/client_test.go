@@ -1,7 +1,13 @@
 package client
 
 import (
+	"net"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
 	"github.com/gjbae1212/grpc-vpn/auth"
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal"
 	"github.com/sirupsen/logrus"
 	"testing"
 
@@ -10,6 +16,7 @@ import (
 
 func TestSetDefaultLogger(t *testing.T) {
 	assert := assert.New(t)
+	defer SetDefaultLogger(defaultLogger)
 	tests := map[string]struct {
 		input *logrus.Logger
 	}{
@@ -26,10 +33,10 @@ func TestNewVpnClient(t *testing.T) {
 	assert := assert.New(t)
 
 	testAuth, _ := auth.NewClientManagerForTest()
-	testAuthMethod, _ := testAuth.ClientAuth()
+	testAuthMethod, _ := testAuth.(auth.RefreshingClientManager).RefreshingClientAuth()
 
 	googleAuth, _ := auth.NewClientManagerForGoogleOpenID("a", "a")
-	googleAuthMethod, _ := googleAuth.ClientAuth()
+	googleAuthMethod, _ := googleAuth.(auth.RefreshingClientManager).RefreshingClientAuth()
 
 	tests := map[string]struct {
 		opts  []Option
@@ -52,7 +59,7 @@ func TestNewVpnClient(t *testing.T) {
 			opts: []Option{
 				WithServerAddr("1.1.1.1"),
 				WithServerPort("80"),
-				WithAuthMethod(googleAuthMethod),
+				WithRefreshingAuthMethod(googleAuthMethod),
 			},
 			check: &config{
 				serverAddr:   "1.1.1.1",
@@ -71,3 +78,149 @@ func TestNewVpnClient(t *testing.T) {
 		assert.Equal(t.check.grpcInsecure, v.(*vpnClient).cfg.grpcInsecure)
 	}
 }
+
+func TestNewVpnClient_ClientCertificate(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		opts []Option
+	}{
+		"insecure": {
+			opts: []Option{
+				WithServerAddr("1.1.1.1"),
+				WithServerPort("80"),
+				WithGRPCInsecure(true),
+				WithClientCertificate("cert", "pem"),
+			},
+		},
+		"invalid": {
+			opts: []Option{
+				WithServerAddr("1.1.1.1"),
+				WithServerPort("80"),
+				WithClientCertificate("cert", "pem"),
+			},
+		},
+	}
+
+	for _, t := range tests {
+		_, err := NewVpnClient(t.opts...)
+		assert.Error(err)
+	}
+}
+
+func TestVpnClient_isMyVpnIP(t *testing.T) {
+	assert := assert.New(t)
+
+	vc := &vpnClient{vpnMyIP: net.ParseIP("10.10.10.2").To4(), vpnMyIP6: net.ParseIP("fd00:10::2")}
+	tests := map[string]struct {
+		input net.IP
+		ok    bool
+	}{
+		"empty": {},
+		"other": {input: net.ParseIP("10.10.10.3")},
+		"ipv4":  {input: net.ParseIP("10.10.10.2"), ok: true},
+		"ipv6":  {input: net.ParseIP("fd00:10::2"), ok: true},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.ok, vc.isMyVpnIP(t.input))
+	}
+}
+
+func TestNewVpnClient_Routes(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		include []string
+		exclude []string
+		isErr   bool
+	}{
+		"invalid-include": {include: []string{"allan"}, isErr: true},
+		"invalid-exclude": {exclude: []string{"allan"}, isErr: true},
+		"ipv6-exclude":    {exclude: []string{"fd00::/8"}, isErr: true},
+		"success":         {include: []string{"172.16.0.0/16", "fd00::/8"}, exclude: []string{"192.168.0.0/24"}},
+	}
+
+	for _, t := range tests {
+		v, err := NewVpnClient(WithServerAddr("1.1.1.1"), WithServerPort("80"),
+			WithIncludeRoutes(t.include), WithExcludeRoutes(t.exclude))
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Len(v.(*vpnClient).includeRoutes, len(t.include))
+			assert.Len(v.(*vpnClient).excludeRoutes, len(t.exclude))
+		}
+	}
+}
+
+func TestRenewJwtAfter(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	encode := func(issuedAt time.Time, lifetime time.Duration) string {
+		token, err := internal.EncodeJWT(&internal.VpnClaims{StandardClaims: jwt.StandardClaims{
+			IssuedAt:  issuedAt.Unix(),
+			ExpiresAt: issuedAt.Add(lifetime).Unix(),
+		}}, []byte("salt"))
+		assert.NoError(err)
+		return token
+	}
+
+	tests := map[string]struct {
+		token  string
+		output time.Duration
+	}{
+		"invalid":      {token: "invalid", output: renewJwtRetryInterval},
+		"issued":       {token: encode(now, 10*time.Hour), output: 8 * time.Hour},
+		"half":         {token: encode(now.Add(-5*time.Hour), 10*time.Hour), output: 3 * time.Hour},
+		"need-renewal": {token: encode(now.Add(-9*time.Hour), 10*time.Hour), output: renewJwtRetryInterval},
+		"expired":      {token: encode(now.Add(-11*time.Hour), 10*time.Hour), output: renewJwtRetryInterval},
+	}
+
+	for _, t := range tests {
+		assert.InDelta(t.output.Seconds(), renewJwtAfter(t.token, now).Seconds(), 1)
+	}
+}
+
+func TestVpnClient_renewJwt(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		refreshToken string
+		close        bool
+	}{
+		"without-refresh-token": {},
+		"closed":                {refreshToken: "refresh", close: true},
+	}
+
+	for _, t := range tests {
+		v, err := NewVpnClient(WithServerAddr("1.1.1.1"), WithServerPort("80"))
+		assert.NoError(err)
+		vc := v.(*vpnClient)
+		vc.out = make(chan *protocol.IPPacket) // nobody reads out queue
+		vc.setJwt("invalid", t.refreshToken)
+		if t.close {
+			assert.NoError(vc.Close())
+		}
+
+		stopped := make(chan bool, 1)
+		go func() {
+			vc.renewJwt()
+			stopped <- true
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			assert.Fail("renewJwt isn't stopped")
+		}
+		// close is called several times.
+		assert.NoError(vc.Close())
+	}
+
+	// signal of renewal response doesn't block.
+	v, err := NewVpnClient(WithServerAddr("1.1.1.1"), WithServerPort("80"))
+	assert.NoError(err)
+	vc := v.(*vpnClient)
+	vc.notifyRenewed()
+	vc.notifyRenewed()
+	assert.Len(vc.renewed, 1)
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"github.com/gjbae1212/grpc-vpn/auth"
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
 )
 
 // Option is to use a dependency injection for handler.
@@ -14,7 +15,12 @@ type config struct {
 	serverPort              string
 	grpcInsecure            bool
 	selfSignedCertification string
-	authMethod              auth.ClientAuthMethod
+	clientCertification     string
+	clientPem               string
+	authMethod              auth.RefreshingClientAuthMethod
+	mfaPrompt               auth.MFAPrompt
+	includeRoutes           []string
+	excludeRoutes           []string
 }
 
 // OptionFunc is a function for Option interface.
@@ -37,12 +43,35 @@ func WithServerPort(port string) OptionFunc {
 }
 
 // WithAuthMethod returns OptionFunc for inserting auth method.
+// jwt isn't renewed, because auth method doesn't return refresh token.
 func WithAuthMethod(f auth.ClientAuthMethod) OptionFunc {
+	return func(c *config) {
+		if f == nil {
+			c.authMethod = nil
+			return
+		}
+		c.authMethod = func(conn protocol.VPNClient) (string, string, error) {
+			jwt, err := f(conn)
+			return jwt, "", err
+		}
+	}
+}
+
+// WithRefreshingAuthMethod returns OptionFunc for inserting auth method which returns refresh token too.
+// jwt is renewed with refresh token before it's expired.
+func WithRefreshingAuthMethod(f auth.RefreshingClientAuthMethod) OptionFunc {
 	return func(c *config) {
 		c.authMethod = f
 	}
 }
 
+// WithMFAPrompt returns OptionFunc for inserting prompt which reads totp code when vpn server requires second factor.
+func WithMFAPrompt(prompt auth.MFAPrompt) OptionFunc {
+	return func(c *config) {
+		c.mfaPrompt = prompt
+	}
+}
+
 // WithGRPCInsecure returns OptionFunc for inserting grpc insecure.
 func WithGRPCInsecure(b bool) OptionFunc {
 	return func(c *config) {
@@ -56,3 +85,25 @@ func WithSelfSignedCertification(cert string) OptionFunc {
 		c.selfSignedCertification = cert
 	}
 }
+
+// WithClientCertificate returns OptionFunc for inserting client certificate and private key(PEM) for mutual tls.
+func WithClientCertificate(cert, pem string) OptionFunc {
+	return func(c *config) {
+		c.clientCertification = cert
+		c.clientPem = pem
+	}
+}
+
+// WithIncludeRoutes returns OptionFunc for inserting routes(cidr) which always flow through VPN.
+func WithIncludeRoutes(routes []string) OptionFunc {
+	return func(c *config) {
+		c.includeRoutes = routes
+	}
+}
+
+// WithExcludeRoutes returns OptionFunc for inserting routes(cidr) which never flow through VPN(only IPv4).
+func WithExcludeRoutes(routes []string) OptionFunc {
+	return func(c *config) {
+		c.excludeRoutes = routes
+	}
+}
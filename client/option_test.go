@@ -58,8 +58,41 @@ func TestWithAuthMethod(t *testing.T) {
 	}
 
 	tests := map[string]struct {
-		input  auth.ClientAuthMethod
-		output auth.ClientAuthMethod
+		input auth.ClientAuthMethod
+		isNil bool
+	}{
+		"nil": {
+			isNil: true,
+		},
+		"success": {
+			input: temp,
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithAuthMethod(t.input)
+		f(c)
+		assert.Equal(t.isNil, c.authMethod == nil)
+		if c.authMethod != nil {
+			a, _ := t.input(nil)
+			b, refreshToken, _ := c.authMethod(nil)
+			assert.Equal(a, b)
+			assert.Empty(refreshToken)
+		}
+	}
+}
+
+func TestWithRefreshingAuthMethod(t *testing.T) {
+	assert := assert.New(t)
+
+	temp := func(conn protocol.VPNClient) (jwt string, refreshToken string, err error) {
+		return "allan", "refresh", nil
+	}
+
+	tests := map[string]struct {
+		input  auth.RefreshingClientAuthMethod
+		output auth.RefreshingClientAuthMethod
 	}{
 		"success": {
 			input:  temp,
@@ -69,12 +102,35 @@ func TestWithAuthMethod(t *testing.T) {
 
 	for _, t := range tests {
 		c := &config{}
-		f := WithAuthMethod(t.input)
+		f := WithRefreshingAuthMethod(t.input)
 		f(c)
-		a, _ := t.output(nil)
-		b, _ := c.authMethod(nil)
-		assert.Equal(a, b)
+		a, b, _ := t.output(nil)
+		c1, c2, _ := c.authMethod(nil)
+		assert.Equal(a, c1)
+		assert.Equal(b, c2)
+	}
+}
 
+func TestWithMFAPrompt(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input  auth.MFAPrompt
+		output string
+	}{
+		"success": {
+			input:  func() (string, error) { return "123456", nil },
+			output: "123456",
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithMFAPrompt(t.input)
+		f(c)
+		code, err := c.mfaPrompt()
+		assert.NoError(err)
+		assert.Equal(t.output, code)
 	}
 }
 
@@ -99,6 +155,28 @@ func TestWithSelfSignedCertification(t *testing.T) {
 	}
 }
 
+func TestWithClientCertificate(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		cert string
+		pem  string
+	}{
+		"success": {
+			cert: "cert",
+			pem:  "pem",
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithClientCertificate(t.cert, t.pem)
+		f(c)
+		assert.Equal(t.cert, c.clientCertification)
+		assert.Equal(t.pem, c.clientPem)
+	}
+}
+
 func TestWithGRPCInsecure(t *testing.T) {
 	assert := assert.New(t)
 
@@ -119,3 +197,45 @@ func TestWithGRPCInsecure(t *testing.T) {
 		assert.Equal(t.output, c.grpcInsecure)
 	}
 }
+
+func TestWithIncludeRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input  []string
+		output []string
+	}{
+		"success": {
+			input:  []string{"172.16.0.0/16"},
+			output: []string{"172.16.0.0/16"},
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithIncludeRoutes(t.input)
+		f(c)
+		assert.Equal(t.output, c.includeRoutes)
+	}
+}
+
+func TestWithExcludeRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input  []string
+		output []string
+	}{
+		"success": {
+			input:  []string{"192.168.0.0/24"},
+			output: []string{"192.168.0.0/24"},
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithExcludeRoutes(t.input)
+		f(c)
+		assert.Equal(t.output, c.excludeRoutes)
+	}
+}
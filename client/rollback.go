@@ -10,6 +10,9 @@ import (
 
 type Rollback struct {
 	Routes        []route
+	TunRoutes     []tunRoute
+	NetRoutes     []netRoute
+	DNS           *internal.DNSBackup
 	reset         bool
 	originGateway string
 	tun           *water.Interface
@@ -21,6 +24,17 @@ type route struct {
 	dev  string
 }
 
+type tunRoute struct {
+	subnet *net.IPNet
+	dev    string
+}
+
+type netRoute struct {
+	subnet *net.IPNet
+	via    net.IP
+	dev    string
+}
+
 // AddRoute adds a route to the deletion set when it is reset.
 func (r *Rollback) AddRoute(destination net.IP, via net.IP, dev string) {
 	r.Routes = append(r.Routes, route{
@@ -30,6 +44,31 @@ func (r *Rollback) AddRoute(destination net.IP, via net.IP, dev string) {
 	})
 }
 
+// AddTunRoute adds a route via tun device to the deletion set when it is reset.
+func (r *Rollback) AddTunRoute(subnet *net.IPNet, dev string) {
+	r.TunRoutes = append(r.TunRoutes, tunRoute{
+		subnet: subnet,
+		dev:    dev,
+	})
+}
+
+// AddNetRoute adds a route via gateway on device to the deletion set when it is reset.
+func (r *Rollback) AddNetRoute(subnet *net.IPNet, via net.IP, dev string) {
+	r.NetRoutes = append(r.NetRoutes, netRoute{
+		subnet: subnet,
+		via:    via,
+		dev:    dev,
+	})
+}
+
+// RestoreDNS adds an original dns setting which is restored when it is reset.
+// if an original dns setting is already added, it is kept.
+func (r *Rollback) RestoreDNS(backup *internal.DNSBackup) {
+	if r.DNS == nil {
+		r.DNS = backup
+	}
+}
+
 // ResetGatewayOSX tells the rollback object what gateway should be set on exit.
 func (r *Rollback) ResetGatewayOSX(tun *water.Interface, gw string) {
 	r.reset = true
@@ -48,6 +87,35 @@ func (r *Rollback) Close() {
 		}
 	}
 
+	for _, route := range r.TunRoutes {
+		e := internal.DelTunRoute(route.subnet, route.dev)
+		if e == nil {
+			defaultLogger.Info(color.GreenString("Deleted route to %s on %s\n", route.subnet.String(), route.dev))
+		} else {
+			defaultLogger.Info(color.RedString("Error: Route delete %s (on %s) - %s\n", route.subnet.String(), route.dev, e.Error()))
+		}
+	}
+	for _, route := range r.NetRoutes {
+		e := internal.DelNetRoute(route.subnet, route.via, route.dev)
+		if e == nil {
+			defaultLogger.Info(color.GreenString("Deleted route to %s via %s on %s\n", route.subnet.String(), route.via.String(), route.dev))
+		} else {
+			defaultLogger.Info(color.RedString("Error: Route delete %s (%s on %s) - %s\n", route.subnet.String(), route.via.String(), route.dev, e.Error()))
+		}
+	}
+	// tun routes and net routes are added again when vpn is reconnected.
+	r.TunRoutes = nil
+	r.NetRoutes = nil
+
+	if r.DNS != nil {
+		if e := internal.RestoreDNS(r.DNS); e == nil {
+			defaultLogger.Info(color.GreenString("Restored dns\n"))
+		} else {
+			defaultLogger.Info(color.RedString("Error: Restore dns - %s\n", e.Error()))
+		}
+		r.DNS = nil
+	}
+
 	if r.reset {
 		r.tun.Close()
 		internal.CommandExec("route", []string{"add", "default", r.originGateway})
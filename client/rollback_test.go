@@ -1 +1,63 @@
 package client
+
+import (
+	"net"
+	"testing"
+
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollback_AddTunRoute(t *testing.T) {
+	assert := assert.New(t)
+
+	_, subnet, _ := net.ParseCIDR("172.16.0.0/16")
+	tests := map[string]struct {
+		subnet *net.IPNet
+		dev    string
+	}{
+		"success": {subnet: subnet, dev: "utun1"},
+	}
+
+	for _, t := range tests {
+		r := &Rollback{}
+		r.AddTunRoute(t.subnet, t.dev)
+		assert.Len(r.TunRoutes, 1)
+		assert.Equal(t.subnet, r.TunRoutes[0].subnet)
+		assert.Equal(t.dev, r.TunRoutes[0].dev)
+	}
+}
+
+func TestRollback_AddNetRoute(t *testing.T) {
+	assert := assert.New(t)
+
+	_, subnet, _ := net.ParseCIDR("192.168.0.0/24")
+	tests := map[string]struct {
+		subnet *net.IPNet
+		via    net.IP
+		dev    string
+	}{
+		"success": {subnet: subnet, via: net.ParseIP("192.168.0.1"), dev: "en0"},
+	}
+
+	for _, t := range tests {
+		r := &Rollback{}
+		r.AddNetRoute(t.subnet, t.via, t.dev)
+		assert.Len(r.NetRoutes, 1)
+		assert.Equal(t.subnet, r.NetRoutes[0].subnet)
+		assert.Equal(t.via, r.NetRoutes[0].via)
+		assert.Equal(t.dev, r.NetRoutes[0].dev)
+	}
+}
+
+func TestRollback_RestoreDNS(t *testing.T) {
+	assert := assert.New(t)
+
+	first := &internal.DNSBackup{}
+	second := &internal.DNSBackup{}
+
+	r := &Rollback{}
+	r.RestoreDNS(first)
+	r.RestoreDNS(second)
+	assert.True(first == r.DNS)
+}
@@ -31,9 +31,16 @@ type config struct {
 	Addr                    string
 	Port                    string
 	SelfSignedCertification string
+	ClientCertification     string
+	ClientPem               string
 	Insecure                bool
+	IncludeRoutes           []string
+	ExcludeRoutes           []string
 	GoogleConfig            *auth.GoogleOpenIDConfig
 	AwsConfig               *auth.AwsIamConfig
+	LdapConfig              *auth.LdapConfig
+	OpenIDConfig            *auth.OpenIDConfig
+	AuthCombine             bool
 }
 
 type commandRun func(cmd *cobra.Command, args []string)
@@ -79,9 +86,21 @@ func setConfig(cfgPath string) error {
 					defaultConfig.Addr = internal.InterfaceToString(v)
 				case "self_signed_certification":
 					defaultConfig.SelfSignedCertification = internal.InterfaceToString(v)
+				case "client_certification":
+					defaultConfig.ClientCertification = internal.InterfaceToString(v)
+				case "client_pem":
+					defaultConfig.ClientPem = internal.InterfaceToString(v)
 				case "insecure":
 					insecure, _ := strconv.ParseBool(internal.InterfaceToString(v))
 					defaultConfig.Insecure = insecure
+				case "include_routes":
+					for _, vv := range v.([]interface{}) {
+						defaultConfig.IncludeRoutes = append(defaultConfig.IncludeRoutes, internal.InterfaceToString(vv))
+					}
+				case "exclude_routes":
+					for _, vv := range v.([]interface{}) {
+						defaultConfig.ExcludeRoutes = append(defaultConfig.ExcludeRoutes, internal.InterfaceToString(vv))
+					}
 				default:
 					return fmt.Errorf("[ERR] unknown config %s", k)
 				}
@@ -89,6 +108,9 @@ func setConfig(cfgPath string) error {
 		case "auth":
 			for k, v := range value.(map[interface{}]interface{}) {
 				switch k {
+				case "combine":
+					combine, _ := strconv.ParseBool(internal.InterfaceToString(v))
+					defaultConfig.AuthCombine = combine
 				case "google_openid":
 					defaultConfig.GoogleConfig = &auth.GoogleOpenIDConfig{}
 					for kk, vv := range v.(map[interface{}]interface{}) {
@@ -97,6 +119,11 @@ func setConfig(cfgPath string) error {
 							defaultConfig.GoogleConfig.ClientId = internal.InterfaceToString(vv)
 						case "client_secret":
 							defaultConfig.GoogleConfig.ClientSecret = internal.InterfaceToString(vv)
+						case "device_flow":
+							deviceFlow, _ := strconv.ParseBool(internal.InterfaceToString(vv))
+							defaultConfig.GoogleConfig.DeviceFlow = deviceFlow
+						case "redirect_port":
+							defaultConfig.GoogleConfig.RedirectPort = internal.InterfaceToString(vv)
 						default:
 							return fmt.Errorf("[ERR] unknown config %s", kk)
 						}
@@ -109,6 +136,50 @@ func setConfig(cfgPath string) error {
 							defaultConfig.AwsConfig.ClientAccessKey = internal.InterfaceToString(vv)
 						case "secret_access_key":
 							defaultConfig.AwsConfig.ClientSecretAccessKey = internal.InterfaceToString(vv)
+						case "session_token":
+							defaultConfig.AwsConfig.ClientSessionToken = internal.InterfaceToString(vv)
+						case "profile":
+							defaultConfig.AwsConfig.ClientProfile = internal.InterfaceToString(vv)
+						case "sts_endpoint":
+							defaultConfig.AwsConfig.ClientStsEndpoint = internal.InterfaceToString(vv)
+						case "sts_region":
+							defaultConfig.AwsConfig.ClientStsRegion = internal.InterfaceToString(vv)
+						case "server_id":
+							defaultConfig.AwsConfig.ClientServerId = internal.InterfaceToString(vv)
+						default:
+							return fmt.Errorf("[ERR] unknown config %s", kk)
+						}
+					}
+				case "openid":
+					defaultConfig.OpenIDConfig = &auth.OpenIDConfig{}
+					for kk, vv := range v.(map[interface{}]interface{}) {
+						switch kk.(string) {
+						case "issuer":
+							defaultConfig.OpenIDConfig.Issuer = internal.InterfaceToString(vv)
+						case "client_id":
+							defaultConfig.OpenIDConfig.ClientId = internal.InterfaceToString(vv)
+						case "device_flow":
+							deviceFlow, _ := strconv.ParseBool(internal.InterfaceToString(vv))
+							defaultConfig.OpenIDConfig.DeviceFlow = deviceFlow
+						case "redirect_port":
+							defaultConfig.OpenIDConfig.RedirectPort = internal.InterfaceToString(vv)
+						case "scopes":
+							for _, vvv := range vv.([]interface{}) {
+								defaultConfig.OpenIDConfig.Scopes = append(defaultConfig.OpenIDConfig.Scopes,
+									vvv.(string))
+							}
+						default:
+							return fmt.Errorf("[ERR] unknown config %s", kk)
+						}
+					}
+				case "ldap":
+					defaultConfig.LdapConfig = &auth.LdapConfig{}
+					for kk, vv := range v.(map[interface{}]interface{}) {
+						switch kk.(string) {
+						case "username":
+							defaultConfig.LdapConfig.ClientUsername = internal.InterfaceToString(vv)
+						case "password":
+							defaultConfig.LdapConfig.ClientPassword = internal.InterfaceToString(vv)
 						default:
 							return fmt.Errorf("[ERR] unknown config %s", kk)
 						}
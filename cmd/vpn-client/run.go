@@ -6,6 +6,7 @@ import (
 	"runtime"
 
 	"github.com/fatih/color"
+	"github.com/gjbae1212/grpc-vpn/auth"
 	"github.com/gjbae1212/grpc-vpn/client"
 	"github.com/mitchellh/go-ps"
 	"github.com/spf13/cobra"
@@ -69,17 +70,56 @@ func startRun() commandRun {
 			opts = append(opts, client.WithSelfSignedCertification(defaultConfig.SelfSignedCertification))
 		}
 		opts = append(opts, client.WithGRPCInsecure(defaultConfig.Insecure))
+		if len(defaultConfig.IncludeRoutes) > 0 {
+			opts = append(opts, client.WithIncludeRoutes(defaultConfig.IncludeRoutes))
+		}
+		if len(defaultConfig.ExcludeRoutes) > 0 {
+			opts = append(opts, client.WithExcludeRoutes(defaultConfig.ExcludeRoutes))
+		}
+
+		// mutual tls authentication(it's used only if any other authentication method isn't configured)
+		var mtlsMethods []auth.RefreshingClientAuthMethod
+		if defaultConfig.ClientCertification != "" || defaultConfig.ClientPem != "" {
+			opts = append(opts, client.WithClientCertificate(defaultConfig.ClientCertification, defaultConfig.ClientPem))
+			mtls, _ := auth.NewClientManagerForMTLS()
+			method, _ := mtls.(auth.RefreshingClientManager).RefreshingClientAuth()
+			mtlsMethods = append(mtlsMethods, method)
+		}
 
+		var authMethods []auth.RefreshingClientAuthMethod
 		// aws authentication
-		method1, ok1 := defaultConfig.AwsConfig.ClientAuth()
-		if ok1 {
-			opts = append(opts, client.WithAuthMethod(method1))
+		if method1, ok1 := defaultConfig.AwsConfig.RefreshingClientAuth(); ok1 {
+			authMethods = append(authMethods, method1)
 		}
 
 		// google authentication
-		method2, ok2 := defaultConfig.GoogleConfig.ClientAuth()
-		if ok2 {
-			opts = append(opts, client.WithAuthMethod(method2))
+		if method2, ok2 := defaultConfig.GoogleConfig.RefreshingClientAuth(); ok2 {
+			authMethods = append(authMethods, method2)
+		}
+
+		// ldap authentication
+		if method3, ok3 := defaultConfig.LdapConfig.RefreshingClientAuth(); ok3 {
+			authMethods = append(authMethods, method3)
+		}
+
+		// openid authentication
+		if method4, ok4 := defaultConfig.OpenIDConfig.RefreshingClientAuth(); ok4 {
+			authMethods = append(authMethods, method4)
+		}
+
+		switch {
+		case defaultConfig.AuthCombine && len(mtlsMethods)+len(authMethods) > 1:
+			// all of authentication methods are sent together, if auth policy of vpn server requires them.
+			combined := auth.CombineClientAuth(append(mtlsMethods, authMethods...)...)
+			opts = append(opts, client.WithRefreshingAuthMethod(combined))
+		case len(authMethods) > 1:
+			// which one is used isn't clear, so several authentication methods need auth.combine.
+			log.Println(color.RedString("[ERR] several auth methods are configured, set auth.combine or configure only one"))
+			os.Exit(1)
+		case len(authMethods) == 1:
+			opts = append(opts, client.WithRefreshingAuthMethod(authMethods[0]))
+		case len(mtlsMethods) == 1:
+			opts = append(opts, client.WithRefreshingAuthMethod(mtlsMethods[0]))
 		}
 
 		client, err := client.NewVpnClient(opts...)
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/gjbae1212/grpc-vpn/server"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const defaultMFAIssuer = "grpc-vpn"
+
+var (
+	mfaCmd = &cobra.Command{
+		Use:   "mfa",
+		Short: "Manage totp second factor of users",
+		Long:  "Manage totp second factor of users",
+	}
+
+	mfaEnrollCmd = &cobra.Command{
+		Use:   "enroll [user]",
+		Short: "Enroll user, new totp secret is generated and printed",
+		Long:  "Enroll user, new totp secret is generated and printed(previous secret of user is replaced)",
+		Args:  cobra.ExactArgs(1),
+		Run:   mfaEnrollRun(),
+	}
+
+	mfaUnenrollCmd = &cobra.Command{
+		Use:   "unenroll [user]",
+		Short: "Unenroll user, totp secret of user is removed",
+		Long:  "Unenroll user, totp secret of user is removed",
+		Args:  cobra.ExactArgs(1),
+		Run:   mfaUnenrollRun(),
+	}
+)
+
+// mfaStore returns file store of config, running vpn-server reloads it when file is modified.
+func mfaStore() server.MFAStore {
+	store, err := server.NewFileMFAStore(defaultConfig.MFAPath)
+	if err != nil {
+		log.Println(color.RedString("[ERR] mfa path %s", err))
+		os.Exit(1)
+	}
+	return store
+}
+
+func mfaEnrollRun() commandRun {
+	return func(cmd *cobra.Command, args []string) {
+		secret, err := mfaStore().Enroll(args[0])
+		if err != nil {
+			log.Println(color.RedString("[ERR] %s", err))
+			os.Exit(1)
+		}
+
+		issuer := defaultConfig.MFAIssuer
+		if issuer == "" {
+			issuer = defaultMFAIssuer
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "SECRET")
+		fmt.Fprintln(cmd.OutOrStdout(), secret)
+		fmt.Fprintln(cmd.OutOrStdout(), "URI")
+		fmt.Fprintln(cmd.OutOrStdout(), internal.TOTPURI(issuer, args[0], secret))
+	}
+}
+
+func mfaUnenrollRun() commandRun {
+	return func(cmd *cobra.Command, args []string) {
+		if err := mfaStore().Unenroll(args[0]); err != nil {
+			log.Println(color.RedString("[ERR] %s", err))
+			os.Exit(1)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "UNENROLLED")
+		fmt.Fprintln(cmd.OutOrStdout(), args[0])
+	}
+}
+
+func init() {
+	mfaCmd.AddCommand(mfaEnrollCmd, mfaUnenrollCmd)
+	rootCmd.AddCommand(mfaCmd)
+}
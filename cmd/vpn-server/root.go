@@ -6,10 +6,13 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/gjbae1212/grpc-vpn/auth"
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
 	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/gjbae1212/grpc-vpn/server"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -28,15 +31,40 @@ var (
 )
 
 type config struct {
-	Port             string
-	SubNet           string
-	LogPath          string
-	JwtSalt          string
-	JwtExpiration    time.Duration
-	TlsCertification string
-	TlsPem           string
-	GoogleConfig     *auth.GoogleOpenIDConfig
-	AwsConfig        *auth.AwsIamConfig
+	Port                   string
+	SubNet                 string
+	SubNet6                string
+	LogPath                string
+	JwtSalt                string
+	JwtExpiration          time.Duration
+	RefreshTokenExpiration *time.Duration
+	SessionGracePeriod     *time.Duration
+	JwtActiveKey           string
+	JwtKeys                map[string]string
+	LeaseTTL               time.Duration
+	Reservations           map[string][]string
+	Routes                 []string
+	FullTunnel             bool
+	DNSServers             []string
+	DNSSearchDomains       []string
+	ClientIsolation        string
+	RevocationPath         string
+	TlsCertification       string
+	TlsPem                 string
+	TlsClientCA            string
+	GoogleConfig           *auth.GoogleOpenIDConfig
+	AwsConfig              *auth.AwsIamConfig
+	LdapConfig             *auth.LdapConfig
+	OpenIDConfig           *auth.OpenIDConfig
+	DNSConfig              *server.DNSServerConfig
+	Groups                 map[string][]string
+	ACLConfig              *server.ACLConfig
+	AdminToken             string
+	MFAPath                string
+	MFARequired            bool
+	MFAIssuer              string
+	AuthTest               bool
+	AuthAnyOf              [][]protocol.AuthType
 }
 
 type commandRun func(cmd *cobra.Command, args []string)
@@ -80,6 +108,8 @@ func setConfig(cfgPath string) error {
 					defaultConfig.Port = internal.InterfaceToString(v)
 				case "subnet":
 					defaultConfig.SubNet = internal.InterfaceToString(v)
+				case "subnet6":
+					defaultConfig.SubNet6 = internal.InterfaceToString(v)
 				case "log_path":
 					defaultConfig.LogPath = internal.InterfaceToString(v)
 				case "jwt_salt":
@@ -87,10 +117,172 @@ func setConfig(cfgPath string) error {
 				case "jwt_expiration":
 					expire, _ := time.ParseDuration(internal.InterfaceToString(v))
 					defaultConfig.JwtExpiration = expire
+				case "refresh_token_expiration":
+					if value := internal.InterfaceToString(v); value != "" {
+						expire, err := time.ParseDuration(value)
+						if err != nil {
+							return fmt.Errorf("[ERR] invalid config %s", k)
+						}
+						defaultConfig.RefreshTokenExpiration = &expire
+					}
+				case "session_grace_period":
+					if value := internal.InterfaceToString(v); value != "" {
+						period, err := time.ParseDuration(value)
+						if err != nil {
+							return fmt.Errorf("[ERR] invalid config %s", k)
+						}
+						defaultConfig.SessionGracePeriod = &period
+					}
+				case "lease_ttl":
+					ttl, _ := time.ParseDuration(internal.InterfaceToString(v))
+					defaultConfig.LeaseTTL = ttl
+				case "reservations":
+					defaultConfig.Reservations = map[string][]string{}
+					for kk, vv := range v.(map[interface{}]interface{}) {
+						user := internal.InterfaceToString(kk)
+						switch ips := vv.(type) {
+						case string:
+							defaultConfig.Reservations[user] = append(defaultConfig.Reservations[user], ips)
+						case []interface{}:
+							for _, vvv := range ips {
+								defaultConfig.Reservations[user] = append(defaultConfig.Reservations[user],
+									internal.InterfaceToString(vvv))
+							}
+						default:
+							return fmt.Errorf("[ERR] unknown reservation %s", user)
+						}
+					}
+				case "routes":
+					for _, vv := range v.([]interface{}) {
+						defaultConfig.Routes = append(defaultConfig.Routes, internal.InterfaceToString(vv))
+					}
+				case "full_tunnel":
+					fullTunnel, _ := strconv.ParseBool(internal.InterfaceToString(v))
+					defaultConfig.FullTunnel = fullTunnel
+				case "dns_servers":
+					for _, vv := range v.([]interface{}) {
+						defaultConfig.DNSServers = append(defaultConfig.DNSServers, internal.InterfaceToString(vv))
+					}
+				case "dns_search_domains":
+					for _, vv := range v.([]interface{}) {
+						defaultConfig.DNSSearchDomains = append(defaultConfig.DNSSearchDomains, internal.InterfaceToString(vv))
+					}
+				case "client_isolation":
+					defaultConfig.ClientIsolation = internal.InterfaceToString(v)
+				case "revocation_path":
+					defaultConfig.RevocationPath = internal.InterfaceToString(v)
 				case "tls_certification":
 					defaultConfig.TlsCertification = internal.InterfaceToString(v)
 				case "tls_pem":
 					defaultConfig.TlsPem = internal.InterfaceToString(v)
+				case "tls_client_ca":
+					defaultConfig.TlsClientCA = internal.InterfaceToString(v)
+				default:
+					return fmt.Errorf("[ERR] unknown config %s", k)
+				}
+			}
+		case "dns":
+			defaultConfig.DNSConfig = &server.DNSServerConfig{}
+			for k, v := range value.(map[interface{}]interface{}) {
+				switch k.(string) {
+				case "domain":
+					defaultConfig.DNSConfig.Domain = internal.InterfaceToString(v)
+				case "upstreams":
+					for _, vv := range v.([]interface{}) {
+						defaultConfig.DNSConfig.Upstreams = append(defaultConfig.DNSConfig.Upstreams,
+							internal.InterfaceToString(vv))
+					}
+				case "port":
+					defaultConfig.DNSConfig.Port = internal.InterfaceToString(v)
+				case "timeout":
+					timeout, _ := time.ParseDuration(internal.InterfaceToString(v))
+					defaultConfig.DNSConfig.Timeout = timeout
+				default:
+					return fmt.Errorf("[ERR] unknown config %s", k)
+				}
+			}
+		case "jwt":
+			defaultConfig.JwtKeys = map[string]string{}
+			for k, v := range value.(map[interface{}]interface{}) {
+				switch k.(string) {
+				case "active_key":
+					defaultConfig.JwtActiveKey = internal.InterfaceToString(v)
+				case "keys":
+					for kk, vv := range v.(map[interface{}]interface{}) {
+						defaultConfig.JwtKeys[internal.InterfaceToString(kk)] = internal.InterfaceToString(vv)
+					}
+				default:
+					return fmt.Errorf("[ERR] unknown config %s", k)
+				}
+			}
+		case "groups":
+			defaultConfig.Groups = map[string][]string{}
+			for k, v := range value.(map[interface{}]interface{}) {
+				group := internal.InterfaceToString(k)
+				for _, vv := range v.([]interface{}) {
+					defaultConfig.Groups[group] = append(defaultConfig.Groups[group], internal.InterfaceToString(vv))
+				}
+			}
+		case "acl":
+			defaultConfig.ACLConfig = &server.ACLConfig{}
+			for k, v := range value.(map[interface{}]interface{}) {
+				switch k.(string) {
+				case "default_allow":
+					defaultAllow, _ := strconv.ParseBool(internal.InterfaceToString(v))
+					defaultConfig.ACLConfig.DefaultAllow = defaultAllow
+				case "log_denied":
+					logDenied, _ := strconv.ParseBool(internal.InterfaceToString(v))
+					defaultConfig.ACLConfig.LogDenied = logDenied
+				case "rules":
+					for _, vv := range v.([]interface{}) {
+						rule := &server.ACLRule{}
+						for kkk, vvv := range vv.(map[interface{}]interface{}) {
+							var values []string
+							for _, value := range vvv.([]interface{}) {
+								values = append(values, internal.InterfaceToString(value))
+							}
+							switch kkk.(string) {
+							case "users":
+								rule.Users = values
+							case "groups":
+								rule.Groups = values
+							case "roles":
+								rule.Roles = values
+							case "destinations":
+								rule.Destinations = values
+							case "ports":
+								rule.Ports = values
+							case "protocols":
+								rule.Protocols = values
+							default:
+								return fmt.Errorf("[ERR] unknown config %s", kkk)
+							}
+						}
+						defaultConfig.ACLConfig.Rules = append(defaultConfig.ACLConfig.Rules, rule)
+					}
+				default:
+					return fmt.Errorf("[ERR] unknown config %s", k)
+				}
+			}
+		case "admin":
+			for k, v := range value.(map[interface{}]interface{}) {
+				switch k.(string) {
+				case "token":
+					defaultConfig.AdminToken = internal.InterfaceToString(v)
+				default:
+					return fmt.Errorf("[ERR] unknown config %s", k)
+				}
+			}
+		case "mfa":
+			for k, v := range value.(map[interface{}]interface{}) {
+				switch k.(string) {
+				case "path":
+					defaultConfig.MFAPath = internal.InterfaceToString(v)
+				case "required":
+					required, _ := strconv.ParseBool(internal.InterfaceToString(v))
+					defaultConfig.MFARequired = required
+				case "issuer":
+					defaultConfig.MFAIssuer = internal.InterfaceToString(v)
 				default:
 					return fmt.Errorf("[ERR] unknown config %s", k)
 				}
@@ -98,6 +290,28 @@ func setConfig(cfgPath string) error {
 		case "auth":
 			for k, v := range value.(map[interface{}]interface{}) {
 				switch k {
+				case "test":
+					test, _ := strconv.ParseBool(internal.InterfaceToString(v))
+					defaultConfig.AuthTest = test
+				case "policy":
+					for kk, vv := range v.(map[interface{}]interface{}) {
+						switch kk.(string) {
+						case "any_of":
+							for _, combination := range vv.([]interface{}) {
+								var authTypes []protocol.AuthType
+								for _, name := range combination.([]interface{}) {
+									authType, err := auth.ParseAuthType(internal.InterfaceToString(name))
+									if err != nil {
+										return fmt.Errorf("[ERR] unknown auth type %s", name)
+									}
+									authTypes = append(authTypes, authType)
+								}
+								defaultConfig.AuthAnyOf = append(defaultConfig.AuthAnyOf, authTypes)
+							}
+						default:
+							return fmt.Errorf("[ERR] unknown config %s", kk)
+						}
+					}
 				case "google_openid":
 					defaultConfig.GoogleConfig = &auth.GoogleOpenIDConfig{}
 					for kk, vv := range v.(map[interface{}]interface{}) {
@@ -108,6 +322,8 @@ func setConfig(cfgPath string) error {
 							defaultConfig.GoogleConfig.ClientSecret = internal.InterfaceToString(vv)
 						case "hd":
 							defaultConfig.GoogleConfig.HD = internal.InterfaceToString(vv)
+						case "device_client_id":
+							defaultConfig.GoogleConfig.DeviceClientId = internal.InterfaceToString(vv)
 						case "allow_emails":
 							for _, vvv := range vv.([]interface{}) {
 								defaultConfig.GoogleConfig.AllowEmails = append(defaultConfig.GoogleConfig.AllowEmails,
@@ -123,11 +339,144 @@ func setConfig(cfgPath string) error {
 						switch kk.(string) {
 						case "account_id":
 							defaultConfig.AwsConfig.ServerAccountId = internal.InterfaceToString(vv)
+						case "sts_endpoint":
+							defaultConfig.AwsConfig.ServerStsEndpoint = internal.InterfaceToString(vv)
+						case "server_id":
+							defaultConfig.AwsConfig.ServerId = internal.InterfaceToString(vv)
 						case "allow_users":
 							for _, vvv := range vv.([]interface{}) {
 								defaultConfig.AwsConfig.ServerAllowUsers = append(defaultConfig.AwsConfig.ServerAllowUsers,
 									vvv.(string))
 							}
+						case "allow_roles":
+							for _, vvv := range vv.([]interface{}) {
+								// assumed role always has a name, so empty role can never match.
+								if internal.InterfaceToString(vvv) == "" {
+									return fmt.Errorf("[ERR] empty value of %s", kk)
+								}
+								defaultConfig.AwsConfig.ServerAllowRoles = append(defaultConfig.AwsConfig.ServerAllowRoles,
+									internal.InterfaceToString(vvv))
+							}
+						case "allow_paths":
+							for _, vvv := range vv.([]interface{}) {
+								// empty rule matches every principal(empty path prefix).
+								if internal.InterfaceToString(vvv) == "" {
+									return fmt.Errorf("[ERR] empty value of %s", kk)
+								}
+								defaultConfig.AwsConfig.ServerAllowPaths = append(defaultConfig.AwsConfig.ServerAllowPaths,
+									internal.InterfaceToString(vvv))
+							}
+						case "allow_arns":
+							for _, vvv := range vv.([]interface{}) {
+								// empty arn pattern can never match an arn.
+								if internal.InterfaceToString(vvv) == "" {
+									return fmt.Errorf("[ERR] empty value of %s", kk)
+								}
+								defaultConfig.AwsConfig.ServerAllowArns = append(defaultConfig.AwsConfig.ServerAllowArns,
+									internal.InterfaceToString(vvv))
+							}
+						case "role_groups":
+							defaultConfig.AwsConfig.ServerRoleGroups = map[string][]string{}
+							for group, roles := range vv.(map[interface{}]interface{}) {
+								for _, role := range roles.([]interface{}) {
+									defaultConfig.AwsConfig.ServerRoleGroups[internal.InterfaceToString(group)] = append(
+										defaultConfig.AwsConfig.ServerRoleGroups[internal.InterfaceToString(group)], internal.InterfaceToString(role))
+								}
+							}
+						default:
+							return fmt.Errorf("[ERR] unknown config %s", kk)
+						}
+					}
+					if defaultConfig.AwsConfig.ServerAccountId != "" && defaultConfig.AwsConfig.ServerId == "" {
+						return fmt.Errorf("[ERR] server_id is required with account_id of aws_iam")
+					}
+				case "openid":
+					defaultConfig.OpenIDConfig = &auth.OpenIDConfig{}
+					for kk, vv := range v.(map[interface{}]interface{}) {
+						switch kk.(string) {
+						case "issuer":
+							defaultConfig.OpenIDConfig.Issuer = internal.InterfaceToString(vv)
+						case "client_id":
+							defaultConfig.OpenIDConfig.ClientId = internal.InterfaceToString(vv)
+						case "client_secret":
+							defaultConfig.OpenIDConfig.ClientSecret = internal.InterfaceToString(vv)
+						case "scopes":
+							for _, vvv := range vv.([]interface{}) {
+								defaultConfig.OpenIDConfig.Scopes = append(defaultConfig.OpenIDConfig.Scopes,
+									vvv.(string))
+							}
+						case "username_claim":
+							defaultConfig.OpenIDConfig.UsernameClaim = internal.InterfaceToString(vv)
+						case "groups_claim":
+							defaultConfig.OpenIDConfig.GroupsClaim = internal.InterfaceToString(vv)
+						case "required_claims":
+							defaultConfig.OpenIDConfig.RequiredClaims = map[string]string{}
+							for name, value := range vv.(map[interface{}]interface{}) {
+								defaultConfig.OpenIDConfig.RequiredClaims[internal.InterfaceToString(name)] =
+									internal.InterfaceToString(value)
+							}
+						case "allow_users":
+							for _, vvv := range vv.([]interface{}) {
+								defaultConfig.OpenIDConfig.AllowUsers = append(defaultConfig.OpenIDConfig.AllowUsers,
+									vvv.(string))
+							}
+						case "allow_groups":
+							for _, vvv := range vv.([]interface{}) {
+								defaultConfig.OpenIDConfig.AllowGroups = append(defaultConfig.OpenIDConfig.AllowGroups,
+									vvv.(string))
+							}
+						case "group_mapping":
+							defaultConfig.OpenIDConfig.GroupMapping = map[string][]string{}
+							for group, members := range vv.(map[interface{}]interface{}) {
+								for _, member := range members.([]interface{}) {
+									defaultConfig.OpenIDConfig.GroupMapping[internal.InterfaceToString(group)] = append(
+										defaultConfig.OpenIDConfig.GroupMapping[internal.InterfaceToString(group)], internal.InterfaceToString(member))
+								}
+							}
+						default:
+							return fmt.Errorf("[ERR] unknown config %s", kk)
+						}
+					}
+				case "ldap":
+					defaultConfig.LdapConfig = &auth.LdapConfig{}
+					for kk, vv := range v.(map[interface{}]interface{}) {
+						switch kk.(string) {
+						case "addr":
+							defaultConfig.LdapConfig.ServerAddr = internal.InterfaceToString(vv)
+						case "start_tls":
+							startTLS, _ := strconv.ParseBool(internal.InterfaceToString(vv))
+							defaultConfig.LdapConfig.ServerStartTLS = startTLS
+						case "insecure_skip_verify":
+							skip, _ := strconv.ParseBool(internal.InterfaceToString(vv))
+							defaultConfig.LdapConfig.ServerInsecureSkipVerify = skip
+						case "bind_dn":
+							defaultConfig.LdapConfig.ServerBindDN = internal.InterfaceToString(vv)
+						case "bind_password":
+							defaultConfig.LdapConfig.ServerBindPassword = internal.InterfaceToString(vv)
+						case "base_dn":
+							defaultConfig.LdapConfig.ServerBaseDN = internal.InterfaceToString(vv)
+						case "user_filter":
+							defaultConfig.LdapConfig.ServerUserFilter = internal.InterfaceToString(vv)
+						case "group_filter":
+							defaultConfig.LdapConfig.ServerGroupFilter = internal.InterfaceToString(vv)
+						case "allow_users":
+							for _, vvv := range vv.([]interface{}) {
+								defaultConfig.LdapConfig.ServerAllowUsers = append(defaultConfig.LdapConfig.ServerAllowUsers,
+									vvv.(string))
+							}
+						case "allow_groups":
+							for _, vvv := range vv.([]interface{}) {
+								defaultConfig.LdapConfig.ServerAllowGroups = append(defaultConfig.LdapConfig.ServerAllowGroups,
+									vvv.(string))
+							}
+						case "group_mapping":
+							defaultConfig.LdapConfig.ServerGroupMapping = map[string][]string{}
+							for group, members := range vv.(map[interface{}]interface{}) {
+								for _, member := range members.([]interface{}) {
+									defaultConfig.LdapConfig.ServerGroupMapping[internal.InterfaceToString(group)] = append(
+										defaultConfig.LdapConfig.ServerGroupMapping[internal.InterfaceToString(group)], internal.InterfaceToString(member))
+								}
+							}
 						default:
 							return fmt.Errorf("[ERR] unknown config %s", kk)
 						}
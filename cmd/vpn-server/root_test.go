@@ -1,18 +1,37 @@
 package main
 
 import (
-	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestSetConfig(t *testing.T) {
 	assert := assert.New(t)
 
+	emptyPath, err := ioutil.TempFile("", "vpn-server-*.yaml")
+	assert.NoError(err)
+	defer os.Remove(emptyPath.Name())
+	_, err = emptyPath.WriteString("auth:\n  aws_iam:\n    account_id: \"123456789012\"\n    server_id: \"vpn.example.com\"\n    allow_paths:\n      - \"\"\n")
+	assert.NoError(err)
+	emptyPath.Close()
+
+	noServerID, err := ioutil.TempFile("", "vpn-server-*.yaml")
+	assert.NoError(err)
+	defer os.Remove(noServerID.Name())
+	_, err = noServerID.WriteString("auth:\n  aws_iam:\n    account_id: \"123456789012\"\n")
+	assert.NoError(err)
+	noServerID.Close()
+
 	tests := map[string]struct {
 		path  string
 		isErr bool
 	}{
-		"success": {path: "sample.yaml"},
+		"success":           {path: "sample.yaml"},
+		"empty-allow-path":  {path: emptyPath.Name(), isErr: true},
+		"without-server-id": {path: noServerID.Name(), isErr: true},
 	}
 
 	for _, t := range tests {
@@ -6,6 +6,8 @@ import (
 	"os"
 	"runtime"
 
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal"
 	"github.com/gjbae1212/grpc-vpn/server"
 
 	"github.com/fatih/color"
@@ -44,31 +46,125 @@ func startRun() commandRun {
 		if defaultConfig.SubNet != "" {
 			opts = append(opts, server.WithVpnSubNet(defaultConfig.SubNet))
 		}
+		if defaultConfig.SubNet6 != "" {
+			opts = append(opts, server.WithVpnSubNet6(defaultConfig.SubNet6))
+		}
 		if defaultConfig.Port != "" {
 			opts = append(opts, server.WithGrpcPort(defaultConfig.Port))
 		}
 		if defaultConfig.JwtSalt != "" {
 			opts = append(opts, server.WithVpnJwtSalt(defaultConfig.JwtSalt))
 		}
+		if len(defaultConfig.Reservations) > 0 {
+			opts = append(opts, server.WithIPReservations(defaultConfig.Reservations))
+		}
+		if len(defaultConfig.Routes) > 0 {
+			opts = append(opts, server.WithVpnRoutes(defaultConfig.Routes))
+		}
+		opts = append(opts, server.WithVpnFullTunnel(defaultConfig.FullTunnel))
+		if len(defaultConfig.DNSServers) > 0 {
+			opts = append(opts, server.WithVpnDNSServers(defaultConfig.DNSServers))
+		}
+		if len(defaultConfig.DNSSearchDomains) > 0 {
+			opts = append(opts, server.WithVpnDNSSearchDomains(defaultConfig.DNSSearchDomains))
+		}
+		if defaultConfig.ClientIsolation != "" {
+			opts = append(opts, server.WithClientIsolation(server.ClientIsolation(defaultConfig.ClientIsolation)))
+		}
+		if defaultConfig.RevocationPath != "" {
+			store, err := server.NewFileRevocationStore(defaultConfig.RevocationPath)
+			if err != nil {
+				log.Panicln(color.RedString("[ERR] %s", err.Error()))
+			}
+			opts = append(opts, server.WithRevocationStore(store))
+		}
+		if defaultConfig.MFAPath != "" {
+			store, err := server.NewFileMFAStore(defaultConfig.MFAPath)
+			if err != nil {
+				log.Panicln(color.RedString("[ERR] %s", err.Error()))
+			}
+			opts = append(opts, server.WithMFAStore(store), server.WithMFARequired(defaultConfig.MFARequired))
+		}
 		if defaultConfig.TlsCertification != "" {
 			opts = append(opts, server.WithGrpcTlsCertification(defaultConfig.TlsCertification))
 		}
 		if defaultConfig.TlsPem != "" {
 			opts = append(opts, server.WithGrpcTlsPem(defaultConfig.TlsPem))
 		}
+		if defaultConfig.TlsClientCA != "" {
+			opts = append(opts, server.WithGrpcTlsClientCA(defaultConfig.TlsClientCA))
+		}
+		if defaultConfig.JwtActiveKey != "" {
+			var keys []*internal.JWTKey
+			for kid, path := range defaultConfig.JwtKeys {
+				key, err := internal.LoadJWTKey(kid, path)
+				if err != nil {
+					log.Panicln(color.RedString("[ERR] %s", err.Error()))
+				}
+				keys = append(keys, key)
+			}
+			keySet, err := internal.NewJWTKeySet(defaultConfig.JwtActiveKey, keys)
+			if err != nil {
+				log.Panicln(color.RedString("[ERR] %s", err.Error()))
+			}
+			opts = append(opts, server.WithVpnJwtKeySet(keySet))
+		}
 		if defaultConfig.JwtExpiration > 0 {
 			opts = append(opts, server.WithVpnJwtExpiration(defaultConfig.JwtExpiration))
 		}
+		if defaultConfig.RefreshTokenExpiration != nil {
+			opts = append(opts, server.WithVpnRefreshTokenExpiration(*defaultConfig.RefreshTokenExpiration))
+		}
+		if defaultConfig.SessionGracePeriod != nil {
+			opts = append(opts, server.WithVpnSessionGracePeriod(*defaultConfig.SessionGracePeriod))
+		}
+		if defaultConfig.LeaseTTL > 0 {
+			opts = append(opts, server.WithIPAMLeaseTTL(defaultConfig.LeaseTTL))
+		}
+		if defaultConfig.DNSConfig != nil {
+			opts = append(opts, server.WithDNSServer(defaultConfig.DNSConfig))
+		}
+		if len(defaultConfig.Groups) > 0 {
+			opts = append(opts, server.WithUserGroups(defaultConfig.Groups))
+		}
+		if defaultConfig.ACLConfig != nil {
+			opts = append(opts, server.WithACL(defaultConfig.ACLConfig))
+		}
+		if defaultConfig.AdminToken != "" {
+			opts = append(opts, server.WithAdminToken(defaultConfig.AdminToken))
+		}
 
-		// apply auth interceptors
-		var authMethods []auth.ServerAuthMethod
+		// apply auth policy
+		authMethods := map[protocol.AuthType]auth.ServerAuthMethod{}
 		if auth1, ok := defaultConfig.GoogleConfig.ServerAuth(); ok {
-			authMethods = append(authMethods, auth1)
+			authMethods[protocol.AuthType_AT_GOOGLE_OPEN_ID] = auth1
 		}
 		if auth2, ok := defaultConfig.AwsConfig.ServerAuth(); ok {
-			authMethods = append(authMethods, auth2)
+			authMethods[protocol.AuthType_AT_AWS_IAM] = auth2
+		}
+		if auth3, ok := defaultConfig.LdapConfig.ServerAuth(); ok {
+			authMethods[protocol.AuthType_AT_LDAP] = auth3
+		}
+		if auth4, ok := defaultConfig.OpenIDConfig.ServerAuth(); ok {
+			authMethods[protocol.AuthType_AT_OPEN_ID] = auth4
+		}
+		if defaultConfig.TlsClientCA != "" {
+			auth5, _ := (&auth.MTLSConfig{}).ServerAuth()
+			authMethods[protocol.AuthType_AT_MTLS] = auth5
+		}
+		if defaultConfig.AuthTest {
+			authManager, _ := auth.NewServerManagerForTest()
+			auth6, _ := authManager.ServerAuth()
+			authMethods[protocol.AuthType_AT_TEST] = auth6
+		}
+		// vpn server refuses to start without auth method.
+		if len(authMethods) > 0 {
+			policy, err := auth.NewPolicy(authMethods, defaultConfig.AuthAnyOf)
+			if err != nil {
+				log.Panicln(color.RedString("[ERR] %s", err.Error()))
+			}
+			opts = append(opts, server.WithAuthPolicy(policy))
 		}
-		opts = append(opts, server.WithAuthMethods(authMethods))
 
 		// create server
 		server, err := server.NewVpnServer(opts...)
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	leasesCmd = &cobra.Command{
+		Use:   "leases",
+		Short: "Manage vpn ip leases",
+		Long:  "Manage vpn ip leases",
+	}
+
+	leasesListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List vpn ip leases",
+		Long:  "List vpn ip leases",
+		Args:  cobra.NoArgs,
+		Run:   adminRun(listLeases, printLeases),
+	}
+)
+
+func listLeases(ctx context.Context, client protocol.AdminClient, args []string) (proto.Message, error) {
+	return client.ListLeases(ctx, &protocol.ListLeasesRequest{})
+}
+
+func printLeases(w io.Writer, result proto.Message) {
+	fmt.Fprintln(w, "USER\tIP\tACTIVE\tRESERVED\tEXPIRED AT")
+	for _, lease := range result.(*protocol.ListLeasesResponse).Leases {
+		fmt.Fprintf(w, "%s\t%s\t%t\t%t\t%s\n", lease.User, lease.Ip, lease.Active, lease.Reserved,
+			formatUnix(lease.ExpiredAt))
+	}
+}
+
+func init() {
+	leasesCmd.AddCommand(leasesListCmd)
+	rootCmd.AddCommand(leasesCmd)
+}
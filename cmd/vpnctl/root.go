@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gjbae1212/grpc-vpn/auth"
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+var (
+	rootCmd = &cobra.Command{
+		Use:   "vpnctl",
+		Short: color.GreenString(`vpnctl is the admin cli for vpn-server.`),
+		Long:  color.GreenString(`vpnctl is the admin cli for vpn-server.`),
+	}
+
+	defaultConfig config
+)
+
+type config struct {
+	Addr                    string
+	Port                    string
+	SelfSignedCertification string
+	Insecure                bool
+	Token                   string
+}
+
+type commandRun func(cmd *cobra.Command, args []string)
+
+func initConfig() {
+	cfgPath := viper.GetString("config")
+
+	if cfgPath == "" {
+		log.Println(color.RedString("[ERR] Not Found Config file"))
+		os.Exit(1)
+	}
+
+	if err := setConfig(cfgPath); err != nil {
+		log.Println(color.RedString("[ERR] setConfig %s", err))
+		os.Exit(1)
+	}
+
+	switch viper.GetString("output") {
+	case outputTable, outputJSON:
+	default:
+		log.Println(color.RedString("[ERR] unknown output %s", viper.GetString("output")))
+		os.Exit(1)
+	}
+}
+
+func setConfig(cfgPath string) error {
+	cfgAbsPath, err := filepath.Abs(cfgPath)
+	if err != nil {
+		return err
+	}
+
+	yml, err := ioutil.ReadFile(cfgAbsPath)
+	if err != nil {
+		return err
+	}
+
+	conf := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal(yml, &conf); err != nil {
+		return err
+	}
+
+	for name, value := range conf {
+		switch name {
+		case "admin":
+			for k, v := range value.(map[interface{}]interface{}) {
+				switch k.(string) {
+				case "addr":
+					defaultConfig.Addr = internal.InterfaceToString(v)
+				case "port":
+					defaultConfig.Port = internal.InterfaceToString(v)
+				case "insecure":
+					insecure, _ := strconv.ParseBool(internal.InterfaceToString(v))
+					defaultConfig.Insecure = insecure
+				case "self_signed_certification":
+					defaultConfig.SelfSignedCertification = internal.InterfaceToString(v)
+				case "token":
+					defaultConfig.Token = internal.InterfaceToString(v)
+				default:
+					return fmt.Errorf("[ERR] unknown config %s", k)
+				}
+			}
+		default:
+			return fmt.Errorf("[ERR] unknown config %s", name)
+		}
+	}
+
+	return nil
+}
+
+// adminCall connects to admin api of vpn-server, and calls fn with context having admin token.
+func adminCall(fn func(ctx context.Context, client protocol.AdminClient) (proto.Message, error)) (proto.Message, error) {
+	dialOpts := []grpc.DialOption{grpc.WithBlock()}
+	if defaultConfig.Insecure {
+		dialOpts = append(dialOpts, grpc.WithInsecure())
+	} else {
+		tlsConfig := &tls.Config{ServerName: defaultConfig.Addr}
+		if defaultConfig.SelfSignedCertification != "" {
+			roots := x509.NewCertPool()
+			if ok := roots.AppendCertsFromPEM([]byte(defaultConfig.SelfSignedCertification)); !ok {
+				return nil, fmt.Errorf("[ERR] TLS Certification Invalid")
+			}
+			tlsConfig.RootCAs = roots
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, fmt.Sprintf("%s:%s", defaultConfig.Addr, defaultConfig.Port), dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx = metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{
+		auth.AuthorizationHeader: auth.Bearer + " " + defaultConfig.Token,
+	}))
+	return fn(ctx, protocol.NewAdminClient(conn))
+}
+
+// adminRun returns commandRun which calls admin api, and prints result as table or json.
+func adminRun(fn func(ctx context.Context, client protocol.AdminClient, args []string) (proto.Message, error),
+	table func(w io.Writer, result proto.Message)) commandRun {
+	return func(cmd *cobra.Command, args []string) {
+		result, err := adminCall(func(ctx context.Context, client protocol.AdminClient) (proto.Message, error) {
+			return fn(ctx, client, args)
+		})
+		if err != nil {
+			log.Println(color.RedString("[ERR] %s", err.Error()))
+			os.Exit(1)
+		}
+
+		if err := printResult(os.Stdout, viper.GetString("output"), result, table); err != nil {
+			log.Println(color.RedString("[ERR] %s", err.Error()))
+			os.Exit(1)
+		}
+	}
+}
+
+// printResult prints result as table or json.
+func printResult(w io.Writer, output string, result proto.Message, table func(w io.Writer, result proto.Message)) error {
+	switch output {
+	case outputJSON:
+		buf, err := protojson.MarshalOptions{Indent: "  ", UseProtoNames: true, EmitUnpopulated: true}.Marshal(result)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(buf))
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		table(tw, result)
+		tw.Flush()
+	}
+	return nil
+}
+
+// formatUnix formats unix seconds.
+func formatUnix(sec int64) string {
+	if sec == 0 {
+		return "-"
+	}
+	return time.Unix(sec, 0).Format(time.RFC3339)
+}
+
+func init() {
+	cobra.OnInitialize(initConfig)
+
+	rootCmd.PersistentFlags().StringP("config", "c", "", "config file path(yaml)")
+	rootCmd.PersistentFlags().StringP("output", "o", outputTable, "output format(table, json)")
+	viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
+	viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+
+	rootCmd.SetHelpCommand(&cobra.Command{
+		Use:    "no-help",
+		Hidden: true,
+	})
+}
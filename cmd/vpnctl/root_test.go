@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		path  string
+		isErr bool
+	}{
+		"success":  {path: "sample.yaml"},
+		"notfound": {path: "allan.yaml", isErr: true},
+	}
+
+	for _, t := range tests {
+		err := setConfig(t.path)
+		assert.Equal(t.isErr, err != nil)
+	}
+}
+
+func TestPrintResult(t *testing.T) {
+	assert := assert.New(t)
+
+	result := &protocol.ListSessionsResponse{
+		ErrorCode: protocol.ErrorCode_EC_SUCCESS,
+		Sessions: []*protocol.Session{
+			{User: "allan", OriginIp: "1.1.1.1", VpnIp: "10.10.10.2", BytesIn: 10, BytesOut: 20,
+				Groups: []string{"dev", "ops"}, Roles: []string{"developer"}},
+		},
+	}
+
+	tests := map[string]struct {
+		output   string
+		contains []string
+	}{
+		"table": {output: outputTable, contains: []string{"USER", "BYTESOUT", "GROUPS", "allan", "10.10.10.2", "dev,ops", "developer"}},
+		"json": {output: outputJSON, contains: []string{`"user":"allan"`, `"vpn_ip":"10.10.10.2"`, `"bytes_out":"20"`,
+			`"groups":["dev","ops"]`, `"roles":["developer"]`}},
+	}
+
+	for _, t := range tests {
+		buf := &bytes.Buffer{}
+		assert.NoError(printResult(buf, t.output, result, printSessions))
+		// protojson randomly adds spaces to output, so spaces are removed before comparing.
+		out := strings.Join(strings.Fields(buf.String()), "")
+		for _, s := range t.contains {
+			assert.Contains(out, s)
+		}
+	}
+}
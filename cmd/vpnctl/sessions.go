@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	sessionsCmd = &cobra.Command{
+		Use:   "sessions",
+		Short: "Manage connected sessions",
+		Long:  "Manage connected sessions",
+	}
+
+	sessionsListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List connected sessions",
+		Long:  "List connected sessions",
+		Args:  cobra.NoArgs,
+		Run:   adminRun(listSessions, printSessions),
+	}
+
+	sessionsKickCmd = &cobra.Command{
+		Use:   "kick",
+		Short: "Kick sessions by user or vpn ip",
+		Long:  "Kick sessions by user or vpn ip",
+		Args:  cobra.NoArgs,
+		Run:   adminRun(kickSession, printKicked),
+	}
+
+	kickUser  string
+	kickVpnIP string
+)
+
+func listSessions(ctx context.Context, client protocol.AdminClient, args []string) (proto.Message, error) {
+	return client.ListSessions(ctx, &protocol.ListSessionsRequest{})
+}
+
+func kickSession(ctx context.Context, client protocol.AdminClient, args []string) (proto.Message, error) {
+	if kickUser == "" && kickVpnIP == "" {
+		return nil, fmt.Errorf("--user or --vpn-ip is required")
+	}
+	return client.KickSession(ctx, &protocol.KickSessionRequest{User: kickUser, VpnIp: kickVpnIP})
+}
+
+func printSessions(w io.Writer, result proto.Message) {
+	fmt.Fprintln(w, "USER\tORIGIN IP\tVPN IP\tVPN IP6\tCONNECTED AT\tBYTES IN\tBYTES OUT\tJWT ID\tGROUPS\tROLES")
+	for _, session := range result.(*protocol.ListSessionsResponse).Sessions {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%d\t%s\t%s\t%s\n", session.User, session.OriginIp, session.VpnIp, session.VpnIp6,
+			formatUnix(session.ConnectedAt), session.BytesIn, session.BytesOut, session.JwtId,
+			strings.Join(session.Groups, ","), strings.Join(session.Roles, ","))
+	}
+}
+
+func printKicked(w io.Writer, result proto.Message) {
+	fmt.Fprintln(w, "KICKED")
+	fmt.Fprintf(w, "%d\n", result.(*protocol.KickSessionResponse).Kicked)
+}
+
+func init() {
+	sessionsKickCmd.Flags().StringVar(&kickUser, "user", "", "user")
+	sessionsKickCmd.Flags().StringVar(&kickVpnIP, "vpn-ip", "", "vpn ip(ipv4 or ipv6)")
+
+	sessionsCmd.AddCommand(sessionsListCmd)
+	sessionsCmd.AddCommand(sessionsKickCmd)
+	rootCmd.AddCommand(sessionsCmd)
+}
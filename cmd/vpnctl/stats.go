@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	statsCmd = &cobra.Command{
+		Use:   "stats",
+		Short: "Show server statistics",
+		Long:  "Show server statistics",
+		Args:  cobra.NoArgs,
+		Run:   adminRun(getStats, printStats),
+	}
+)
+
+func getStats(ctx context.Context, client protocol.AdminClient, args []string) (proto.Message, error) {
+	return client.GetStats(ctx, &protocol.GetStatsRequest{})
+}
+
+func printStats(w io.Writer, result proto.Message) {
+	stats := result.(*protocol.GetStatsResponse)
+	fmt.Fprintln(w, "STARTED AT\tSESSIONS\tACTIVE LEASES\tBYTES IN\tBYTES OUT\tACL DENIED")
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\n", formatUnix(stats.StartedAt), stats.Sessions, stats.ActiveLeases,
+		stats.BytesIn, stats.BytesOut, stats.AclDenied)
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	tokensCmd = &cobra.Command{
+		Use:   "tokens",
+		Short: "Manage jwt tokens",
+		Long:  "Manage jwt tokens",
+	}
+
+	tokensRevokeCmd = &cobra.Command{
+		Use:   "revoke [jwt id]",
+		Short: "Revoke jwt, jwt which has jwt id is refused and sessions using it are kicked",
+		Long:  "Revoke jwt, jwt which has jwt id is refused and sessions using it are kicked",
+		Args:  cobra.ExactArgs(1),
+		Run:   adminRun(revokeToken, printRevokedToken),
+	}
+)
+
+func revokeToken(ctx context.Context, client protocol.AdminClient, args []string) (proto.Message, error) {
+	return client.RevokeToken(ctx, &protocol.RevokeTokenRequest{JwtId: args[0]})
+}
+
+func printRevokedToken(w io.Writer, result proto.Message) {
+	fmt.Fprintln(w, "KICKED")
+	fmt.Fprintf(w, "%d\n", result.(*protocol.RevokeTokenResponse).Kicked)
+}
+
+func init() {
+	tokensCmd.AddCommand(tokensRevokeCmd)
+	rootCmd.AddCommand(tokensCmd)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	usersCmd = &cobra.Command{
+		Use:   "users",
+		Short: "Manage users",
+		Long:  "Manage users",
+	}
+
+	usersRevokeCmd = &cobra.Command{
+		Use:   "revoke [user]",
+		Short: "Revoke user, issued jwt of user is refused and sessions of user are kicked",
+		Long:  "Revoke user, issued jwt of user is refused and sessions of user are kicked",
+		Args:  cobra.ExactArgs(1),
+		Run:   adminRun(revokeUser, printRevoked),
+	}
+)
+
+func revokeUser(ctx context.Context, client protocol.AdminClient, args []string) (proto.Message, error) {
+	return client.RevokeUser(ctx, &protocol.RevokeUserRequest{User: args[0]})
+}
+
+func printRevoked(w io.Writer, result proto.Message) {
+	fmt.Fprintln(w, "KICKED")
+	fmt.Fprintf(w, "%d\n", result.(*protocol.RevokeUserResponse).Kicked)
+}
+
+func init() {
+	usersCmd.AddCommand(usersRevokeCmd)
+	rootCmd.AddCommand(usersCmd)
+}
@@ -32,6 +32,10 @@ const (
 	AuthType_AT_TEST           AuthType = 1 // test
 	AuthType_AT_GOOGLE_OPEN_ID AuthType = 2 // google open id
 	AuthType_AT_AWS_IAM        AuthType = 3 // aws iam
+	AuthType_AT_LDAP           AuthType = 4 // ldap(active directory)
+	AuthType_AT_OPEN_ID        AuthType = 5 // generic openid connect
+	AuthType_AT_MTLS           AuthType = 6 // mutual tls client certificate
+	AuthType_AT_MFA            AuthType = 7 // second factor(totp)
 )
 
 // Enum value maps for AuthType.
@@ -41,12 +45,20 @@ var (
 		1: "AT_TEST",
 		2: "AT_GOOGLE_OPEN_ID",
 		3: "AT_AWS_IAM",
+		4: "AT_LDAP",
+		5: "AT_OPEN_ID",
+		6: "AT_MTLS",
+		7: "AT_MFA",
 	}
 	AuthType_value = map[string]int32{
 		"AT_NONE":           0,
 		"AT_TEST":           1,
 		"AT_GOOGLE_OPEN_ID": 2,
 		"AT_AWS_IAM":        3,
+		"AT_LDAP":           4,
+		"AT_OPEN_ID":        5,
+		"AT_MTLS":           6,
+		"AT_MFA":            7,
 	}
 )
 
@@ -84,6 +96,8 @@ const (
 	ErrorCode_EC_SUCCESS               ErrorCode = 1
 	ErrorCode_EC_INVALID_AUTHORIZATION ErrorCode = 2
 	ErrorCode_EC_EXPIRED_JWT           ErrorCode = 3
+	ErrorCode_EC_REVOKED_JWT           ErrorCode = 4
+	ErrorCode_EC_MFA_REQUIRED          ErrorCode = 5 // primary authentication succeeded, and second factor is required
 )
 
 // Enum value maps for ErrorCode.
@@ -93,12 +107,16 @@ var (
 		1: "EC_SUCCESS",
 		2: "EC_INVALID_AUTHORIZATION",
 		3: "EC_EXPIRED_JWT",
+		4: "EC_REVOKED_JWT",
+		5: "EC_MFA_REQUIRED",
 	}
 	ErrorCode_value = map[string]int32{
 		"EC_UNKNOWN":               0,
 		"EC_SUCCESS":               1,
 		"EC_INVALID_AUTHORIZATION": 2,
 		"EC_EXPIRED_JWT":           3,
+		"EC_REVOKED_JWT":           4,
+		"EC_MFA_REQUIRED":          5,
 	}
 )
 
@@ -135,6 +153,7 @@ const (
 	IPPacketType_IPPT_UNKNOWN    IPPacketType = 0
 	IPPacketType_IPPT_RAW        IPPacketType = 1
 	IPPacketType_IPPT_VPN_ASSIGN IPPacketType = 2
+	IPPacketType_IPPT_RENEW_JWT  IPPacketType = 3
 )
 
 // Enum value maps for IPPacketType.
@@ -143,11 +162,13 @@ var (
 		0: "IPPT_UNKNOWN",
 		1: "IPPT_RAW",
 		2: "IPPT_VPN_ASSIGN",
+		3: "IPPT_RENEW_JWT",
 	}
 	IPPacketType_value = map[string]int32{
 		"IPPT_UNKNOWN":    0,
 		"IPPT_RAW":        1,
 		"IPPT_VPN_ASSIGN": 2,
+		"IPPT_RENEW_JWT":  3,
 	}
 )
 
@@ -183,10 +204,11 @@ type IPPacket struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ErrorCode  ErrorCode     `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=vpn.ErrorCode" json:"error_code,omitempty"`       // error code
-	PacketType IPPacketType  `protobuf:"varint,2,opt,name=packet_type,json=packetType,proto3,enum=vpn.IPPacketType" json:"packet_type,omitempty"` // packet type
-	Packet1    *IPPacket_Raw `protobuf:"bytes,10,opt,name=packet1,proto3" json:"packet1,omitempty"`                                               // raw packet
-	Packet2    *IPPacket_Vpn `protobuf:"bytes,11,opt,name=packet2,proto3" json:"packet2,omitempty"`                                               // vpn packet
+	ErrorCode  ErrorCode       `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=vpn.ErrorCode" json:"error_code,omitempty"`       // error code
+	PacketType IPPacketType    `protobuf:"varint,2,opt,name=packet_type,json=packetType,proto3,enum=vpn.IPPacketType" json:"packet_type,omitempty"` // packet type
+	Packet1    *IPPacket_Raw   `protobuf:"bytes,10,opt,name=packet1,proto3" json:"packet1,omitempty"`                                               // raw packet
+	Packet2    *IPPacket_Vpn   `protobuf:"bytes,11,opt,name=packet2,proto3" json:"packet2,omitempty"`                                               // vpn packet
+	Packet3    *IPPacket_Renew `protobuf:"bytes,12,opt,name=packet3,proto3" json:"packet3,omitempty"`                                               // jwt renewal packet
 }
 
 func (x *IPPacket) Reset() {
@@ -249,14 +271,25 @@ func (x *IPPacket) GetPacket2() *IPPacket_Vpn {
 	return nil
 }
 
+func (x *IPPacket) GetPacket3() *IPPacket_Renew {
+	if x != nil {
+		return x.Packet3
+	}
+	return nil
+}
+
 type AuthRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	AuthType     AuthType                  `protobuf:"varint,1,opt,name=auth_type,json=authType,proto3,enum=vpn.AuthType" json:"auth_type,omitempty"` // auth type
-	GoogleOpenId *AuthRequest_GoogleOpenID `protobuf:"bytes,2,opt,name=google_open_id,json=googleOpenId,proto3" json:"google_open_id,omitempty"`      // support google openid connect
-	AwsIam       *AuthRequest_AwsIam       `protobuf:"bytes,3,opt,name=aws_iam,json=awsIam,proto3" json:"aws_iam,omitempty"`                          // support aws iam
+	AuthType     AuthType                  `protobuf:"varint,1,opt,name=auth_type,json=authType,proto3,enum=vpn.AuthType" json:"auth_type,omitempty"`           // auth type
+	GoogleOpenId *AuthRequest_GoogleOpenID `protobuf:"bytes,2,opt,name=google_open_id,json=googleOpenId,proto3" json:"google_open_id,omitempty"`                // support google openid connect
+	AwsIam       *AuthRequest_AwsIam       `protobuf:"bytes,3,opt,name=aws_iam,json=awsIam,proto3" json:"aws_iam,omitempty"`                                    // support aws iam
+	Ldap         *AuthRequest_Ldap         `protobuf:"bytes,4,opt,name=ldap,proto3" json:"ldap,omitempty"`                                                      // support ldap(active directory)
+	OpenId       *AuthRequest_OpenID       `protobuf:"bytes,5,opt,name=open_id,json=openId,proto3" json:"open_id,omitempty"`                                    // support generic openid connect
+	Mfa          *AuthRequest_Mfa          `protobuf:"bytes,6,opt,name=mfa,proto3" json:"mfa,omitempty"`                                                        // second factor for challenge of primary authentication
+	AuthTypes    []AuthType                `protobuf:"varint,7,rep,packed,name=auth_types,json=authTypes,proto3,enum=vpn.AuthType" json:"auth_types,omitempty"` // auth types of all credentials in request, if auth policy requires all of them
 }
 
 func (x *AuthRequest) Reset() {
@@ -312,13 +345,43 @@ func (x *AuthRequest) GetAwsIam() *AuthRequest_AwsIam {
 	return nil
 }
 
+func (x *AuthRequest) GetLdap() *AuthRequest_Ldap {
+	if x != nil {
+		return x.Ldap
+	}
+	return nil
+}
+
+func (x *AuthRequest) GetOpenId() *AuthRequest_OpenID {
+	if x != nil {
+		return x.OpenId
+	}
+	return nil
+}
+
+func (x *AuthRequest) GetMfa() *AuthRequest_Mfa {
+	if x != nil {
+		return x.Mfa
+	}
+	return nil
+}
+
+func (x *AuthRequest) GetAuthTypes() []AuthType {
+	if x != nil {
+		return x.AuthTypes
+	}
+	return nil
+}
+
 type AuthResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ErrorCode ErrorCode `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=vpn.ErrorCode" json:"error_code,omitempty"` // error code
-	Jwt       string    `protobuf:"bytes,2,opt,name=jwt,proto3" json:"jwt,omitempty"`                                                  // jwt
+	ErrorCode      ErrorCode `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=vpn.ErrorCode" json:"error_code,omitempty"` // error code
+	Jwt            string    `protobuf:"bytes,2,opt,name=jwt,proto3" json:"jwt,omitempty"`                                                  // jwt
+	RefreshToken   string    `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`            // refresh token for renewing jwt
+	MfaChallengeId string    `protobuf:"bytes,4,opt,name=mfa_challenge_id,json=mfaChallengeId,proto3" json:"mfa_challenge_id,omitempty"`    // challenge id for second factor if error code is EC_MFA_REQUIRED
 }
 
 func (x *AuthResponse) Reset() {
@@ -367,16 +430,41 @@ func (x *AuthResponse) GetJwt() string {
 	return ""
 }
 
-type IPPacket_Raw struct {
+func (x *AuthResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *AuthResponse) GetMfaChallengeId() string {
+	if x != nil {
+		return x.MfaChallengeId
+	}
+	return ""
+}
+
+type Session struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Raw []byte `protobuf:"bytes,1,opt,name=raw,proto3" json:"raw,omitempty"` // raw packet
-}
-
-func (x *IPPacket_Raw) Reset() {
-	*x = IPPacket_Raw{}
+	User        string            `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`                                                                                                      // user
+	OriginIp    string            `protobuf:"bytes,2,opt,name=origin_ip,json=originIp,proto3" json:"origin_ip,omitempty"`                                                                              // origin ip
+	VpnIp       string            `protobuf:"bytes,3,opt,name=vpn_ip,json=vpnIp,proto3" json:"vpn_ip,omitempty"`                                                                                       // vpn ip
+	VpnIp6      string            `protobuf:"bytes,4,opt,name=vpn_ip6,json=vpnIp6,proto3" json:"vpn_ip6,omitempty"`                                                                                    // vpn ipv6
+	ConnectedAt int64             `protobuf:"varint,5,opt,name=connected_at,json=connectedAt,proto3" json:"connected_at,omitempty"`                                                                    // connected time(unix seconds)
+	BytesIn     uint64            `protobuf:"varint,6,opt,name=bytes_in,json=bytesIn,proto3" json:"bytes_in,omitempty"`                                                                                // bytes from client
+	BytesOut    uint64            `protobuf:"varint,7,opt,name=bytes_out,json=bytesOut,proto3" json:"bytes_out,omitempty"`                                                                             // bytes to client
+	JwtId       string            `protobuf:"bytes,8,opt,name=jwt_id,json=jwtId,proto3" json:"jwt_id,omitempty"`                                                                                       // jwt id(jti)
+	Principal   string            `protobuf:"bytes,9,opt,name=principal,proto3" json:"principal,omitempty"`                                                                                            // principal given by auth method
+	Groups      []string          `protobuf:"bytes,10,rep,name=groups,proto3" json:"groups,omitempty"`                                                                                                 // groups
+	Roles       []string          `protobuf:"bytes,11,rep,name=roles,proto3" json:"roles,omitempty"`                                                                                                   // roles given by auth method
+	Attributes  map[string]string `protobuf:"bytes,12,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // attributes given by auth method
+}
+
+func (x *Session) Reset() {
+	*x = Session{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_vpn_struct_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -384,13 +472,13 @@ func (x *IPPacket_Raw) Reset() {
 	}
 }
 
-func (x *IPPacket_Raw) String() string {
+func (x *Session) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IPPacket_Raw) ProtoMessage() {}
+func (*Session) ProtoMessage() {}
 
-func (x *IPPacket_Raw) ProtoReflect() protoreflect.Message {
+func (x *Session) ProtoReflect() protoreflect.Message {
 	mi := &file_vpn_struct_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -402,31 +490,103 @@ func (x *IPPacket_Raw) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IPPacket_Raw.ProtoReflect.Descriptor instead.
-func (*IPPacket_Raw) Descriptor() ([]byte, []int) {
-	return file_vpn_struct_proto_rawDescGZIP(), []int{0, 0}
+// Deprecated: Use Session.ProtoReflect.Descriptor instead.
+func (*Session) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *IPPacket_Raw) GetRaw() []byte {
+func (x *Session) GetUser() string {
 	if x != nil {
-		return x.Raw
+		return x.User
+	}
+	return ""
+}
+
+func (x *Session) GetOriginIp() string {
+	if x != nil {
+		return x.OriginIp
+	}
+	return ""
+}
+
+func (x *Session) GetVpnIp() string {
+	if x != nil {
+		return x.VpnIp
+	}
+	return ""
+}
+
+func (x *Session) GetVpnIp6() string {
+	if x != nil {
+		return x.VpnIp6
+	}
+	return ""
+}
+
+func (x *Session) GetConnectedAt() int64 {
+	if x != nil {
+		return x.ConnectedAt
+	}
+	return 0
+}
+
+func (x *Session) GetBytesIn() uint64 {
+	if x != nil {
+		return x.BytesIn
+	}
+	return 0
+}
+
+func (x *Session) GetBytesOut() uint64 {
+	if x != nil {
+		return x.BytesOut
+	}
+	return 0
+}
+
+func (x *Session) GetJwtId() string {
+	if x != nil {
+		return x.JwtId
+	}
+	return ""
+}
+
+func (x *Session) GetPrincipal() string {
+	if x != nil {
+		return x.Principal
+	}
+	return ""
+}
+
+func (x *Session) GetGroups() []string {
+	if x != nil {
+		return x.Groups
 	}
 	return nil
 }
 
-type IPPacket_Vpn struct {
+func (x *Session) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+func (x *Session) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+type ListSessionsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	VpnAssignedIp []byte `protobuf:"bytes,1,opt,name=vpn_assigned_ip,json=vpnAssignedIp,proto3" json:"vpn_assigned_ip,omitempty"` // vpn  assigned ip
-	VpnGateway    []byte `protobuf:"bytes,2,opt,name=vpn_gateway,json=vpnGateway,proto3" json:"vpn_gateway,omitempty"`            // vpn gateway
-	VpnSubnetIp   []byte `protobuf:"bytes,3,opt,name=vpn_subnet_ip,json=vpnSubnetIp,proto3" json:"vpn_subnet_ip,omitempty"`       // vpn subnet ip
-	VpnSubnetMask []byte `protobuf:"bytes,4,opt,name=vpn_subnet_mask,json=vpnSubnetMask,proto3" json:"vpn_subnet_mask,omitempty"` // vpn subnet mask
 }
 
-func (x *IPPacket_Vpn) Reset() {
-	*x = IPPacket_Vpn{}
+func (x *ListSessionsRequest) Reset() {
+	*x = ListSessionsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_vpn_struct_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -434,13 +594,13 @@ func (x *IPPacket_Vpn) Reset() {
 	}
 }
 
-func (x *IPPacket_Vpn) String() string {
+func (x *ListSessionsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IPPacket_Vpn) ProtoMessage() {}
+func (*ListSessionsRequest) ProtoMessage() {}
 
-func (x *IPPacket_Vpn) ProtoReflect() protoreflect.Message {
+func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_vpn_struct_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -452,64 +612,92 @@ func (x *IPPacket_Vpn) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IPPacket_Vpn.ProtoReflect.Descriptor instead.
-func (*IPPacket_Vpn) Descriptor() ([]byte, []int) {
-	return file_vpn_struct_proto_rawDescGZIP(), []int{0, 1}
+// Deprecated: Use ListSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *IPPacket_Vpn) GetVpnAssignedIp() []byte {
-	if x != nil {
-		return x.VpnAssignedIp
+type ListSessionsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ErrorCode ErrorCode  `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=vpn.ErrorCode" json:"error_code,omitempty"` // error code
+	Sessions  []*Session `protobuf:"bytes,2,rep,name=sessions,proto3" json:"sessions,omitempty"`                                        // sessions
+}
+
+func (x *ListSessionsResponse) Reset() {
+	*x = ListSessionsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *IPPacket_Vpn) GetVpnGateway() []byte {
-	if x != nil {
-		return x.VpnGateway
+func (x *ListSessionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSessionsResponse) ProtoMessage() {}
+
+func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *IPPacket_Vpn) GetVpnSubnetIp() []byte {
+// Deprecated: Use ListSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListSessionsResponse) GetErrorCode() ErrorCode {
 	if x != nil {
-		return x.VpnSubnetIp
+		return x.ErrorCode
 	}
-	return nil
+	return ErrorCode_EC_UNKNOWN
 }
 
-func (x *IPPacket_Vpn) GetVpnSubnetMask() []byte {
+func (x *ListSessionsResponse) GetSessions() []*Session {
 	if x != nil {
-		return x.VpnSubnetMask
+		return x.Sessions
 	}
 	return nil
 }
 
-type AuthRequest_GoogleOpenID struct {
+type KickSessionRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	User  string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`                // user
+	VpnIp string `protobuf:"bytes,2,opt,name=vpn_ip,json=vpnIp,proto3" json:"vpn_ip,omitempty"` // vpn ip(ipv4 or ipv6)
 }
 
-func (x *AuthRequest_GoogleOpenID) Reset() {
-	*x = AuthRequest_GoogleOpenID{}
+func (x *KickSessionRequest) Reset() {
+	*x = KickSessionRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_vpn_struct_proto_msgTypes[5]
+		mi := &file_vpn_struct_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AuthRequest_GoogleOpenID) String() string {
+func (x *KickSessionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AuthRequest_GoogleOpenID) ProtoMessage() {}
+func (*KickSessionRequest) ProtoMessage() {}
 
-func (x *AuthRequest_GoogleOpenID) ProtoReflect() protoreflect.Message {
-	mi := &file_vpn_struct_proto_msgTypes[5]
+func (x *KickSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -520,44 +708,51 @@ func (x *AuthRequest_GoogleOpenID) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AuthRequest_GoogleOpenID.ProtoReflect.Descriptor instead.
-func (*AuthRequest_GoogleOpenID) Descriptor() ([]byte, []int) {
-	return file_vpn_struct_proto_rawDescGZIP(), []int{1, 0}
+// Deprecated: Use KickSessionRequest.ProtoReflect.Descriptor instead.
+func (*KickSessionRequest) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *AuthRequest_GoogleOpenID) GetCode() string {
+func (x *KickSessionRequest) GetUser() string {
 	if x != nil {
-		return x.Code
+		return x.User
 	}
 	return ""
 }
 
-type AuthRequest_AwsIam struct {
+func (x *KickSessionRequest) GetVpnIp() string {
+	if x != nil {
+		return x.VpnIp
+	}
+	return ""
+}
+
+type KickSessionResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	AccessKey       string `protobuf:"bytes,1,opt,name=access_key,json=accessKey,proto3" json:"access_key,omitempty"`
-	SecretAccessKey string `protobuf:"bytes,2,opt,name=secret_access_key,json=secretAccessKey,proto3" json:"secret_access_key,omitempty"`
+	ErrorCode ErrorCode `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=vpn.ErrorCode" json:"error_code,omitempty"` // error code
+	Kicked    int32     `protobuf:"varint,2,opt,name=kicked,proto3" json:"kicked,omitempty"`                                           // the number of kicked sessions
 }
 
-func (x *AuthRequest_AwsIam) Reset() {
-	*x = AuthRequest_AwsIam{}
+func (x *KickSessionResponse) Reset() {
+	*x = KickSessionResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_vpn_struct_proto_msgTypes[6]
+		mi := &file_vpn_struct_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AuthRequest_AwsIam) String() string {
+func (x *KickSessionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AuthRequest_AwsIam) ProtoMessage() {}
+func (*KickSessionResponse) ProtoMessage() {}
 
-func (x *AuthRequest_AwsIam) ProtoReflect() protoreflect.Message {
-	mi := &file_vpn_struct_proto_msgTypes[6]
+func (x *KickSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -568,150 +763,1447 @@ func (x *AuthRequest_AwsIam) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AuthRequest_AwsIam.ProtoReflect.Descriptor instead.
-func (*AuthRequest_AwsIam) Descriptor() ([]byte, []int) {
-	return file_vpn_struct_proto_rawDescGZIP(), []int{1, 1}
+// Deprecated: Use KickSessionResponse.ProtoReflect.Descriptor instead.
+func (*KickSessionResponse) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *AuthRequest_AwsIam) GetAccessKey() string {
+func (x *KickSessionResponse) GetErrorCode() ErrorCode {
 	if x != nil {
-		return x.AccessKey
+		return x.ErrorCode
 	}
-	return ""
+	return ErrorCode_EC_UNKNOWN
 }
 
-func (x *AuthRequest_AwsIam) GetSecretAccessKey() string {
+func (x *KickSessionResponse) GetKicked() int32 {
 	if x != nil {
-		return x.SecretAccessKey
+		return x.Kicked
 	}
-	return ""
+	return 0
 }
 
-var File_vpn_struct_proto protoreflect.FileDescriptor
+type RevokeUserRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_vpn_struct_proto_rawDesc = []byte{
-	0x0a, 0x10, 0x76, 0x70, 0x6e, 0x2d, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x12, 0x03, 0x76, 0x70, 0x6e, 0x22, 0xfd, 0x02, 0x0a, 0x08, 0x49, 0x50, 0x50, 0x61,
-	0x63, 0x6b, 0x65, 0x74, 0x12, 0x2d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f,
-	0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x45,
-	0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43,
-	0x6f, 0x64, 0x65, 0x12, 0x32, 0x0a, 0x0b, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x74, 0x79,
-	0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x49,
-	0x50, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0a, 0x70, 0x61, 0x63,
-	0x6b, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2b, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x65,
-	0x74, 0x31, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x49,
-	0x50, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x2e, 0x52, 0x61, 0x77, 0x52, 0x07, 0x70, 0x61, 0x63,
-	0x6b, 0x65, 0x74, 0x31, 0x12, 0x2b, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x32, 0x18,
-	0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x49, 0x50, 0x50, 0x61,
-	0x63, 0x6b, 0x65, 0x74, 0x2e, 0x56, 0x70, 0x6e, 0x52, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74,
-	0x32, 0x1a, 0x17, 0x0a, 0x03, 0x52, 0x61, 0x77, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x61, 0x77, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x72, 0x61, 0x77, 0x1a, 0x9a, 0x01, 0x0a, 0x03, 0x56,
-	0x70, 0x6e, 0x12, 0x26, 0x0a, 0x0f, 0x76, 0x70, 0x6e, 0x5f, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e,
-	0x65, 0x64, 0x5f, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x76, 0x70, 0x6e,
-	0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x49, 0x70, 0x12, 0x1f, 0x0a, 0x0b, 0x76, 0x70,
-	0x6e, 0x5f, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x0a, 0x76, 0x70, 0x6e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x22, 0x0a, 0x0d, 0x76,
-	0x70, 0x6e, 0x5f, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x5f, 0x69, 0x70, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x0b, 0x76, 0x70, 0x6e, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x49, 0x70, 0x12,
-	0x26, 0x0a, 0x0f, 0x76, 0x70, 0x6e, 0x5f, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x5f, 0x6d, 0x61,
-	0x73, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x76, 0x70, 0x6e, 0x53, 0x75, 0x62,
-	0x6e, 0x65, 0x74, 0x4d, 0x61, 0x73, 0x6b, 0x22, 0xa9, 0x02, 0x0a, 0x0b, 0x41, 0x75, 0x74, 0x68,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x09, 0x61, 0x75, 0x74, 0x68, 0x5f,
-	0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x76, 0x70, 0x6e,
-	0x2e, 0x41, 0x75, 0x74, 0x68, 0x54, 0x79, 0x70, 0x65, 0x52, 0x08, 0x61, 0x75, 0x74, 0x68, 0x54,
-	0x79, 0x70, 0x65, 0x12, 0x43, 0x0a, 0x0e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x5f, 0x6f, 0x70,
-	0x65, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x76, 0x70,
-	0x6e, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x47, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x4f, 0x70, 0x65, 0x6e, 0x49, 0x44, 0x52, 0x0c, 0x67, 0x6f, 0x6f, 0x67,
-	0x6c, 0x65, 0x4f, 0x70, 0x65, 0x6e, 0x49, 0x64, 0x12, 0x30, 0x0a, 0x07, 0x61, 0x77, 0x73, 0x5f,
-	0x69, 0x61, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x76, 0x70, 0x6e, 0x2e,
-	0x41, 0x75, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x41, 0x77, 0x73, 0x49,
-	0x61, 0x6d, 0x52, 0x06, 0x61, 0x77, 0x73, 0x49, 0x61, 0x6d, 0x1a, 0x22, 0x0a, 0x0c, 0x47, 0x6f,
-	0x6f, 0x67, 0x6c, 0x65, 0x4f, 0x70, 0x65, 0x6e, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f,
-	0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x1a, 0x53,
-	0x0a, 0x06, 0x41, 0x77, 0x73, 0x49, 0x61, 0x6d, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x63, 0x63, 0x65,
-	0x73, 0x73, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61, 0x63,
-	0x63, 0x65, 0x73, 0x73, 0x4b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x11, 0x73, 0x65, 0x63, 0x72, 0x65,
-	0x74, 0x5f, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73,
-	0x4b, 0x65, 0x79, 0x22, 0x4f, 0x0a, 0x0c, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x45, 0x72,
-	0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f,
-	0x64, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6a, 0x77, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x03, 0x6a, 0x77, 0x74, 0x2a, 0x4b, 0x0a, 0x08, 0x41, 0x75, 0x74, 0x68, 0x54, 0x79, 0x70, 0x65,
-	0x12, 0x0b, 0x0a, 0x07, 0x41, 0x54, 0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a,
-	0x07, 0x41, 0x54, 0x5f, 0x54, 0x45, 0x53, 0x54, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x41, 0x54,
-	0x5f, 0x47, 0x4f, 0x4f, 0x47, 0x4c, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x4e, 0x5f, 0x49, 0x44, 0x10,
-	0x02, 0x12, 0x0e, 0x0a, 0x0a, 0x41, 0x54, 0x5f, 0x41, 0x57, 0x53, 0x5f, 0x49, 0x41, 0x4d, 0x10,
-	0x03, 0x2a, 0x5d, 0x0a, 0x09, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x0e,
-	0x0a, 0x0a, 0x45, 0x43, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0e,
-	0x0a, 0x0a, 0x45, 0x43, 0x5f, 0x53, 0x55, 0x43, 0x43, 0x45, 0x53, 0x53, 0x10, 0x01, 0x12, 0x1c,
-	0x0a, 0x18, 0x45, 0x43, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x41, 0x55, 0x54,
-	0x48, 0x4f, 0x52, 0x49, 0x5a, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e,
-	0x45, 0x43, 0x5f, 0x45, 0x58, 0x50, 0x49, 0x52, 0x45, 0x44, 0x5f, 0x4a, 0x57, 0x54, 0x10, 0x03,
-	0x2a, 0x43, 0x0a, 0x0c, 0x49, 0x50, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65,
-	0x12, 0x10, 0x0a, 0x0c, 0x49, 0x50, 0x50, 0x54, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e,
-	0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x49, 0x50, 0x50, 0x54, 0x5f, 0x52, 0x41, 0x57, 0x10, 0x01,
-	0x12, 0x13, 0x0a, 0x0f, 0x49, 0x50, 0x50, 0x54, 0x5f, 0x56, 0x50, 0x4e, 0x5f, 0x41, 0x53, 0x53,
-	0x49, 0x47, 0x4e, 0x10, 0x02, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	User string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"` // user
 }
 
-var (
-	file_vpn_struct_proto_rawDescOnce sync.Once
-	file_vpn_struct_proto_rawDescData = file_vpn_struct_proto_rawDesc
-)
+func (x *RevokeUserRequest) Reset() {
+	*x = RevokeUserRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_vpn_struct_proto_rawDescGZIP() []byte {
-	file_vpn_struct_proto_rawDescOnce.Do(func() {
-		file_vpn_struct_proto_rawDescData = protoimpl.X.CompressGZIP(file_vpn_struct_proto_rawDescData)
-	})
-	return file_vpn_struct_proto_rawDescData
+func (x *RevokeUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_vpn_struct_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_vpn_struct_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
-var file_vpn_struct_proto_goTypes = []interface{}{
-	(AuthType)(0),                    // 0: vpn.AuthType
-	(ErrorCode)(0),                   // 1: vpn.ErrorCode
-	(IPPacketType)(0),                // 2: vpn.IPPacketType
-	(*IPPacket)(nil),                 // 3: vpn.IPPacket
-	(*AuthRequest)(nil),              // 4: vpn.AuthRequest
-	(*AuthResponse)(nil),             // 5: vpn.AuthResponse
-	(*IPPacket_Raw)(nil),             // 6: vpn.IPPacket.Raw
-	(*IPPacket_Vpn)(nil),             // 7: vpn.IPPacket.Vpn
-	(*AuthRequest_GoogleOpenID)(nil), // 8: vpn.AuthRequest.GoogleOpenID
-	(*AuthRequest_AwsIam)(nil),       // 9: vpn.AuthRequest.AwsIam
+func (*RevokeUserRequest) ProtoMessage() {}
+
+func (x *RevokeUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_vpn_struct_proto_depIdxs = []int32{
-	1, // 0: vpn.IPPacket.error_code:type_name -> vpn.ErrorCode
-	2, // 1: vpn.IPPacket.packet_type:type_name -> vpn.IPPacketType
-	6, // 2: vpn.IPPacket.packet1:type_name -> vpn.IPPacket.Raw
-	7, // 3: vpn.IPPacket.packet2:type_name -> vpn.IPPacket.Vpn
-	0, // 4: vpn.AuthRequest.auth_type:type_name -> vpn.AuthType
-	8, // 5: vpn.AuthRequest.google_open_id:type_name -> vpn.AuthRequest.GoogleOpenID
-	9, // 6: vpn.AuthRequest.aws_iam:type_name -> vpn.AuthRequest.AwsIam
-	1, // 7: vpn.AuthResponse.error_code:type_name -> vpn.ErrorCode
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	8, // [8:8] is the sub-list for extension extendee
-	0, // [0:8] is the sub-list for field type_name
+
+// Deprecated: Use RevokeUserRequest.ProtoReflect.Descriptor instead.
+func (*RevokeUserRequest) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{8}
 }
 
-func init() { file_vpn_struct_proto_init() }
-func file_vpn_struct_proto_init() {
-	if File_vpn_struct_proto != nil {
-		return
+func (x *RevokeUserRequest) GetUser() string {
+	if x != nil {
+		return x.User
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_vpn_struct_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*IPPacket); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
+	return ""
+}
+
+type RevokeUserResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ErrorCode ErrorCode `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=vpn.ErrorCode" json:"error_code,omitempty"` // error code
+	Kicked    int32     `protobuf:"varint,2,opt,name=kicked,proto3" json:"kicked,omitempty"`                                           // the number of kicked sessions
+}
+
+func (x *RevokeUserResponse) Reset() {
+	*x = RevokeUserResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevokeUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeUserResponse) ProtoMessage() {}
+
+func (x *RevokeUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeUserResponse.ProtoReflect.Descriptor instead.
+func (*RevokeUserResponse) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RevokeUserResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_EC_UNKNOWN
+}
+
+func (x *RevokeUserResponse) GetKicked() int32 {
+	if x != nil {
+		return x.Kicked
+	}
+	return 0
+}
+
+type RevokeTokenRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JwtId string `protobuf:"bytes,1,opt,name=jwt_id,json=jwtId,proto3" json:"jwt_id,omitempty"` // jwt id(jti)
+}
+
+func (x *RevokeTokenRequest) Reset() {
+	*x = RevokeTokenRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevokeTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeTokenRequest) ProtoMessage() {}
+
+func (x *RevokeTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeTokenRequest.ProtoReflect.Descriptor instead.
+func (*RevokeTokenRequest) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RevokeTokenRequest) GetJwtId() string {
+	if x != nil {
+		return x.JwtId
+	}
+	return ""
+}
+
+type RevokeTokenResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ErrorCode ErrorCode `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=vpn.ErrorCode" json:"error_code,omitempty"` // error code
+	Kicked    int32     `protobuf:"varint,2,opt,name=kicked,proto3" json:"kicked,omitempty"`                                           // the number of kicked sessions
+}
+
+func (x *RevokeTokenResponse) Reset() {
+	*x = RevokeTokenResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RevokeTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeTokenResponse) ProtoMessage() {}
+
+func (x *RevokeTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeTokenResponse.ProtoReflect.Descriptor instead.
+func (*RevokeTokenResponse) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RevokeTokenResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_EC_UNKNOWN
+}
+
+func (x *RevokeTokenResponse) GetKicked() int32 {
+	if x != nil {
+		return x.Kicked
+	}
+	return 0
+}
+
+type Lease struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User      string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`                             // user
+	Ip        string `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`                                 // vpn ip
+	Active    bool   `protobuf:"varint,3,opt,name=active,proto3" json:"active,omitempty"`                        // whether ip is used or not
+	Reserved  bool   `protobuf:"varint,4,opt,name=reserved,proto3" json:"reserved,omitempty"`                    // whether ip is reserved to user or not
+	ExpiredAt int64  `protobuf:"varint,5,opt,name=expired_at,json=expiredAt,proto3" json:"expired_at,omitempty"` // expired time after released(unix seconds)
+}
+
+func (x *Lease) Reset() {
+	*x = Lease{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Lease) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lease) ProtoMessage() {}
+
+func (x *Lease) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lease.ProtoReflect.Descriptor instead.
+func (*Lease) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Lease) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *Lease) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Lease) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *Lease) GetReserved() bool {
+	if x != nil {
+		return x.Reserved
+	}
+	return false
+}
+
+func (x *Lease) GetExpiredAt() int64 {
+	if x != nil {
+		return x.ExpiredAt
+	}
+	return 0
+}
+
+type ListLeasesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListLeasesRequest) Reset() {
+	*x = ListLeasesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListLeasesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLeasesRequest) ProtoMessage() {}
+
+func (x *ListLeasesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLeasesRequest.ProtoReflect.Descriptor instead.
+func (*ListLeasesRequest) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{13}
+}
+
+type ListLeasesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ErrorCode ErrorCode `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=vpn.ErrorCode" json:"error_code,omitempty"` // error code
+	Leases    []*Lease  `protobuf:"bytes,2,rep,name=leases,proto3" json:"leases,omitempty"`                                            // leases
+}
+
+func (x *ListLeasesResponse) Reset() {
+	*x = ListLeasesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListLeasesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLeasesResponse) ProtoMessage() {}
+
+func (x *ListLeasesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLeasesResponse.ProtoReflect.Descriptor instead.
+func (*ListLeasesResponse) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListLeasesResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_EC_UNKNOWN
+}
+
+func (x *ListLeasesResponse) GetLeases() []*Lease {
+	if x != nil {
+		return x.Leases
+	}
+	return nil
+}
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{15}
+}
+
+type GetStatsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ErrorCode    ErrorCode `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=vpn.ErrorCode" json:"error_code,omitempty"` // error code
+	StartedAt    int64     `protobuf:"varint,2,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`                    // server started time(unix seconds)
+	Sessions     int32     `protobuf:"varint,3,opt,name=sessions,proto3" json:"sessions,omitempty"`                                       // the number of connected sessions
+	ActiveLeases int32     `protobuf:"varint,4,opt,name=active_leases,json=activeLeases,proto3" json:"active_leases,omitempty"`           // the number of active leases
+	BytesIn      uint64    `protobuf:"varint,5,opt,name=bytes_in,json=bytesIn,proto3" json:"bytes_in,omitempty"`                          // bytes from connected sessions
+	BytesOut     uint64    `protobuf:"varint,6,opt,name=bytes_out,json=bytesOut,proto3" json:"bytes_out,omitempty"`                       // bytes to connected sessions
+	AclDenied    uint64    `protobuf:"varint,7,opt,name=acl_denied,json=aclDenied,proto3" json:"acl_denied,omitempty"`                    // the number of packets denied by acl
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetStatsResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_EC_UNKNOWN
+}
+
+func (x *GetStatsResponse) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetSessions() int32 {
+	if x != nil {
+		return x.Sessions
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetActiveLeases() int32 {
+	if x != nil {
+		return x.ActiveLeases
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetBytesIn() uint64 {
+	if x != nil {
+		return x.BytesIn
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetBytesOut() uint64 {
+	if x != nil {
+		return x.BytesOut
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetAclDenied() uint64 {
+	if x != nil {
+		return x.AclDenied
+	}
+	return 0
+}
+
+type IPPacket_Raw struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Raw []byte `protobuf:"bytes,1,opt,name=raw,proto3" json:"raw,omitempty"` // raw packet
+}
+
+func (x *IPPacket_Raw) Reset() {
+	*x = IPPacket_Raw{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IPPacket_Raw) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPPacket_Raw) ProtoMessage() {}
+
+func (x *IPPacket_Raw) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPPacket_Raw.ProtoReflect.Descriptor instead.
+func (*IPPacket_Raw) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *IPPacket_Raw) GetRaw() []byte {
+	if x != nil {
+		return x.Raw
+	}
+	return nil
+}
+
+type IPPacket_Vpn struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	VpnAssignedIp    []byte   `protobuf:"bytes,1,opt,name=vpn_assigned_ip,json=vpnAssignedIp,proto3" json:"vpn_assigned_ip,omitempty"`           // vpn  assigned ip
+	VpnGateway       []byte   `protobuf:"bytes,2,opt,name=vpn_gateway,json=vpnGateway,proto3" json:"vpn_gateway,omitempty"`                      // vpn gateway
+	VpnSubnetIp      []byte   `protobuf:"bytes,3,opt,name=vpn_subnet_ip,json=vpnSubnetIp,proto3" json:"vpn_subnet_ip,omitempty"`                 // vpn subnet ip
+	VpnSubnetMask    []byte   `protobuf:"bytes,4,opt,name=vpn_subnet_mask,json=vpnSubnetMask,proto3" json:"vpn_subnet_mask,omitempty"`           // vpn subnet mask
+	VpnAssignedIp6   []byte   `protobuf:"bytes,5,opt,name=vpn_assigned_ip6,json=vpnAssignedIp6,proto3" json:"vpn_assigned_ip6,omitempty"`        // vpn assigned ipv6
+	VpnGateway6      []byte   `protobuf:"bytes,6,opt,name=vpn_gateway6,json=vpnGateway6,proto3" json:"vpn_gateway6,omitempty"`                   // vpn ipv6 gateway
+	VpnSubnetIp6     []byte   `protobuf:"bytes,7,opt,name=vpn_subnet_ip6,json=vpnSubnetIp6,proto3" json:"vpn_subnet_ip6,omitempty"`              // vpn ipv6 subnet ip
+	VpnSubnetMask6   []byte   `protobuf:"bytes,8,opt,name=vpn_subnet_mask6,json=vpnSubnetMask6,proto3" json:"vpn_subnet_mask6,omitempty"`        // vpn ipv6 subnet mask
+	Routes           []string `protobuf:"bytes,9,rep,name=routes,proto3" json:"routes,omitempty"`                                                // routes(cidr) flowing through vpn (split tunneling)
+	FullTunnel       bool     `protobuf:"varint,10,opt,name=full_tunnel,json=fullTunnel,proto3" json:"full_tunnel,omitempty"`                    // whether all traffic flows through vpn or not
+	DnsServers       []string `protobuf:"bytes,11,rep,name=dns_servers,json=dnsServers,proto3" json:"dns_servers,omitempty"`                     // dns servers which clients use
+	DnsSearchDomains []string `protobuf:"bytes,12,rep,name=dns_search_domains,json=dnsSearchDomains,proto3" json:"dns_search_domains,omitempty"` // dns search domains which clients use
+	SessionId        string   `protobuf:"bytes,13,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`                        // session id which is used to resume session after disconnection
+	Resumed          bool     `protobuf:"varint,14,opt,name=resumed,proto3" json:"resumed,omitempty"`                                            // whether session is resumed with same vpn ips or not
+}
+
+func (x *IPPacket_Vpn) Reset() {
+	*x = IPPacket_Vpn{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IPPacket_Vpn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPPacket_Vpn) ProtoMessage() {}
+
+func (x *IPPacket_Vpn) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPPacket_Vpn.ProtoReflect.Descriptor instead.
+func (*IPPacket_Vpn) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{0, 1}
+}
+
+func (x *IPPacket_Vpn) GetVpnAssignedIp() []byte {
+	if x != nil {
+		return x.VpnAssignedIp
+	}
+	return nil
+}
+
+func (x *IPPacket_Vpn) GetVpnGateway() []byte {
+	if x != nil {
+		return x.VpnGateway
+	}
+	return nil
+}
+
+func (x *IPPacket_Vpn) GetVpnSubnetIp() []byte {
+	if x != nil {
+		return x.VpnSubnetIp
+	}
+	return nil
+}
+
+func (x *IPPacket_Vpn) GetVpnSubnetMask() []byte {
+	if x != nil {
+		return x.VpnSubnetMask
+	}
+	return nil
+}
+
+func (x *IPPacket_Vpn) GetVpnAssignedIp6() []byte {
+	if x != nil {
+		return x.VpnAssignedIp6
+	}
+	return nil
+}
+
+func (x *IPPacket_Vpn) GetVpnGateway6() []byte {
+	if x != nil {
+		return x.VpnGateway6
+	}
+	return nil
+}
+
+func (x *IPPacket_Vpn) GetVpnSubnetIp6() []byte {
+	if x != nil {
+		return x.VpnSubnetIp6
+	}
+	return nil
+}
+
+func (x *IPPacket_Vpn) GetVpnSubnetMask6() []byte {
+	if x != nil {
+		return x.VpnSubnetMask6
+	}
+	return nil
+}
+
+func (x *IPPacket_Vpn) GetRoutes() []string {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+func (x *IPPacket_Vpn) GetFullTunnel() bool {
+	if x != nil {
+		return x.FullTunnel
+	}
+	return false
+}
+
+func (x *IPPacket_Vpn) GetDnsServers() []string {
+	if x != nil {
+		return x.DnsServers
+	}
+	return nil
+}
+
+func (x *IPPacket_Vpn) GetDnsSearchDomains() []string {
+	if x != nil {
+		return x.DnsSearchDomains
+	}
+	return nil
+}
+
+func (x *IPPacket_Vpn) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *IPPacket_Vpn) GetResumed() bool {
+	if x != nil {
+		return x.Resumed
+	}
+	return false
+}
+
+type IPPacket_Renew struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Jwt          string `protobuf:"bytes,1,opt,name=jwt,proto3" json:"jwt,omitempty"`                                       // renewed jwt
+	RefreshToken string `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"` // refresh token
+}
+
+func (x *IPPacket_Renew) Reset() {
+	*x = IPPacket_Renew{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IPPacket_Renew) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPPacket_Renew) ProtoMessage() {}
+
+func (x *IPPacket_Renew) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPPacket_Renew.ProtoReflect.Descriptor instead.
+func (*IPPacket_Renew) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{0, 2}
+}
+
+func (x *IPPacket_Renew) GetJwt() string {
+	if x != nil {
+		return x.Jwt
+	}
+	return ""
+}
+
+func (x *IPPacket_Renew) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type AuthRequest_GoogleOpenID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code         string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	IdToken      string `protobuf:"bytes,2,opt,name=id_token,json=idToken,proto3" json:"id_token,omitempty"`                // id token received by device flow
+	CodeVerifier string `protobuf:"bytes,3,opt,name=code_verifier,json=codeVerifier,proto3" json:"code_verifier,omitempty"` // PKCE code verifier
+	RedirectUri  string `protobuf:"bytes,4,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"`    // loopback redirect uri which is used for authorization request
+}
+
+func (x *AuthRequest_GoogleOpenID) Reset() {
+	*x = AuthRequest_GoogleOpenID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthRequest_GoogleOpenID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthRequest_GoogleOpenID) ProtoMessage() {}
+
+func (x *AuthRequest_GoogleOpenID) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthRequest_GoogleOpenID.ProtoReflect.Descriptor instead.
+func (*AuthRequest_GoogleOpenID) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{1, 0}
+}
+
+func (x *AuthRequest_GoogleOpenID) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *AuthRequest_GoogleOpenID) GetIdToken() string {
+	if x != nil {
+		return x.IdToken
+	}
+	return ""
+}
+
+func (x *AuthRequest_GoogleOpenID) GetCodeVerifier() string {
+	if x != nil {
+		return x.CodeVerifier
+	}
+	return ""
+}
+
+func (x *AuthRequest_GoogleOpenID) GetRedirectUri() string {
+	if x != nil {
+		return x.RedirectUri
+	}
+	return ""
+}
+
+type AuthRequest_AwsIam struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Method  string            `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`                                                                                           // method of presigned sts:GetCallerIdentity request
+	Url     string            `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`                                                                                                 // url of presigned request
+	Headers map[string]string `protobuf:"bytes,5,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // headers of presigned request
+	Body    string            `protobuf:"bytes,6,opt,name=body,proto3" json:"body,omitempty"`                                                                                               // body of presigned request
+}
+
+func (x *AuthRequest_AwsIam) Reset() {
+	*x = AuthRequest_AwsIam{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthRequest_AwsIam) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthRequest_AwsIam) ProtoMessage() {}
+
+func (x *AuthRequest_AwsIam) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthRequest_AwsIam.ProtoReflect.Descriptor instead.
+func (*AuthRequest_AwsIam) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{1, 1}
+}
+
+func (x *AuthRequest_AwsIam) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *AuthRequest_AwsIam) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *AuthRequest_AwsIam) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *AuthRequest_AwsIam) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+type AuthRequest_Ldap struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+}
+
+func (x *AuthRequest_Ldap) Reset() {
+	*x = AuthRequest_Ldap{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthRequest_Ldap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthRequest_Ldap) ProtoMessage() {}
+
+func (x *AuthRequest_Ldap) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthRequest_Ldap.ProtoReflect.Descriptor instead.
+func (*AuthRequest_Ldap) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{1, 2}
+}
+
+func (x *AuthRequest_Ldap) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *AuthRequest_Ldap) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type AuthRequest_OpenID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code         string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	IdToken      string `protobuf:"bytes,2,opt,name=id_token,json=idToken,proto3" json:"id_token,omitempty"`                // id token received by device flow
+	CodeVerifier string `protobuf:"bytes,3,opt,name=code_verifier,json=codeVerifier,proto3" json:"code_verifier,omitempty"` // PKCE code verifier
+	RedirectUri  string `protobuf:"bytes,4,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"`    // loopback redirect uri which is used for authorization request
+}
+
+func (x *AuthRequest_OpenID) Reset() {
+	*x = AuthRequest_OpenID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthRequest_OpenID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthRequest_OpenID) ProtoMessage() {}
+
+func (x *AuthRequest_OpenID) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthRequest_OpenID.ProtoReflect.Descriptor instead.
+func (*AuthRequest_OpenID) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{1, 3}
+}
+
+func (x *AuthRequest_OpenID) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *AuthRequest_OpenID) GetIdToken() string {
+	if x != nil {
+		return x.IdToken
+	}
+	return ""
+}
+
+func (x *AuthRequest_OpenID) GetCodeVerifier() string {
+	if x != nil {
+		return x.CodeVerifier
+	}
+	return ""
+}
+
+func (x *AuthRequest_OpenID) GetRedirectUri() string {
+	if x != nil {
+		return x.RedirectUri
+	}
+	return ""
+}
+
+type AuthRequest_Mfa struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChallengeId string `protobuf:"bytes,1,opt,name=challenge_id,json=challengeId,proto3" json:"challenge_id,omitempty"` // challenge id received with EC_MFA_REQUIRED
+	Code        string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`                                  // totp code
+}
+
+func (x *AuthRequest_Mfa) Reset() {
+	*x = AuthRequest_Mfa{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vpn_struct_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthRequest_Mfa) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthRequest_Mfa) ProtoMessage() {}
+
+func (x *AuthRequest_Mfa) ProtoReflect() protoreflect.Message {
+	mi := &file_vpn_struct_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthRequest_Mfa.ProtoReflect.Descriptor instead.
+func (*AuthRequest_Mfa) Descriptor() ([]byte, []int) {
+	return file_vpn_struct_proto_rawDescGZIP(), []int{1, 4}
+}
+
+func (x *AuthRequest_Mfa) GetChallengeId() string {
+	if x != nil {
+		return x.ChallengeId
+	}
+	return ""
+}
+
+func (x *AuthRequest_Mfa) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+var File_vpn_struct_proto protoreflect.FileDescriptor
+
+var file_vpn_struct_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x76, 0x70, 0x6e, 0x2d, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x03, 0x76, 0x70, 0x6e, 0x22, 0xca, 0x06, 0x0a, 0x08, 0x49, 0x50, 0x50, 0x61,
+	0x63, 0x6b, 0x65, 0x74, 0x12, 0x2d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f,
+	0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43,
+	0x6f, 0x64, 0x65, 0x12, 0x32, 0x0a, 0x0b, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x49,
+	0x50, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0a, 0x70, 0x61, 0x63,
+	0x6b, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2b, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x65,
+	0x74, 0x31, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x49,
+	0x50, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x2e, 0x52, 0x61, 0x77, 0x52, 0x07, 0x70, 0x61, 0x63,
+	0x6b, 0x65, 0x74, 0x31, 0x12, 0x2b, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x32, 0x18,
+	0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x49, 0x50, 0x50, 0x61,
+	0x63, 0x6b, 0x65, 0x74, 0x2e, 0x56, 0x70, 0x6e, 0x52, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74,
+	0x32, 0x12, 0x2d, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x33, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x49, 0x50, 0x50, 0x61, 0x63, 0x6b, 0x65,
+	0x74, 0x2e, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x52, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x33,
+	0x1a, 0x17, 0x0a, 0x03, 0x52, 0x61, 0x77, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x61, 0x77, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x72, 0x61, 0x77, 0x1a, 0xf8, 0x03, 0x0a, 0x03, 0x56, 0x70,
+	0x6e, 0x12, 0x26, 0x0a, 0x0f, 0x76, 0x70, 0x6e, 0x5f, 0x61, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65,
+	0x64, 0x5f, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x76, 0x70, 0x6e, 0x41,
+	0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x49, 0x70, 0x12, 0x1f, 0x0a, 0x0b, 0x76, 0x70, 0x6e,
+	0x5f, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a,
+	0x76, 0x70, 0x6e, 0x47, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x12, 0x22, 0x0a, 0x0d, 0x76, 0x70,
+	0x6e, 0x5f, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x5f, 0x69, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0b, 0x76, 0x70, 0x6e, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x49, 0x70, 0x12, 0x26,
+	0x0a, 0x0f, 0x76, 0x70, 0x6e, 0x5f, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x5f, 0x6d, 0x61, 0x73,
+	0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x76, 0x70, 0x6e, 0x53, 0x75, 0x62, 0x6e,
+	0x65, 0x74, 0x4d, 0x61, 0x73, 0x6b, 0x12, 0x28, 0x0a, 0x10, 0x76, 0x70, 0x6e, 0x5f, 0x61, 0x73,
+	0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f, 0x69, 0x70, 0x36, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x0e, 0x76, 0x70, 0x6e, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x49, 0x70, 0x36,
+	0x12, 0x21, 0x0a, 0x0c, 0x76, 0x70, 0x6e, 0x5f, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x36,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x76, 0x70, 0x6e, 0x47, 0x61, 0x74, 0x65, 0x77,
+	0x61, 0x79, 0x36, 0x12, 0x24, 0x0a, 0x0e, 0x76, 0x70, 0x6e, 0x5f, 0x73, 0x75, 0x62, 0x6e, 0x65,
+	0x74, 0x5f, 0x69, 0x70, 0x36, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x76, 0x70, 0x6e,
+	0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x49, 0x70, 0x36, 0x12, 0x28, 0x0a, 0x10, 0x76, 0x70, 0x6e,
+	0x5f, 0x73, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x36, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0e, 0x76, 0x70, 0x6e, 0x53, 0x75, 0x62, 0x6e, 0x65, 0x74, 0x4d, 0x61,
+	0x73, 0x6b, 0x36, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x18, 0x09, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x06, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x66,
+	0x75, 0x6c, 0x6c, 0x5f, 0x74, 0x75, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0a, 0x66, 0x75, 0x6c, 0x6c, 0x54, 0x75, 0x6e, 0x6e, 0x65, 0x6c, 0x12, 0x1f, 0x0a, 0x0b,
+	0x64, 0x6e, 0x73, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0a, 0x64, 0x6e, 0x73, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x12, 0x2c, 0x0a,
+	0x12, 0x64, 0x6e, 0x73, 0x5f, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x5f, 0x64, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x64, 0x6e, 0x73, 0x53, 0x65,
+	0x61, 0x72, 0x63, 0x68, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65,
+	0x73, 0x75, 0x6d, 0x65, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x73,
+	0x75, 0x6d, 0x65, 0x64, 0x1a, 0x3e, 0x0a, 0x05, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x12, 0x10, 0x0a,
+	0x03, 0x6a, 0x77, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6a, 0x77, 0x74, 0x12,
+	0x23, 0x0a, 0x0d, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xbb, 0x07, 0x0a, 0x0b, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x09, 0x61, 0x75, 0x74, 0x68, 0x5f, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x41, 0x75,
+	0x74, 0x68, 0x54, 0x79, 0x70, 0x65, 0x52, 0x08, 0x61, 0x75, 0x74, 0x68, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x43, 0x0a, 0x0e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x5f, 0x6f, 0x70, 0x65, 0x6e, 0x5f,
+	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x41,
+	0x75, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x47, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x4f, 0x70, 0x65, 0x6e, 0x49, 0x44, 0x52, 0x0c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x4f,
+	0x70, 0x65, 0x6e, 0x49, 0x64, 0x12, 0x30, 0x0a, 0x07, 0x61, 0x77, 0x73, 0x5f, 0x69, 0x61, 0x6d,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x41, 0x75, 0x74,
+	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x41, 0x77, 0x73, 0x49, 0x61, 0x6d, 0x52,
+	0x06, 0x61, 0x77, 0x73, 0x49, 0x61, 0x6d, 0x12, 0x29, 0x0a, 0x04, 0x6c, 0x64, 0x61, 0x70, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x41, 0x75, 0x74, 0x68,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4c, 0x64, 0x61, 0x70, 0x52, 0x04, 0x6c, 0x64,
+	0x61, 0x70, 0x12, 0x30, 0x0a, 0x07, 0x6f, 0x70, 0x65, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4f, 0x70, 0x65, 0x6e, 0x49, 0x44, 0x52, 0x06, 0x6f, 0x70,
+	0x65, 0x6e, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x03, 0x6d, 0x66, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x2e, 0x4d, 0x66, 0x61, 0x52, 0x03, 0x6d, 0x66, 0x61, 0x12, 0x2c, 0x0a, 0x0a,
+	0x61, 0x75, 0x74, 0x68, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0e,
+	0x32, 0x0d, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x09, 0x61, 0x75, 0x74, 0x68, 0x54, 0x79, 0x70, 0x65, 0x73, 0x1a, 0x85, 0x01, 0x0a, 0x0c, 0x47,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x4f, 0x70, 0x65, 0x6e, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04, 0x63,
+	0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12,
+	0x19, 0x0a, 0x08, 0x69, 0x64, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x69, 0x64, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6f,
+	0x64, 0x65, 0x5f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0c, 0x63, 0x6f, 0x64, 0x65, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x72, 0x12,
+	0x21, 0x0a, 0x0c, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x55,
+	0x72, 0x69, 0x1a, 0xce, 0x01, 0x0a, 0x06, 0x41, 0x77, 0x73, 0x49, 0x61, 0x6d, 0x12, 0x16, 0x0a,
+	0x06, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d,
+	0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x3e, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x41,
+	0x75, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x41, 0x77, 0x73, 0x49, 0x61,
+	0x6d, 0x2e, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07,
+	0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x1a, 0x3a, 0x0a, 0x0c, 0x48,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x4a, 0x04, 0x08,
+	0x02, 0x10, 0x03, 0x1a, 0x3e, 0x0a, 0x04, 0x4c, 0x64, 0x61, 0x70, 0x12, 0x1a, 0x0a, 0x08, 0x75,
+	0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75,
+	0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77,
+	0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77,
+	0x6f, 0x72, 0x64, 0x1a, 0x7f, 0x0a, 0x06, 0x4f, 0x70, 0x65, 0x6e, 0x49, 0x44, 0x12, 0x12, 0x0a,
+	0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64,
+	0x65, 0x12, 0x19, 0x0a, 0x08, 0x69, 0x64, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x69, 0x64, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x23, 0x0a, 0x0d,
+	0x63, 0x6f, 0x64, 0x65, 0x5f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6f, 0x64, 0x65, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65,
+	0x72, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x5f, 0x75, 0x72,
+	0x69, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x55, 0x72, 0x69, 0x1a, 0x3c, 0x0a, 0x03, 0x4d, 0x66, 0x61, 0x12, 0x21, 0x0a, 0x0c, 0x63,
+	0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x49, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f,
+	0x64, 0x65, 0x22, 0x9e, 0x01, 0x0a, 0x0c, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x45, 0x72,
+	0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6a, 0x77, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6a, 0x77, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x5f,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x66,
+	0x72, 0x65, 0x73, 0x68, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x28, 0x0a, 0x10, 0x6d, 0x66, 0x61,
+	0x5f, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0e, 0x6d, 0x66, 0x61, 0x43, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67,
+	0x65, 0x49, 0x64, 0x22, 0xa5, 0x03, 0x0a, 0x07, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75,
+	0x73, 0x65, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x5f, 0x69, 0x70,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x49, 0x70,
+	0x12, 0x15, 0x0a, 0x06, 0x76, 0x70, 0x6e, 0x5f, 0x69, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x70, 0x6e, 0x49, 0x70, 0x12, 0x17, 0x0a, 0x07, 0x76, 0x70, 0x6e, 0x5f, 0x69,
+	0x70, 0x36, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x76, 0x70, 0x6e, 0x49, 0x70, 0x36,
+	0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x69, 0x6e, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x62, 0x79, 0x74, 0x65, 0x73, 0x49, 0x6e, 0x12, 0x1b,
+	0x0a, 0x09, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x6f, 0x75, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x08, 0x62, 0x79, 0x74, 0x65, 0x73, 0x4f, 0x75, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x6a,
+	0x77, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6a, 0x77, 0x74,
+	0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x69, 0x6e, 0x63, 0x69, 0x70, 0x61, 0x6c,
+	0x12, 0x16, 0x0a, 0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x06, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x6f, 0x6c, 0x65,
+	0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x12, 0x3c,
+	0x0a, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18, 0x0c, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x2e, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x0a, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x1a, 0x3d, 0x0a, 0x0f,
+	0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x15, 0x0a, 0x13, 0x4c,
+	0x69, 0x73, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x22, 0x6f, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x0a, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e,
+	0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x09,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x28, 0x0a, 0x08, 0x73, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x76, 0x70,
+	0x6e, 0x2e, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x22, 0x3f, 0x0a, 0x12, 0x4b, 0x69, 0x63, 0x6b, 0x53, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x15, 0x0a,
+	0x06, 0x76, 0x70, 0x6e, 0x5f, 0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x70, 0x6e, 0x49, 0x70, 0x22, 0x5c, 0x0a, 0x13, 0x4b, 0x69, 0x63, 0x6b, 0x53, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x0a, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x0e, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52,
+	0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6b, 0x69,
+	0x63, 0x6b, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6b, 0x69, 0x63, 0x6b,
+	0x65, 0x64, 0x22, 0x27, 0x0a, 0x11, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x5b, 0x0a, 0x12, 0x52,
+	0x65, 0x76, 0x6f, 0x6b, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x2d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x6b, 0x69, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x6b, 0x69, 0x63, 0x6b, 0x65, 0x64, 0x22, 0x2b, 0x0a, 0x12, 0x52, 0x65, 0x76, 0x6f,
+	0x6b, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15,
+	0x0a, 0x06, 0x6a, 0x77, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x6a, 0x77, 0x74, 0x49, 0x64, 0x22, 0x5c, 0x0a, 0x13, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x0a,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x0e, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65,
+	0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6b,
+	0x69, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6b, 0x69, 0x63,
+	0x6b, 0x65, 0x64, 0x22, 0x7e, 0x0a, 0x05, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x70,
+	0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x64, 0x5f,
+	0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65,
+	0x64, 0x41, 0x74, 0x22, 0x13, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x65, 0x61, 0x73, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x67, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74,
+	0x4c, 0x65, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d,
+	0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f,
+	0x64, 0x65, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x22, 0x0a,
+	0x06, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e,
+	0x76, 0x70, 0x6e, 0x2e, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x06, 0x6c, 0x65, 0x61, 0x73, 0x65,
+	0x73, 0x22, 0x11, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0xf8, 0x01, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x0a, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e,
+	0x76, 0x70, 0x6e, 0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x09, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x6c, 0x65,
+	0x61, 0x73, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x61, 0x63, 0x74, 0x69,
+	0x76, 0x65, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x79, 0x74, 0x65,
+	0x73, 0x5f, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x62, 0x79, 0x74, 0x65,
+	0x73, 0x49, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x62, 0x79, 0x74, 0x65, 0x73, 0x5f, 0x6f, 0x75, 0x74,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x62, 0x79, 0x74, 0x65, 0x73, 0x4f, 0x75, 0x74,
+	0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x63, 0x6c, 0x5f, 0x64, 0x65, 0x6e, 0x69, 0x65, 0x64, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x61, 0x63, 0x6c, 0x44, 0x65, 0x6e, 0x69, 0x65, 0x64, 0x2a,
+	0x81, 0x01, 0x0a, 0x08, 0x41, 0x75, 0x74, 0x68, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07,
+	0x41, 0x54, 0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x54, 0x5f,
+	0x54, 0x45, 0x53, 0x54, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x41, 0x54, 0x5f, 0x47, 0x4f, 0x4f,
+	0x47, 0x4c, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x4e, 0x5f, 0x49, 0x44, 0x10, 0x02, 0x12, 0x0e, 0x0a,
+	0x0a, 0x41, 0x54, 0x5f, 0x41, 0x57, 0x53, 0x5f, 0x49, 0x41, 0x4d, 0x10, 0x03, 0x12, 0x0b, 0x0a,
+	0x07, 0x41, 0x54, 0x5f, 0x4c, 0x44, 0x41, 0x50, 0x10, 0x04, 0x12, 0x0e, 0x0a, 0x0a, 0x41, 0x54,
+	0x5f, 0x4f, 0x50, 0x45, 0x4e, 0x5f, 0x49, 0x44, 0x10, 0x05, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x54,
+	0x5f, 0x4d, 0x54, 0x4c, 0x53, 0x10, 0x06, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x54, 0x5f, 0x4d, 0x46,
+	0x41, 0x10, 0x07, 0x2a, 0x86, 0x01, 0x0a, 0x09, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64,
+	0x65, 0x12, 0x0e, 0x0a, 0x0a, 0x45, 0x43, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10,
+	0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x45, 0x43, 0x5f, 0x53, 0x55, 0x43, 0x43, 0x45, 0x53, 0x53, 0x10,
+	0x01, 0x12, 0x1c, 0x0a, 0x18, 0x45, 0x43, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f,
+	0x41, 0x55, 0x54, 0x48, 0x4f, 0x52, 0x49, 0x5a, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x02, 0x12,
+	0x12, 0x0a, 0x0e, 0x45, 0x43, 0x5f, 0x45, 0x58, 0x50, 0x49, 0x52, 0x45, 0x44, 0x5f, 0x4a, 0x57,
+	0x54, 0x10, 0x03, 0x12, 0x12, 0x0a, 0x0e, 0x45, 0x43, 0x5f, 0x52, 0x45, 0x56, 0x4f, 0x4b, 0x45,
+	0x44, 0x5f, 0x4a, 0x57, 0x54, 0x10, 0x04, 0x12, 0x13, 0x0a, 0x0f, 0x45, 0x43, 0x5f, 0x4d, 0x46,
+	0x41, 0x5f, 0x52, 0x45, 0x51, 0x55, 0x49, 0x52, 0x45, 0x44, 0x10, 0x05, 0x2a, 0x57, 0x0a, 0x0c,
+	0x49, 0x50, 0x50, 0x61, 0x63, 0x6b, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x0c,
+	0x49, 0x50, 0x50, 0x54, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0c,
+	0x0a, 0x08, 0x49, 0x50, 0x50, 0x54, 0x5f, 0x52, 0x41, 0x57, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f,
+	0x49, 0x50, 0x50, 0x54, 0x5f, 0x56, 0x50, 0x4e, 0x5f, 0x41, 0x53, 0x53, 0x49, 0x47, 0x4e, 0x10,
+	0x02, 0x12, 0x12, 0x0a, 0x0e, 0x49, 0x50, 0x50, 0x54, 0x5f, 0x52, 0x45, 0x4e, 0x45, 0x57, 0x5f,
+	0x4a, 0x57, 0x54, 0x10, 0x03, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_vpn_struct_proto_rawDescOnce sync.Once
+	file_vpn_struct_proto_rawDescData = file_vpn_struct_proto_rawDesc
+)
+
+func file_vpn_struct_proto_rawDescGZIP() []byte {
+	file_vpn_struct_proto_rawDescOnce.Do(func() {
+		file_vpn_struct_proto_rawDescData = protoimpl.X.CompressGZIP(file_vpn_struct_proto_rawDescData)
+	})
+	return file_vpn_struct_proto_rawDescData
+}
+
+var file_vpn_struct_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_vpn_struct_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
+var file_vpn_struct_proto_goTypes = []interface{}{
+	(AuthType)(0),                    // 0: vpn.AuthType
+	(ErrorCode)(0),                   // 1: vpn.ErrorCode
+	(IPPacketType)(0),                // 2: vpn.IPPacketType
+	(*IPPacket)(nil),                 // 3: vpn.IPPacket
+	(*AuthRequest)(nil),              // 4: vpn.AuthRequest
+	(*AuthResponse)(nil),             // 5: vpn.AuthResponse
+	(*Session)(nil),                  // 6: vpn.Session
+	(*ListSessionsRequest)(nil),      // 7: vpn.ListSessionsRequest
+	(*ListSessionsResponse)(nil),     // 8: vpn.ListSessionsResponse
+	(*KickSessionRequest)(nil),       // 9: vpn.KickSessionRequest
+	(*KickSessionResponse)(nil),      // 10: vpn.KickSessionResponse
+	(*RevokeUserRequest)(nil),        // 11: vpn.RevokeUserRequest
+	(*RevokeUserResponse)(nil),       // 12: vpn.RevokeUserResponse
+	(*RevokeTokenRequest)(nil),       // 13: vpn.RevokeTokenRequest
+	(*RevokeTokenResponse)(nil),      // 14: vpn.RevokeTokenResponse
+	(*Lease)(nil),                    // 15: vpn.Lease
+	(*ListLeasesRequest)(nil),        // 16: vpn.ListLeasesRequest
+	(*ListLeasesResponse)(nil),       // 17: vpn.ListLeasesResponse
+	(*GetStatsRequest)(nil),          // 18: vpn.GetStatsRequest
+	(*GetStatsResponse)(nil),         // 19: vpn.GetStatsResponse
+	(*IPPacket_Raw)(nil),             // 20: vpn.IPPacket.Raw
+	(*IPPacket_Vpn)(nil),             // 21: vpn.IPPacket.Vpn
+	(*IPPacket_Renew)(nil),           // 22: vpn.IPPacket.Renew
+	(*AuthRequest_GoogleOpenID)(nil), // 23: vpn.AuthRequest.GoogleOpenID
+	(*AuthRequest_AwsIam)(nil),       // 24: vpn.AuthRequest.AwsIam
+	(*AuthRequest_Ldap)(nil),         // 25: vpn.AuthRequest.Ldap
+	(*AuthRequest_OpenID)(nil),       // 26: vpn.AuthRequest.OpenID
+	(*AuthRequest_Mfa)(nil),          // 27: vpn.AuthRequest.Mfa
+	nil,                              // 28: vpn.AuthRequest.AwsIam.HeadersEntry
+	nil,                              // 29: vpn.Session.AttributesEntry
+}
+var file_vpn_struct_proto_depIdxs = []int32{
+	1,  // 0: vpn.IPPacket.error_code:type_name -> vpn.ErrorCode
+	2,  // 1: vpn.IPPacket.packet_type:type_name -> vpn.IPPacketType
+	20, // 2: vpn.IPPacket.packet1:type_name -> vpn.IPPacket.Raw
+	21, // 3: vpn.IPPacket.packet2:type_name -> vpn.IPPacket.Vpn
+	22, // 4: vpn.IPPacket.packet3:type_name -> vpn.IPPacket.Renew
+	0,  // 5: vpn.AuthRequest.auth_type:type_name -> vpn.AuthType
+	23, // 6: vpn.AuthRequest.google_open_id:type_name -> vpn.AuthRequest.GoogleOpenID
+	24, // 7: vpn.AuthRequest.aws_iam:type_name -> vpn.AuthRequest.AwsIam
+	25, // 8: vpn.AuthRequest.ldap:type_name -> vpn.AuthRequest.Ldap
+	26, // 9: vpn.AuthRequest.open_id:type_name -> vpn.AuthRequest.OpenID
+	27, // 10: vpn.AuthRequest.mfa:type_name -> vpn.AuthRequest.Mfa
+	0,  // 11: vpn.AuthRequest.auth_types:type_name -> vpn.AuthType
+	1,  // 12: vpn.AuthResponse.error_code:type_name -> vpn.ErrorCode
+	29, // 13: vpn.Session.attributes:type_name -> vpn.Session.AttributesEntry
+	1,  // 14: vpn.ListSessionsResponse.error_code:type_name -> vpn.ErrorCode
+	6,  // 15: vpn.ListSessionsResponse.sessions:type_name -> vpn.Session
+	1,  // 16: vpn.KickSessionResponse.error_code:type_name -> vpn.ErrorCode
+	1,  // 17: vpn.RevokeUserResponse.error_code:type_name -> vpn.ErrorCode
+	1,  // 18: vpn.RevokeTokenResponse.error_code:type_name -> vpn.ErrorCode
+	1,  // 19: vpn.ListLeasesResponse.error_code:type_name -> vpn.ErrorCode
+	15, // 20: vpn.ListLeasesResponse.leases:type_name -> vpn.Lease
+	1,  // 21: vpn.GetStatsResponse.error_code:type_name -> vpn.ErrorCode
+	28, // 22: vpn.AuthRequest.AwsIam.headers:type_name -> vpn.AuthRequest.AwsIam.HeadersEntry
+	23, // [23:23] is the sub-list for method output_type
+	23, // [23:23] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
+}
+
+func init() { file_vpn_struct_proto_init() }
+func file_vpn_struct_proto_init() {
+	if File_vpn_struct_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_vpn_struct_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IPPacket); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
 				return &v.unknownFields
 			default:
 				return nil
@@ -742,7 +2234,7 @@ func file_vpn_struct_proto_init() {
 			}
 		}
 		file_vpn_struct_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*IPPacket_Raw); i {
+			switch v := v.(*Session); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -754,7 +2246,7 @@ func file_vpn_struct_proto_init() {
 			}
 		}
 		file_vpn_struct_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*IPPacket_Vpn); i {
+			switch v := v.(*ListSessionsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -766,7 +2258,7 @@ func file_vpn_struct_proto_init() {
 			}
 		}
 		file_vpn_struct_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AuthRequest_GoogleOpenID); i {
+			switch v := v.(*ListSessionsResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -778,6 +2270,186 @@ func file_vpn_struct_proto_init() {
 			}
 		}
 		file_vpn_struct_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KickSessionRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vpn_struct_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KickSessionResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vpn_struct_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevokeUserRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vpn_struct_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevokeUserResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vpn_struct_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevokeTokenRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vpn_struct_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RevokeTokenResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vpn_struct_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Lease); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vpn_struct_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListLeasesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vpn_struct_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListLeasesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vpn_struct_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetStatsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vpn_struct_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetStatsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vpn_struct_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IPPacket_Raw); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vpn_struct_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IPPacket_Vpn); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vpn_struct_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IPPacket_Renew); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vpn_struct_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthRequest_GoogleOpenID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vpn_struct_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AuthRequest_AwsIam); i {
 			case 0:
 				return &v.state
@@ -789,6 +2461,42 @@ func file_vpn_struct_proto_init() {
 				return nil
 			}
 		}
+		file_vpn_struct_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthRequest_Ldap); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vpn_struct_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthRequest_OpenID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vpn_struct_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthRequest_Mfa); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -796,7 +2504,7 @@ func file_vpn_struct_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_vpn_struct_proto_rawDesc,
 			NumEnums:      3,
-			NumMessages:   7,
+			NumMessages:   27,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -39,24 +39,74 @@ var file_vpn_proto_rawDesc = []byte{
 	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2e, 0x0a, 0x08, 0x45, 0x78, 0x63, 0x68,
 	0x61, 0x6e, 0x67, 0x65, 0x12, 0x0d, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x49, 0x50, 0x50, 0x61, 0x63,
 	0x6b, 0x65, 0x74, 0x1a, 0x0d, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x49, 0x50, 0x50, 0x61, 0x63, 0x6b,
-	0x65, 0x74, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x74, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x32, 0x93, 0x03, 0x0a, 0x05, 0x41, 0x64, 0x6d,
+	0x69, 0x6e, 0x12, 0x45, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x18, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x76,
+	0x70, 0x6e, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x42, 0x0a, 0x0b, 0x4b, 0x69, 0x63,
+	0x6b, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x17, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x4b,
+	0x69, 0x63, 0x6b, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x18, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x4b, 0x69, 0x63, 0x6b, 0x53, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3f, 0x0a,
+	0x0a, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x55, 0x73, 0x65, 0x72, 0x12, 0x16, 0x2e, 0x76, 0x70,
+	0x6e, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65,
+	0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x42,
+	0x0a, 0x0b, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x17, 0x2e,
+	0x76, 0x70, 0x6e, 0x2e, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x52, 0x65, 0x76,
+	0x6f, 0x6b, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x12, 0x3f, 0x0a, 0x0a, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x73,
+	0x12, 0x16, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4c, 0x65, 0x61, 0x73, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x4c, 0x65, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12,
+	0x14, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x76, 0x70, 0x6e, 0x2e, 0x47, 0x65, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var file_vpn_proto_goTypes = []interface{}{
-	(*AuthRequest)(nil),  // 0: vpn.AuthRequest
-	(*IPPacket)(nil),     // 1: vpn.IPPacket
-	(*AuthResponse)(nil), // 2: vpn.AuthResponse
+	(*AuthRequest)(nil),          // 0: vpn.AuthRequest
+	(*IPPacket)(nil),             // 1: vpn.IPPacket
+	(*ListSessionsRequest)(nil),  // 2: vpn.ListSessionsRequest
+	(*KickSessionRequest)(nil),   // 3: vpn.KickSessionRequest
+	(*RevokeUserRequest)(nil),    // 4: vpn.RevokeUserRequest
+	(*RevokeTokenRequest)(nil),   // 5: vpn.RevokeTokenRequest
+	(*ListLeasesRequest)(nil),    // 6: vpn.ListLeasesRequest
+	(*GetStatsRequest)(nil),      // 7: vpn.GetStatsRequest
+	(*AuthResponse)(nil),         // 8: vpn.AuthResponse
+	(*ListSessionsResponse)(nil), // 9: vpn.ListSessionsResponse
+	(*KickSessionResponse)(nil),  // 10: vpn.KickSessionResponse
+	(*RevokeUserResponse)(nil),   // 11: vpn.RevokeUserResponse
+	(*RevokeTokenResponse)(nil),  // 12: vpn.RevokeTokenResponse
+	(*ListLeasesResponse)(nil),   // 13: vpn.ListLeasesResponse
+	(*GetStatsResponse)(nil),     // 14: vpn.GetStatsResponse
 }
 var file_vpn_proto_depIdxs = []int32{
-	0, // 0: vpn.VPN.Auth:input_type -> vpn.AuthRequest
-	1, // 1: vpn.VPN.Exchange:input_type -> vpn.IPPacket
-	2, // 2: vpn.VPN.Auth:output_type -> vpn.AuthResponse
-	1, // 3: vpn.VPN.Exchange:output_type -> vpn.IPPacket
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: vpn.VPN.Auth:input_type -> vpn.AuthRequest
+	1,  // 1: vpn.VPN.Exchange:input_type -> vpn.IPPacket
+	2,  // 2: vpn.Admin.ListSessions:input_type -> vpn.ListSessionsRequest
+	3,  // 3: vpn.Admin.KickSession:input_type -> vpn.KickSessionRequest
+	4,  // 4: vpn.Admin.RevokeUser:input_type -> vpn.RevokeUserRequest
+	5,  // 5: vpn.Admin.RevokeToken:input_type -> vpn.RevokeTokenRequest
+	6,  // 6: vpn.Admin.ListLeases:input_type -> vpn.ListLeasesRequest
+	7,  // 7: vpn.Admin.GetStats:input_type -> vpn.GetStatsRequest
+	8,  // 8: vpn.VPN.Auth:output_type -> vpn.AuthResponse
+	1,  // 9: vpn.VPN.Exchange:output_type -> vpn.IPPacket
+	9,  // 10: vpn.Admin.ListSessions:output_type -> vpn.ListSessionsResponse
+	10, // 11: vpn.Admin.KickSession:output_type -> vpn.KickSessionResponse
+	11, // 12: vpn.Admin.RevokeUser:output_type -> vpn.RevokeUserResponse
+	12, // 13: vpn.Admin.RevokeToken:output_type -> vpn.RevokeTokenResponse
+	13, // 14: vpn.Admin.ListLeases:output_type -> vpn.ListLeasesResponse
+	14, // 15: vpn.Admin.GetStats:output_type -> vpn.GetStatsResponse
+	8,  // [8:16] is the sub-list for method output_type
+	0,  // [0:8] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_vpn_proto_init() }
@@ -73,7 +123,7 @@ func file_vpn_proto_init() {
 			NumEnums:      0,
 			NumMessages:   0,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_vpn_proto_goTypes,
 		DependencyIndexes: file_vpn_proto_depIdxs,
@@ -236,3 +286,267 @@ var _VPN_serviceDesc = grpc.ServiceDesc{
 	},
 	Metadata: "vpn.proto",
 }
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AdminClient interface {
+	// list connected sessions
+	ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	// kick sessions by user or vpn ip
+	KickSession(ctx context.Context, in *KickSessionRequest, opts ...grpc.CallOption) (*KickSessionResponse, error)
+	// revoke user, issued jwt of user is refused
+	RevokeUser(ctx context.Context, in *RevokeUserRequest, opts ...grpc.CallOption) (*RevokeUserResponse, error)
+	// revoke token, jwt which has jwt id(jti) is refused
+	RevokeToken(ctx context.Context, in *RevokeTokenRequest, opts ...grpc.CallOption) (*RevokeTokenResponse, error)
+	// list vpn ip leases
+	ListLeases(ctx context.Context, in *ListLeasesRequest, opts ...grpc.CallOption) (*ListLeasesResponse, error)
+	// server statistics
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) ListSessions(ctx context.Context, in *ListSessionsRequest, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	out := new(ListSessionsResponse)
+	err := c.cc.Invoke(ctx, "/vpn.Admin/ListSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) KickSession(ctx context.Context, in *KickSessionRequest, opts ...grpc.CallOption) (*KickSessionResponse, error) {
+	out := new(KickSessionResponse)
+	err := c.cc.Invoke(ctx, "/vpn.Admin/KickSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RevokeUser(ctx context.Context, in *RevokeUserRequest, opts ...grpc.CallOption) (*RevokeUserResponse, error) {
+	out := new(RevokeUserResponse)
+	err := c.cc.Invoke(ctx, "/vpn.Admin/RevokeUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RevokeToken(ctx context.Context, in *RevokeTokenRequest, opts ...grpc.CallOption) (*RevokeTokenResponse, error) {
+	out := new(RevokeTokenResponse)
+	err := c.cc.Invoke(ctx, "/vpn.Admin/RevokeToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListLeases(ctx context.Context, in *ListLeasesRequest, opts ...grpc.CallOption) (*ListLeasesResponse, error) {
+	out := new(ListLeasesResponse)
+	err := c.cc.Invoke(ctx, "/vpn.Admin/ListLeases", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error) {
+	out := new(GetStatsResponse)
+	err := c.cc.Invoke(ctx, "/vpn.Admin/GetStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+type AdminServer interface {
+	// list connected sessions
+	ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error)
+	// kick sessions by user or vpn ip
+	KickSession(context.Context, *KickSessionRequest) (*KickSessionResponse, error)
+	// revoke user, issued jwt of user is refused
+	RevokeUser(context.Context, *RevokeUserRequest) (*RevokeUserResponse, error)
+	// revoke token, jwt which has jwt id(jti) is refused
+	RevokeToken(context.Context, *RevokeTokenRequest) (*RevokeTokenResponse, error)
+	// list vpn ip leases
+	ListLeases(context.Context, *ListLeasesRequest) (*ListLeasesResponse, error)
+	// server statistics
+	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
+}
+
+// UnimplementedAdminServer can be embedded to have forward compatible implementations.
+type UnimplementedAdminServer struct {
+}
+
+func (*UnimplementedAdminServer) ListSessions(context.Context, *ListSessionsRequest) (*ListSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (*UnimplementedAdminServer) KickSession(context.Context, *KickSessionRequest) (*KickSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KickSession not implemented")
+}
+func (*UnimplementedAdminServer) RevokeUser(context.Context, *RevokeUserRequest) (*RevokeUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeUser not implemented")
+}
+func (*UnimplementedAdminServer) RevokeToken(context.Context, *RevokeTokenRequest) (*RevokeTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeToken not implemented")
+}
+func (*UnimplementedAdminServer) ListLeases(context.Context, *ListLeasesRequest) (*ListLeasesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLeases not implemented")
+}
+func (*UnimplementedAdminServer) GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+
+func RegisterAdminServer(s *grpc.Server, srv AdminServer) {
+	s.RegisterService(&_Admin_serviceDesc, srv)
+}
+
+func _Admin_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vpn.Admin/ListSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListSessions(ctx, req.(*ListSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_KickSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KickSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).KickSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vpn.Admin/KickSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).KickSession(ctx, req.(*KickSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RevokeUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RevokeUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vpn.Admin/RevokeUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RevokeUser(ctx, req.(*RevokeUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RevokeToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RevokeToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vpn.Admin/RevokeToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RevokeToken(ctx, req.(*RevokeTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListLeases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLeasesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListLeases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vpn.Admin/ListLeases",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListLeases(ctx, req.(*ListLeasesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/vpn.Admin/GetStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Admin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "vpn.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSessions",
+			Handler:    _Admin_ListSessions_Handler,
+		},
+		{
+			MethodName: "KickSession",
+			Handler:    _Admin_KickSession_Handler,
+		},
+		{
+			MethodName: "RevokeUser",
+			Handler:    _Admin_RevokeUser_Handler,
+		},
+		{
+			MethodName: "RevokeToken",
+			Handler:    _Admin_RevokeToken_Handler,
+		},
+		{
+			MethodName: "ListLeases",
+			Handler:    _Admin_ListLeases_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _Admin_GetStats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vpn.proto",
+}
@@ -28,6 +28,17 @@ func CommandExec(command string, args []string) error {
 	return nil
 }
 
+// CommandOutput executes command and returns standard output.
+func CommandOutput(command string, args []string) ([]byte, error) {
+	output, err := exec.Command(command, args...).Output()
+	if err != nil {
+		commandLogger.Error(color.RedString("[FAIL-CMD] %s %s [Reason]: %s",
+			command, strings.Join(args, " "), err.Error()))
+		return nil, err
+	}
+	return output, nil
+}
+
 func init() {
 	commandLogger, _ = NewLogger("")
 }
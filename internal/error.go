@@ -7,11 +7,14 @@ var (
 	ErrorInvalidParams        = errors.New("[ERR] Invalid Params")
 	ErrorUnauthorized         = errors.New("[ERR] Unauthorized")
 	ErrorInvalidJWT           = errors.New("[ERR] Invalid JWT")
+	ErrorRevokedJWT           = errors.New("[ERR] Revoked JWT")
 	ErrorInvalidContext       = errors.New("[ERR] Invalid Context")
 	ErrorExceedClientPool     = errors.New("[ERR] Exceed Client Pool")
+	ErrorInvalidReservation   = errors.New("[ERR] Invalid Reservation")
 	ErrorCloseConnection      = errors.New("[ERR] Close Connection")
 	ErrorReceiveUnknownPacket = errors.New("[ERR] Receive Unknown Packet")
 	ErrorMismatchVpnIP        = errors.New("[ERR] Mismatch Vpn IP")
 	ErrorStoppingServer       = errors.New("[ERR] Stopping Server")
 	ErrorAlreadyRunning       = errors.New("[ERR] Already Running")
+	ErrorNotFoundAuthMethod   = errors.New("[ERR] Not Found Auth Method")
 )
@@ -1,21 +1,225 @@
 package internal
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+
 	"github.com/dgrijalva/jwt-go"
 	"github.com/pkg/errors"
 )
 
 const (
 	HS256 = "HS256"
+	RS256 = "RS256"
+	ES256 = "ES256"
+	ES384 = "ES384"
+	ES512 = "ES512"
+	EdDSA = "EdDSA"
 )
 
+// SigningMethodEdDSA is a signing method for Ed25519 keys which jwt-go doesn't support.
+var SigningMethodEdDSA = &signingMethodEdDSA{}
+
+type signingMethodEdDSA struct{}
+
+// Alg returns the name of signing method.
+func (m *signingMethodEdDSA) Alg() string {
+	return EdDSA
+}
+
+// Verify checks signature using ed25519.PublicKey.
+func (m *signingMethodEdDSA) Verify(signingString, signature string, key interface{}) error {
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return jwt.ErrInvalidKeyType
+	}
+
+	sig, err := jwt.DecodeSegment(signature)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(publicKey, []byte(signingString), sig) {
+		return jwt.ErrSignatureInvalid
+	}
+	return nil
+}
+
+// Sign signs signingString using ed25519.PrivateKey.
+func (m *signingMethodEdDSA) Sign(signingString string, key interface{}) (string, error) {
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", jwt.ErrInvalidKeyType
+	}
+	return jwt.EncodeSegment(ed25519.Sign(privateKey, []byte(signingString))), nil
+}
+
+// VpnClaims is claims of jwt issued by vpn server.
+type VpnClaims struct {
+	jwt.StandardClaims
+	Principal  string            `json:"principal,omitempty"`  // authenticated principal(e.g. aws iam arn)
+	Groups     []string          `json:"groups,omitempty"`     // groups which auth method maps principal to
+	Roles      []string          `json:"roles,omitempty"`      // roles given by auth method(e.g. aws iam role)
+	Attributes map[string]string `json:"attributes,omitempty"` // attributes given by auth method(e.g. google hosted domain)
+}
+
+// JWTKey is a key for signing and verifying jwt.
+type JWTKey struct {
+	Id         string            // key id(kid)
+	Method     jwt.SigningMethod // signing method
+	PrivateKey crypto.Signer     // private key for signing(nil if the key is only for verifying)
+	PublicKey  crypto.PublicKey  // public key for verifying
+}
+
+// JWTKeySet is a set of keys, the active key signs jwt and all keys verify jwt.
+type JWTKeySet struct {
+	active *JWTKey            // signing key
+	keys   map[string]*JWTKey // verifying keys(map[kid]key)
+}
+
+// EncodeJWT is to encode jwt using the active key, and kid header is set.
+func (s *JWTKeySet) EncodeJWT(claims *VpnClaims) (string, error) {
+	if claims == nil {
+		return "", errors.Wrapf(ErrorInvalidParams, "Method: EncodeJWT")
+	}
+
+	token := jwt.NewWithClaims(s.active.Method, claims)
+	token.Header["kid"] = s.active.Id
+	return token.SignedString(s.active.PrivateKey)
+}
+
+// DecodeJWT is to decode jwt using a key matched with kid header.
+func (s *JWTKeySet) DecodeJWT(data string) (*jwt.Token, error) {
+	if data == "" {
+		return nil, errors.Wrapf(ErrorInvalidParams, "Method: DecodeJWT")
+	}
+
+	token, err := jwt.ParseWithClaims(data, &VpnClaims{}, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := s.keys[kid]
+		if !ok || token.Method.Alg() != key.Method.Alg() {
+			return nil, errors.Wrapf(ErrorInvalidJWT, "Method: DecodeJWT")
+		}
+		return key.PublicKey, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(ErrorInvalidJWT, "Method: DecodeJWT")
+	}
+	if token == nil || !token.Valid {
+		return nil, errors.Wrapf(ErrorInvalidJWT, "Method: DecodeJWT")
+	}
+	return token, nil
+}
+
+// NewJWTKeySet returns a key set, active is kid of signing key which must have a private key.
+// keys which aren't active still verify jwt, so rotated keys don't invalidate issued jwt.
+func NewJWTKeySet(active string, keys []*JWTKey) (*JWTKeySet, error) {
+	set := &JWTKeySet{keys: map[string]*JWTKey{}}
+	for _, key := range keys {
+		if key == nil || key.Id == "" || key.Method == nil || key.PublicKey == nil {
+			return nil, errors.Wrapf(ErrorInvalidParams, "Method: NewJWTKeySet")
+		}
+		if _, ok := set.keys[key.Id]; ok {
+			return nil, errors.Wrapf(ErrorInvalidParams, "Method: NewJWTKeySet")
+		}
+		set.keys[key.Id] = key
+	}
+
+	key, ok := set.keys[active]
+	if !ok || key.PrivateKey == nil {
+		return nil, errors.Wrapf(ErrorInvalidParams, "Method: NewJWTKeySet")
+	}
+	set.active = key
+	return set, nil
+}
+
+// ParseJWTKey parses a PEM encoded private key or public key.
+// signing method is decided by key type, RSA(RS256), ECDSA(ES256, ES384, ES512), Ed25519(EdDSA).
+func ParseJWTKey(id string, data []byte) (*JWTKey, error) {
+	if id == "" || len(data) == 0 {
+		return nil, errors.Wrapf(ErrorInvalidParams, "Method: ParseJWTKey")
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Wrapf(ErrorInvalidParams, "Method: ParseJWTKey")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, errors.Wrapf(ErrorInvalidParams, "Method: ParseJWTKey")
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: ParseJWTKey")
+	}
+
+	jwtKey := &JWTKey{Id: id}
+	if signer, ok := key.(crypto.Signer); ok {
+		jwtKey.PrivateKey = signer
+		key = signer.Public()
+	}
+	jwtKey.PublicKey = key
+
+	switch publicKey := key.(type) {
+	case *rsa.PublicKey:
+		jwtKey.Method = jwt.SigningMethodRS256
+	case *ecdsa.PublicKey:
+		switch publicKey.Curve {
+		case elliptic.P256():
+			jwtKey.Method = jwt.SigningMethodES256
+		case elliptic.P384():
+			jwtKey.Method = jwt.SigningMethodES384
+		case elliptic.P521():
+			jwtKey.Method = jwt.SigningMethodES512
+		default:
+			return nil, errors.Wrapf(ErrorInvalidParams, "Method: ParseJWTKey")
+		}
+	case ed25519.PublicKey:
+		jwtKey.Method = SigningMethodEdDSA
+	default:
+		return nil, errors.Wrapf(ErrorInvalidParams, "Method: ParseJWTKey")
+	}
+	return jwtKey, nil
+}
+
+// LoadJWTKey reads a PEM file and parses a private key or public key.
+func LoadJWTKey(id string, path string) (*JWTKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: LoadJWTKey")
+	}
+
+	key, err := ParseJWTKey(id, data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: LoadJWTKey")
+	}
+	return key, nil
+}
+
 // DecodeJWT is to decode jwt using HS256.
 func DecodeJWT(data string, salt []byte) (*jwt.Token, error) {
 	if data == "" || len(salt) == 0 {
 		return nil, errors.Wrapf(ErrorInvalidParams, "Method: DecodeJWT")
 	}
 
-	token, err := jwt.ParseWithClaims(data, &jwt.StandardClaims{}, func(token *jwt.Token) (interface{}, error) {
+	token, err := jwt.ParseWithClaims(data, &VpnClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if token.Method.Alg() != HS256 {
 			return nil, errors.Wrapf(ErrorInvalidJWT, "Method: DecodeJWT")
 		}
@@ -32,7 +236,7 @@ func DecodeJWT(data string, salt []byte) (*jwt.Token, error) {
 }
 
 // EncodeJWT is to encode jwt using HS256.
-func EncodeJWT(claims *jwt.StandardClaims, salt []byte) (string, error) {
+func EncodeJWT(claims *VpnClaims, salt []byte) (string, error) {
 	if claims == nil || len(salt) == 0 {
 		return "", errors.Wrapf(ErrorInvalidParams, "Method: EncodeJWT")
 	}
@@ -40,3 +244,9 @@ func EncodeJWT(claims *jwt.StandardClaims, salt []byte) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(salt)
 }
+
+func init() {
+	jwt.RegisterSigningMethod(EdDSA, func() jwt.SigningMethod {
+		return SigningMethodEdDSA
+	})
+}
@@ -1,10 +1,19 @@
 package internal
 
 import (
-	"github.com/dgrijalva/jwt-go"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"testing"
 	"time"
 
+	"github.com/dgrijalva/jwt-go"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -12,29 +21,29 @@ func TestEncodeAndDecodeJWT(t *testing.T) {
 	assert := assert.New(t)
 
 	tests := map[string]struct {
-		claims *jwt.StandardClaims
+		claims *VpnClaims
 		salt   []byte
 		isErr  bool
 		valid  bool
 	}{
 		"fail": {isErr: true},
 		"expired": {
-			claims: &jwt.StandardClaims{
+			claims: &VpnClaims{StandardClaims: jwt.StandardClaims{
 				Audience:  "hello",
 				ExpiresAt: time.Now().Add(-1 * time.Hour).Unix(),
 				Issuer:    "vpn-server",
 				Subject:   "vpn jwt token",
-			},
+			}},
 			salt:  []byte("allan"),
 			valid: false,
 		},
 		"success": {
-			claims: &jwt.StandardClaims{
+			claims: &VpnClaims{StandardClaims: jwt.StandardClaims{
 				Audience:  "hello",
 				ExpiresAt: time.Now().Add(time.Hour).Unix(),
 				Issuer:    "vpn-server",
 				Subject:   "vpn jwt token",
-			},
+			}},
 			salt:  []byte("allan"),
 			valid: true,
 		},
@@ -49,3 +58,129 @@ func TestEncodeAndDecodeJWT(t *testing.T) {
 		}
 	}
 }
+
+func testJWTKeyPEM(t *testing.T, key crypto.Signer, public bool) []byte {
+	var der []byte
+	var err error
+	typ := "PRIVATE KEY"
+	if public {
+		typ = "PUBLIC KEY"
+		der, err = x509.MarshalPKIXPublicKey(key.Public())
+	} else {
+		der, err = x509.MarshalPKCS8PrivateKey(key)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+}
+
+func TestParseJWTKey(t *testing.T) {
+	assert := assert.New(t)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	rsaPKCS1 := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+
+	tests := map[string]struct {
+		id      string
+		data    []byte
+		alg     string
+		private bool
+		isErr   bool
+	}{
+		"empty":       {isErr: true},
+		"invalid":     {id: "kid", data: []byte("invalid"), isErr: true},
+		"rsa-pkcs1":   {id: "kid", data: rsaPKCS1, alg: RS256, private: true},
+		"rsa":         {id: "kid", data: testJWTKeyPEM(t, rsaKey, false), alg: RS256, private: true},
+		"rsa-public":  {id: "kid", data: testJWTKeyPEM(t, rsaKey, true), alg: RS256},
+		"ec":          {id: "kid", data: testJWTKeyPEM(t, ecKey, false), alg: ES256, private: true},
+		"ec-public":   {id: "kid", data: testJWTKeyPEM(t, ecKey, true), alg: ES256},
+		"ed25519":     {id: "kid", data: testJWTKeyPEM(t, edKey, false), alg: EdDSA, private: true},
+		"ed25519-pub": {id: "kid", data: testJWTKeyPEM(t, edKey, true), alg: EdDSA},
+	}
+
+	for _, t := range tests {
+		key, err := ParseJWTKey(t.id, t.data)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Equal(t.id, key.Id)
+			assert.Equal(t.alg, key.Method.Alg())
+			assert.Equal(t.private, key.PrivateKey != nil)
+		}
+	}
+}
+
+func TestJWTKeySet(t *testing.T) {
+	assert := assert.New(t)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+
+	oldKey, err := ParseJWTKey("old", testJWTKeyPEM(t, rsaKey, false))
+	assert.NoError(err)
+	oldPublicKey, err := ParseJWTKey("old", testJWTKeyPEM(t, rsaKey, true))
+	assert.NoError(err)
+	ecJWTKey, err := ParseJWTKey("ec", testJWTKeyPEM(t, ecKey, false))
+	assert.NoError(err)
+	edJWTKey, err := ParseJWTKey("ed", testJWTKeyPEM(t, edKey, false))
+	assert.NoError(err)
+
+	_, err = NewJWTKeySet("unknown", []*JWTKey{oldKey})
+	assert.Error(err)
+	_, err = NewJWTKeySet("old", []*JWTKey{oldPublicKey})
+	assert.Error(err)
+	_, err = NewJWTKeySet("old", []*JWTKey{oldKey, oldPublicKey})
+	assert.Error(err)
+
+	claims := &VpnClaims{StandardClaims: jwt.StandardClaims{Audience: "allan", ExpiresAt: time.Now().Add(time.Hour).Unix()}}
+	oldSet, err := NewJWTKeySet("old", []*JWTKey{oldKey})
+	assert.NoError(err)
+	oldToken, err := oldSet.EncodeJWT(claims)
+	assert.NoError(err)
+	_, err = oldSet.EncodeJWT(nil)
+	assert.Error(err)
+
+	// rotate signing key, old key is kept for verifying only.
+	set, err := NewJWTKeySet("ed", []*JWTKey{oldPublicKey, ecJWTKey, edJWTKey})
+	assert.NoError(err)
+	newToken, err := set.EncodeJWT(claims)
+	assert.NoError(err)
+	ecSet, err := NewJWTKeySet("ec", []*JWTKey{ecJWTKey})
+	assert.NoError(err)
+	ecToken, err := ecSet.EncodeJWT(claims)
+	assert.NoError(err)
+	hsToken, err := EncodeJWT(claims, []byte("allan"))
+	assert.NoError(err)
+
+	tests := map[string]struct {
+		set   *JWTKeySet
+		token string
+		kid   string
+		isErr bool
+	}{
+		"empty":       {set: set, isErr: true},
+		"rotated":     {set: set, token: oldToken, kid: "old"},
+		"active":      {set: set, token: newToken, kid: "ed"},
+		"ec":          {set: set, token: ecToken, kid: "ec"},
+		"hs256":       {set: set, token: hsToken, isErr: true},
+		"unknown-kid": {set: oldSet, token: newToken, isErr: true},
+	}
+
+	for _, t := range tests {
+		token, err := t.set.DecodeJWT(t.token)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Equal(t.kid, token.Header["kid"])
+			assert.Equal("allan", token.Claims.(*VpnClaims).Audience)
+		}
+	}
+}
@@ -16,6 +16,19 @@ const (
 	TunTxLen = 300
 )
 
+// DNSBackup is an original dns setting which is restored when vpn is closed.
+type DNSBackup struct {
+	// linux(resolv.conf)
+	path    string // resolv.conf path
+	content []byte // original resolv.conf
+	link    string // original symbolic link(such as systemd-resolved)
+
+	// darwin(networksetup)
+	service       string   // network service name
+	servers       []string // original dns servers
+	searchDomains []string // original search domains
+}
+
 // IncreaseIP is to increase 1.
 func IncreaseIP(ip net.IP) {
 	for j := len(ip) - 1; j >= 0; j-- {
@@ -37,3 +50,33 @@ func GetIPByAddr(addr string) (net.IP, error) {
 	}
 	return addrs[seededRand.Int()%len(addrs)], nil
 }
+
+// IsReservedIP checks whether ip is a network address or a broadcast address(only IPv4) in subnet.
+func IsReservedIP(ip net.IP, subnet *net.IPNet) bool {
+	if ip == nil || subnet == nil {
+		return false
+	}
+
+	if v4 := ip.To4(); v4 != nil && len(subnet.Mask) == net.IPv4len {
+		ip = v4
+	}
+	if len(ip) != len(subnet.Mask) {
+		return false
+	}
+
+	// network address
+	if ip.Mask(subnet.Mask).Equal(ip) {
+		return true
+	}
+
+	// broadcast address (IPv6 doesn't have broadcast.)
+	if len(ip) != net.IPv4len {
+		return false
+	}
+	for i := range ip {
+		if ip[i]|subnet.Mask[i] != 0xff {
+			return false
+		}
+	}
+	return true
+}
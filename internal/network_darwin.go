@@ -26,6 +26,14 @@ func SetTunIP(tun string, localAddr net.IP, addr *net.IPNet) error {
 	return CommandExec("ipconfig", args)
 }
 
+// SetTunIP6 sets the local IPv6 address of a network interface.
+func SetTunIP6(tun string, localAddr net.IP, addr *net.IPNet) error {
+	ones, _ := addr.Mask.Size()
+	sub := fmt.Sprintf("%s inet6 %s prefixlen %d", tun, localAddr.String(), ones)
+	args := strings.Split(sub, " ")
+	return CommandExec("ifconfig", args)
+}
+
 // SetDefaultGateway sets the systems gateway to the IP / device specified.
 func SetDefaultGateway(gw, tun string) error {
 	sub := fmt.Sprintf("-n change default -interface %s", tun)
@@ -33,26 +41,116 @@ func SetDefaultGateway(gw, tun string) error {
 	return CommandExec("route", args)
 }
 
+// SetDefaultGateway6 redirects all IPv6 traffic to the device specified.
+// it adds two halves of default route(::/1, 8000::/1) which are removed with the device.
+func SetDefaultGateway6(gw, tun string) error {
+	for _, dest := range []string{"::/1", "8000::/1"} {
+		sub := fmt.Sprintf("-n add -inet6 %s -interface %s", dest, tun)
+		args := strings.Split(sub, " ")
+		if err := CommandExec("route", args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // SetPacketForward sets ip packet forward.
 func SetPacketForward(ok bool) error {
 	// TODO: Don't support
 	return nil
 }
 
+// SetPacketForward6 sets ipv6 packet forward.
+func SetPacketForward6(ok bool) error {
+	// TODO: Don't support
+	return nil
+}
+
 // SetPostRoutingMasquerade sets outbound packets masquerade.
 func SetPostRoutingMasquerade(ok bool) error {
 	// TODO: Don't support
 	return nil
 }
 
-// SetGoogleDNS sets google dns
-func SetGoogleDNS() error {
-	return CommandExec("networksetup", []string{"-setdnsservers", "Wi-Fi", "8.8.8.8"})
+// SetPostRoutingMasquerade6 sets outbound ipv6 packets masquerade.
+func SetPostRoutingMasquerade6(ok bool) error {
+	// TODO: Don't support
+	return nil
+}
+
+// SetDNS sets dns servers and search domains on network service of device, and returns original setting.
+func SetDNS(dev string, servers, searchDomains []string) (*DNSBackup, error) {
+	output, err := CommandOutput("networksetup", []string{"-listnetworkserviceorder"})
+	if err != nil {
+		return nil, fmt.Errorf("[err] SetDNS %w", err)
+	}
+	service, err := parseNetworkService(output, dev)
+	if err != nil {
+		return nil, fmt.Errorf("[err] SetDNS %w", err)
+	}
+
+	backup := &DNSBackup{service: service}
+	if output, err := CommandOutput("networksetup", []string{"-getdnsservers", service}); err == nil {
+		backup.servers = parseNetworkSetupList(output)
+	}
+	if output, err := CommandOutput("networksetup", []string{"-getsearchdomains", service}); err == nil {
+		backup.searchDomains = parseNetworkSetupList(output)
+	}
+
+	if err := setNetworkSetupList("-setdnsservers", service, servers); err != nil {
+		return nil, fmt.Errorf("[err] SetDNS %w", err)
+	}
+	if err := setNetworkSetupList("-setsearchdomains", service, searchDomains); err != nil {
+		return nil, fmt.Errorf("[err] SetDNS %w", err)
+	}
+	return backup, nil
 }
 
-// SetDeleteDNS sets google dns
-func SetDeleteDNS() error {
-	return CommandExec("networksetup", []string{"-setdnsservers", "Wi-Fi", "empty"})
+// RestoreDNS restores original dns servers and search domains.
+func RestoreDNS(backup *DNSBackup) error {
+	if backup == nil {
+		return nil
+	}
+	if err := setNetworkSetupList("-setdnsservers", backup.service, backup.servers); err != nil {
+		return err
+	}
+	return setNetworkSetupList("-setsearchdomains", backup.service, backup.searchDomains)
+}
+
+// setNetworkSetupList sets list values(dns servers, search domains) on network service.
+func setNetworkSetupList(command, service string, values []string) error {
+	args := []string{command, service}
+	if len(values) == 0 {
+		args = append(args, "empty")
+	} else {
+		args = append(args, values...)
+	}
+	return CommandExec("networksetup", args)
+}
+
+// parseNetworkSetupList parses output of networksetup(-getdnsservers, -getsearchdomains).
+func parseNetworkSetupList(output []byte) []string {
+	var values []string
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.Contains(line, " ") {
+			// such as "There aren't any DNS Servers set on Wi-Fi."
+			continue
+		}
+		values = append(values, line)
+	}
+	return values
+}
+
+// parseNetworkService returns network service name of device from output of `networksetup -listnetworkserviceorder`.
+func parseNetworkService(output []byte, dev string) (string, error) {
+	rx := regexp.MustCompile(`(?m)^\(\*?\d+\)\s+(.+)\n\(Hardware Port: .*, Device: (.*)\)`)
+	for _, match := range rx.FindAllSubmatch(output, -1) {
+		if string(match[2]) == dev {
+			return strings.TrimSpace(string(match[1])), nil
+		}
+	}
+	return "", fmt.Errorf("[err] not found network service for %s", dev)
 }
 
 // AddRoute routes all traffic for addr via interface iName.
@@ -69,6 +167,42 @@ func DelRoute(addr, viaAddr net.IP, tun string) error {
 	return CommandExec("route", args)
 }
 
+// AddNetRoute routes traffic for subnet via gateway on device.
+func AddNetRoute(subnet *net.IPNet, viaAddr net.IP, dev string) error {
+	sub := fmt.Sprintf("-n add -net %s %s -ifscope %s", subnet.String(), viaAddr.String(), dev)
+	args := strings.Split(sub, " ")
+	return CommandExec("route", args)
+}
+
+// DelNetRoute deletes the route for subnet via gateway on device.
+func DelNetRoute(subnet *net.IPNet, viaAddr net.IP, dev string) error {
+	sub := fmt.Sprintf("-n delete -net %s %s -ifscope %s", subnet.String(), viaAddr.String(), dev)
+	args := strings.Split(sub, " ")
+	return CommandExec("route", args)
+}
+
+// AddTunRoute routes traffic for subnet via tun device.
+func AddTunRoute(subnet *net.IPNet, tun string) error {
+	family := "-inet"
+	if subnet.IP.To4() == nil {
+		family = "-inet6"
+	}
+	sub := fmt.Sprintf("-n add %s -net %s -interface %s", family, subnet.String(), tun)
+	args := strings.Split(sub, " ")
+	return CommandExec("route", args)
+}
+
+// DelTunRoute deletes the route for subnet via tun device.
+func DelTunRoute(subnet *net.IPNet, tun string) error {
+	family := "-inet"
+	if subnet.IP.To4() == nil {
+		family = "-inet6"
+	}
+	sub := fmt.Sprintf("-n delete %s -net %s -interface %s", family, subnet.String(), tun)
+	args := strings.Split(sub, " ")
+	return CommandExec("route", args)
+}
+
 // GetNetGateway returns net gateway (default route) and nic.
 func GetNetGateway() (gw, dev string, err error) {
 	cmd := exec.Command("route", "-n", "get", "default")
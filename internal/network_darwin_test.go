@@ -1 +1,55 @@
 package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNetworkService(t *testing.T) {
+	assert := assert.New(t)
+
+	output := []byte(`An asterisk (*) denotes that a network service is disabled.
+(1) USB 10/100/1000 LAN
+(Hardware Port: USB 10/100/1000 LAN, Device: en7)
+
+(2) Wi-Fi
+(Hardware Port: Wi-Fi, Device: en0)
+
+(*3) Thunderbolt Bridge
+(Hardware Port: Thunderbolt Bridge, Device: bridge0)
+`)
+
+	tests := map[string]struct {
+		dev    string
+		output string
+		isErr  bool
+	}{
+		"wifi":     {dev: "en0", output: "Wi-Fi"},
+		"lan":      {dev: "en7", output: "USB 10/100/1000 LAN"},
+		"disabled": {dev: "bridge0", output: "Thunderbolt Bridge"},
+		"notfound": {dev: "en9", isErr: true},
+	}
+
+	for _, t := range tests {
+		service, err := parseNetworkService(output, t.dev)
+		assert.Equal(t.isErr, err != nil)
+		assert.Equal(t.output, service)
+	}
+}
+
+func TestParseNetworkSetupList(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input  string
+		output []string
+	}{
+		"empty":   {input: "There aren't any DNS Servers set on Wi-Fi.\n"},
+		"servers": {input: "10.10.10.1\n8.8.8.8\n", output: []string{"10.10.10.1", "8.8.8.8"}},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.output, parseNetworkSetupList([]byte(t.input)))
+	}
+}
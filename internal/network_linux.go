@@ -4,12 +4,18 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
 	"strconv"
 	"strings"
 )
 
+var (
+	// resolvConfPath is a path of resolver configuration.
+	resolvConfPath = "/etc/resolv.conf"
+)
+
 // SetTunStatus is to up or down network device for TUN.
 func SetTunStatus(tun string, up bool) error {
 	status := "down"
@@ -28,6 +34,14 @@ func SetTunIP(tun string, localAddr net.IP, addr *net.IPNet) error {
 	return CommandExec("ifconfig", args)
 }
 
+// SetTunIP6 sets the local IPv6 address of a network interface.
+func SetTunIP6(tun string, localAddr net.IP, addr *net.IPNet) error {
+	ones, _ := addr.Mask.Size()
+	sub := fmt.Sprintf("-6 addr add %s/%d dev %s", localAddr.String(), ones, tun)
+	args := strings.Split(sub, " ")
+	return CommandExec("ip", args)
+}
+
 // SetDefaultGateway sets the systems gateway to the IP / device specified.
 func SetDefaultGateway(gw, tun string) error {
 	sub := fmt.Sprintf("add default gw %s dev %s", gw, tun)
@@ -35,6 +49,19 @@ func SetDefaultGateway(gw, tun string) error {
 	return CommandExec("route", args)
 }
 
+// SetDefaultGateway6 redirects all IPv6 traffic to the device specified.
+// it adds two halves of default route(::/1, 8000::/1) which are removed with the device.
+func SetDefaultGateway6(gw, tun string) error {
+	for _, dest := range []string{"::/1", "8000::/1"} {
+		sub := fmt.Sprintf("-6 route add %s via %s dev %s", dest, gw, tun)
+		args := strings.Split(sub, " ")
+		if err := CommandExec("ip", args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // SetPacketForward sets ip packet forward.
 func SetPacketForward(ok bool) error {
 	sub := fmt.Sprintf("net.ipv4.ip_forward=1")
@@ -44,6 +71,15 @@ func SetPacketForward(ok bool) error {
 	return CommandExec("sysctl", []string{sub})
 }
 
+// SetPacketForward6 sets ipv6 packet forward.
+func SetPacketForward6(ok bool) error {
+	sub := fmt.Sprintf("net.ipv6.conf.all.forwarding=1")
+	if !ok {
+		sub = fmt.Sprintf("net.ipv6.conf.all.forwarding=0")
+	}
+	return CommandExec("sysctl", []string{sub})
+}
+
 // SetPostRoutingMasquerade sets outbound packets masquerade.
 func SetPostRoutingMasquerade(ok bool) error {
 	var sub string
@@ -56,16 +92,72 @@ func SetPostRoutingMasquerade(ok bool) error {
 	return CommandExec("iptables", args)
 }
 
-// SetGoogleDNS sets google dns
-func SetGoogleDNS() error {
-	// TODO: Don't support
-	return nil
+// SetPostRoutingMasquerade6 sets outbound ipv6 packets masquerade.
+func SetPostRoutingMasquerade6(ok bool) error {
+	var sub string
+	if ok {
+		sub = "-t nat -A POSTROUTING -j MASQUERADE"
+	} else {
+		sub = "-t nat -D POSTROUTING 1"
+	}
+	args := strings.Split(sub, " ")
+	return CommandExec("ip6tables", args)
 }
 
-// SetDeleteDNS sets google dns
-func SetDeleteDNS() error {
-	// TODO: Don't support
-	return nil
+// SetDNS rewrites resolv.conf with dns servers and search domains, and returns original setting.
+// dev is not used on linux.
+func SetDNS(dev string, servers, searchDomains []string) (*DNSBackup, error) {
+	_ = dev
+	backup := &DNSBackup{path: resolvConfPath}
+
+	if link, err := os.Readlink(resolvConfPath); err == nil {
+		backup.link = link
+	}
+	content, err := ioutil.ReadFile(resolvConfPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("[err] SetDNS %w", err)
+	}
+	backup.content = content
+
+	// don't overwrite the file linked(such as systemd-resolved).
+	if backup.link != "" {
+		if err := os.Remove(resolvConfPath); err != nil {
+			return nil, fmt.Errorf("[err] SetDNS %w", err)
+		}
+	}
+
+	if err := ioutil.WriteFile(resolvConfPath, MakeResolvConf(servers, searchDomains), 0644); err != nil {
+		return nil, fmt.Errorf("[err] SetDNS %w", err)
+	}
+	return backup, nil
+}
+
+// RestoreDNS restores original resolv.conf.
+func RestoreDNS(backup *DNSBackup) error {
+	if backup == nil {
+		return nil
+	}
+
+	if backup.link != "" {
+		if err := os.Remove(backup.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("[err] RestoreDNS %w", err)
+		}
+		return os.Symlink(backup.link, backup.path)
+	}
+	return ioutil.WriteFile(backup.path, backup.content, 0644)
+}
+
+// MakeResolvConf returns resolv.conf contents.
+func MakeResolvConf(servers, searchDomains []string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("# generated by grpc-vpn\n")
+	for _, server := range servers {
+		buf.WriteString(fmt.Sprintf("nameserver %s\n", server))
+	}
+	if len(searchDomains) > 0 {
+		buf.WriteString(fmt.Sprintf("search %s\n", strings.Join(searchDomains, " ")))
+	}
+	return buf.Bytes()
 }
 
 // AddRoute routes all traffic for addr via interface tunName.
@@ -82,6 +174,34 @@ func DelRoute(addr, viaAddr net.IP, tun string) error {
 	return CommandExec("route", args)
 }
 
+// AddNetRoute routes traffic for subnet via gateway on device.
+func AddNetRoute(subnet *net.IPNet, viaAddr net.IP, dev string) error {
+	sub := fmt.Sprintf("route add %s via %s dev %s", subnet.String(), viaAddr.String(), dev)
+	args := strings.Split(sub, " ")
+	return CommandExec("ip", args)
+}
+
+// DelNetRoute deletes the route for subnet via gateway on device.
+func DelNetRoute(subnet *net.IPNet, viaAddr net.IP, dev string) error {
+	sub := fmt.Sprintf("route del %s via %s dev %s", subnet.String(), viaAddr.String(), dev)
+	args := strings.Split(sub, " ")
+	return CommandExec("ip", args)
+}
+
+// AddTunRoute routes traffic for subnet via tun device.
+func AddTunRoute(subnet *net.IPNet, tun string) error {
+	sub := fmt.Sprintf("route add %s dev %s", subnet.String(), tun)
+	args := strings.Split(sub, " ")
+	return CommandExec("ip", args)
+}
+
+// DelTunRoute deletes the route for subnet via tun device.
+func DelTunRoute(subnet *net.IPNet, tun string) error {
+	sub := fmt.Sprintf("route del %s dev %s", subnet.String(), tun)
+	args := strings.Split(sub, " ")
+	return CommandExec("ip", args)
+}
+
 // GetNetGateway return net gateway (default route) and nic.
 func GetNetGateway() (gw, dev string, err error) {
 	file, err := os.Open("/proc/net/route")
@@ -1 +1,78 @@
 package internal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeResolvConf(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		servers []string
+		domains []string
+		output  string
+	}{
+		"servers": {
+			servers: []string{"10.10.10.1", "8.8.8.8"},
+			output:  "# generated by grpc-vpn\nnameserver 10.10.10.1\nnameserver 8.8.8.8\n",
+		},
+		"search": {
+			servers: []string{"10.10.10.1"},
+			domains: []string{"corp.example.com", "example.com"},
+			output:  "# generated by grpc-vpn\nnameserver 10.10.10.1\nsearch corp.example.com example.com\n",
+		},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.output, string(MakeResolvConf(t.servers, t.domains)))
+	}
+}
+
+func TestSetDNS(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "resolv")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	origin := resolvConfPath
+	defer func() { resolvConfPath = origin }()
+
+	tests := map[string]struct {
+		link bool
+	}{
+		"file": {},
+		"link": {link: true},
+	}
+
+	for name, t := range tests {
+		resolvConfPath = filepath.Join(dir, name)
+		target := filepath.Join(dir, name+".origin")
+		assert.NoError(ioutil.WriteFile(target, []byte("nameserver 127.0.0.53\n"), 0644))
+		if t.link {
+			assert.NoError(os.Symlink(target, resolvConfPath))
+		} else {
+			assert.NoError(os.Rename(target, resolvConfPath))
+		}
+
+		backup, err := SetDNS("", []string{"10.10.10.1"}, nil)
+		assert.NoError(err)
+		content, _ := ioutil.ReadFile(resolvConfPath)
+		assert.Equal("# generated by grpc-vpn\nnameserver 10.10.10.1\n", string(content))
+
+		assert.NoError(RestoreDNS(backup))
+		content, _ = ioutil.ReadFile(resolvConfPath)
+		assert.Equal("nameserver 127.0.0.53\n", string(content))
+		link, _ := os.Readlink(resolvConfPath)
+		if t.link {
+			assert.Equal(target, link)
+		} else {
+			assert.Empty(link)
+		}
+	}
+}
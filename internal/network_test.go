@@ -52,3 +52,28 @@ func TestGetIPByAddr(t *testing.T) {
 		log.Println(ip)
 	}
 }
+
+func TestIsReservedIP(t *testing.T) {
+	assert := assert.New(t)
+
+	_, subnet, _ := net.ParseCIDR("10.10.10.0/24")
+	_, subnet6, _ := net.ParseCIDR("fd00:10::/64")
+	tests := map[string]struct {
+		ip     net.IP
+		subnet *net.IPNet
+		ok     bool
+	}{
+		"empty":         {},
+		"host":          {ip: net.ParseIP("10.10.10.10"), subnet: subnet},
+		"network":       {ip: net.ParseIP("10.10.10.0"), subnet: subnet, ok: true},
+		"broadcast":     {ip: net.ParseIP("10.10.10.255"), subnet: subnet, ok: true},
+		"mismatch":      {ip: net.ParseIP("fd00:10::"), subnet: subnet},
+		"ipv6-host":     {ip: net.ParseIP("fd00:10::ff"), subnet: subnet6},
+		"ipv6-network":  {ip: net.ParseIP("fd00:10::"), subnet: subnet6, ok: true},
+		"ipv6-all-ones": {ip: net.ParseIP("fd00:10::ffff:ffff:ffff:ffff"), subnet: subnet6},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.ok, IsReservedIP(t.ip, t.subnet))
+	}
+}
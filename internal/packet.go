@@ -0,0 +1,109 @@
+package internal
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+const (
+	// IPv4HeaderSize is minimum header size in IPv4 packet.
+	IPv4HeaderSize = 20
+
+	// IPv6HeaderSize is fixed header size in IPv6 packet.
+	IPv6HeaderSize = 40
+)
+
+const (
+	// ProtocolICMP is a protocol number of ICMP.
+	ProtocolICMP = 1
+
+	// ProtocolTCP is a protocol number of TCP.
+	ProtocolTCP = 6
+
+	// ProtocolUDP is a protocol number of UDP.
+	ProtocolUDP = 17
+
+	// ProtocolICMPv6 is a protocol number of ICMPv6.
+	ProtocolICMPv6 = 58
+)
+
+// IsIPv4Packet checks whether packet is IPv4 or not.
+func IsIPv4Packet(packet []byte) bool {
+	return len(packet) >= IPv4HeaderSize && packet[0]>>4 == 4
+}
+
+// IsIPv6Packet checks whether packet is IPv6 or not.
+func IsIPv6Packet(packet []byte) bool {
+	return len(packet) >= IPv6HeaderSize && packet[0]>>4 == 6
+}
+
+// PacketSource returns source ip in IPv4 or IPv6 packet.
+// if packet is invalid, it returns nil.
+func PacketSource(packet []byte) net.IP {
+	switch {
+	case IsIPv4Packet(packet):
+		return net.IPv4(packet[12], packet[13], packet[14], packet[15])
+	case IsIPv6Packet(packet):
+		ip := make(net.IP, net.IPv6len)
+		copy(ip, packet[8:24])
+		return ip
+	default:
+		return nil
+	}
+}
+
+// PacketDestination returns destination ip in IPv4 or IPv6 packet.
+// if packet is invalid, it returns nil.
+func PacketDestination(packet []byte) net.IP {
+	switch {
+	case IsIPv4Packet(packet):
+		return net.IPv4(packet[16], packet[17], packet[18], packet[19])
+	case IsIPv6Packet(packet):
+		ip := make(net.IP, net.IPv6len)
+		copy(ip, packet[24:40])
+		return ip
+	default:
+		return nil
+	}
+}
+
+// PacketProtocol returns protocol number(such as tcp, udp) in IPv4 or IPv6 packet.
+// IPv6 extension headers aren't parsed, so it returns the first next header.
+func PacketProtocol(packet []byte) uint8 {
+	switch {
+	case IsIPv4Packet(packet):
+		return packet[9]
+	case IsIPv6Packet(packet):
+		return packet[6]
+	default:
+		return 0
+	}
+}
+
+// PacketDestinationPort returns destination port in tcp or udp packet.
+// if packet isn't tcp or udp or is a non-first fragment, it returns 0.
+func PacketDestinationPort(packet []byte) uint16 {
+	var offset int
+	switch {
+	case IsIPv4Packet(packet):
+		// fragment offset
+		if binary.BigEndian.Uint16(packet[6:8])&0x1fff != 0 {
+			return 0
+		}
+		offset = int(packet[0]&0x0f) * 4
+	case IsIPv6Packet(packet):
+		offset = IPv6HeaderSize
+	default:
+		return 0
+	}
+
+	switch PacketProtocol(packet) {
+	case ProtocolTCP, ProtocolUDP:
+	default:
+		return 0
+	}
+	if len(packet) < offset+4 {
+		return 0
+	}
+	return binary.BigEndian.Uint16(packet[offset+2 : offset+4])
+}
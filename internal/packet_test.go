@@ -0,0 +1,125 @@
+package internal
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testIPv4Packet(src, dest net.IP) []byte {
+	packet := make([]byte, IPv4HeaderSize)
+	packet[0] = 0x45
+	copy(packet[12:16], src.To4())
+	copy(packet[16:20], dest.To4())
+	return packet
+}
+
+func testIPv6Packet(src, dest net.IP) []byte {
+	packet := make([]byte, IPv6HeaderSize)
+	packet[0] = 0x60
+	copy(packet[8:24], src.To16())
+	copy(packet[24:40], dest.To16())
+	return packet
+}
+
+func TestIsIPv4Packet(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input []byte
+		ok    bool
+	}{
+		"empty": {input: []byte{}},
+		"short": {input: []byte{0x45}},
+		"ipv6":  {input: testIPv6Packet(net.ParseIP("fd00::2"), net.ParseIP("fd00::1"))},
+		"ipv4":  {input: testIPv4Packet(net.ParseIP("10.0.0.2"), net.ParseIP("10.0.0.1")), ok: true},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.ok, IsIPv4Packet(t.input))
+	}
+}
+
+func TestIsIPv6Packet(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input []byte
+		ok    bool
+	}{
+		"empty": {input: []byte{}},
+		"short": {input: []byte{0x60}},
+		"ipv4":  {input: testIPv4Packet(net.ParseIP("10.0.0.2"), net.ParseIP("10.0.0.1"))},
+		"ipv6":  {input: testIPv6Packet(net.ParseIP("fd00::2"), net.ParseIP("fd00::1")), ok: true},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.ok, IsIPv6Packet(t.input))
+	}
+}
+
+func TestPacketSourceAndDestination(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input []byte
+		src   net.IP
+		dest  net.IP
+	}{
+		"invalid": {input: []byte{0x00}},
+		"ipv4": {
+			input: testIPv4Packet(net.ParseIP("10.0.0.2"), net.ParseIP("10.0.0.1")),
+			src:   net.ParseIP("10.0.0.2"),
+			dest:  net.ParseIP("10.0.0.1"),
+		},
+		"ipv6": {
+			input: testIPv6Packet(net.ParseIP("fd00::2"), net.ParseIP("fd00::1")),
+			src:   net.ParseIP("fd00::2"),
+			dest:  net.ParseIP("fd00::1"),
+		},
+	}
+
+	for _, t := range tests {
+		src := PacketSource(t.input)
+		dest := PacketDestination(t.input)
+		assert.True(t.src.Equal(src))
+		assert.True(t.dest.Equal(dest))
+	}
+}
+
+func TestPacketProtocolAndDestinationPort(t *testing.T) {
+	assert := assert.New(t)
+
+	withTransport := func(packet []byte, protocol uint8, port uint16) []byte {
+		if IsIPv4Packet(packet) {
+			packet[9] = protocol
+		} else {
+			packet[6] = protocol
+		}
+		transport := make([]byte, 8)
+		transport[2] = byte(port >> 8)
+		transport[3] = byte(port)
+		return append(packet, transport...)
+	}
+	fragment := withTransport(testIPv4Packet(net.ParseIP("10.0.0.2"), net.ParseIP("10.0.0.1")), ProtocolUDP, 53)
+	fragment[7] = 0x10
+
+	tests := map[string]struct {
+		input    []byte
+		protocol uint8
+		port     uint16
+	}{
+		"invalid":       {input: []byte{0x00}},
+		"ipv4-tcp":      {input: withTransport(testIPv4Packet(net.ParseIP("10.0.0.2"), net.ParseIP("10.0.0.1")), ProtocolTCP, 443), protocol: ProtocolTCP, port: 443},
+		"ipv4-icmp":     {input: withTransport(testIPv4Packet(net.ParseIP("10.0.0.2"), net.ParseIP("10.0.0.1")), ProtocolICMP, 443), protocol: ProtocolICMP},
+		"ipv4-fragment": {input: fragment, protocol: ProtocolUDP},
+		"ipv6-udp":      {input: withTransport(testIPv6Packet(net.ParseIP("fd00::2"), net.ParseIP("fd00::1")), ProtocolUDP, 53), protocol: ProtocolUDP, port: 53},
+		"ipv6-short":    {input: testIPv6Packet(net.ParseIP("fd00::2"), net.ParseIP("fd00::1")), protocol: 0},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.protocol, PacketProtocol(t.input))
+		assert.Equal(t.port, PacketDestinationPort(t.input))
+	}
+}
@@ -1,5 +1,7 @@
 package internal
 
+import "sort"
+
 // IsMatchedStringFromSlice checks whether to be matched string from slice or not.
 func IsMatchedStringFromSlice(s string, slice []string) bool {
 	for _, e := range slice {
@@ -9,3 +11,17 @@ func IsMatchedStringFromSlice(s string, slice []string) bool {
 	}
 	return false
 }
+
+// MergeStrings returns sorted strings of both slices without duplicates.
+func MergeStrings(values, others []string) []string {
+	set := map[string]bool{}
+	var merged []string
+	for _, value := range append(append([]string{}, values...), others...) {
+		if !set[value] {
+			set[value] = true
+			merged = append(merged, value)
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
@@ -21,3 +21,21 @@ func TestIsMatchedStringFromSlice(t *testing.T) {
 		assert.Equal(t.ok, IsMatchedStringFromSlice(t.check, t.slice))
 	}
 }
+
+func TestMergeStrings(t *testing.T) {
+	assert := assert.New(t)
+	tests := map[string]struct {
+		values []string
+		others []string
+		output []string
+	}{
+		"empty":  {},
+		"values": {values: []string{"ops", "dev"}, output: []string{"dev", "ops"}},
+		"others": {others: []string{"dev"}, output: []string{"dev"}},
+		"merged": {values: []string{"dev", "ops"}, others: []string{"admin", "dev"}, output: []string{"admin", "dev", "ops"}},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.output, MergeStrings(t.values, t.others))
+	}
+}
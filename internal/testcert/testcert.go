@@ -0,0 +1,77 @@
+// Package testcert provides certificate fixtures for mTLS tests.
+package testcert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Certificate is a certificate with its private key and PEM.
+type Certificate struct {
+	Cert *x509.Certificate
+	Key  *ecdsa.PrivateKey
+	PEM  string
+}
+
+// New returns a certificate which is signed by parent(if parent is nil, it's self-signed CA).
+func New(template *x509.Certificate, parent *Certificate) (*Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	parentCert, parentKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		parentCert, parentKey = parent.Cert, parent.Key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &Certificate{Cert: cert, Key: key,
+		PEM: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))}, nil
+}
+
+// KeyPEM returns PEM of private key.
+func (c *Certificate) KeyPEM() (string, error) {
+	der, err := x509.MarshalECPrivateKey(c.Key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})), nil
+}
+
+// PeerContext returns context of grpc peer whose verified chain is certs.
+func PeerContext(certs ...*Certificate) context.Context {
+	state := tls.ConnectionState{}
+	if len(certs) > 0 {
+		chain := make([]*x509.Certificate, 0, len(certs))
+		for _, c := range certs {
+			chain = append(chain, c.Cert)
+		}
+		state.VerifiedChains = [][]*x509.Certificate{chain}
+	}
+	return peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+}
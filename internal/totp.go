@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// https://tools.ietf.org/html/rfc6238 TOTP: Time-Based One-Time Password Algorithm
+const (
+	TOTPPeriod = 30 * time.Second
+	TOTPDigits = 6
+
+	totpSecretSize = 20
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a random base32 totp secret.
+func GenerateTOTPSecret() (string, error) {
+	buf := make([]byte, totpSecretSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Wrapf(err, "Method: GenerateTOTPSecret")
+	}
+	return totpEncoding.EncodeToString(buf), nil
+}
+
+// TOTPCode returns totp code of secret for counter(unix time / period).
+func TOTPCode(secret string, counter int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(strings.Replace(secret, " ", "", -1), "=")))
+	if err != nil || len(key) == 0 {
+		return "", errors.Wrapf(ErrorInvalidParams, "Method: TOTPCode")
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", TOTPDigits, value%1000000), nil
+}
+
+// TOTPCounter returns totp counter of time.
+func TOTPCounter(t time.Time) int64 {
+	return t.Unix() / int64(TOTPPeriod/time.Second)
+}
+
+// ValidateTOTP checks code of secret at time, and one period of clock skew is allowed.
+// it returns counter of matched code which is used to refuse replaying the code.
+func ValidateTOTP(secret, code string, t time.Time) (int64, bool) {
+	if len(code) != TOTPDigits {
+		return 0, false
+	}
+	now := TOTPCounter(t)
+	for _, counter := range []int64{now, now - 1, now + 1} {
+		expected, err := TOTPCode(secret, counter)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return counter, true
+		}
+	}
+	return 0, false
+}
+
+// TOTPURI returns otpauth uri of secret which authenticator apps register by QR code.
+func TOTPURI(issuer, user, secret string) string {
+	values := url.Values{}
+	values.Set("secret", secret)
+	values.Set("issuer", issuer)
+	values.Set("period", fmt.Sprintf("%d", int64(TOTPPeriod/time.Second)))
+	values.Set("digits", fmt.Sprintf("%d", TOTPDigits))
+	label := url.PathEscape(issuer + ":" + user)
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, values.Encode())
+}
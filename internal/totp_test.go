@@ -0,0 +1,78 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// secret of rfc6238 test vectors("12345678901234567890").
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	assert := assert.New(t)
+
+	secret, err := GenerateTOTPSecret()
+	assert.NoError(err)
+	assert.Len(secret, 32)
+	other, err := GenerateTOTPSecret()
+	assert.NoError(err)
+	assert.NotEqual(secret, other)
+
+	_, err = TOTPCode(secret, 1)
+	assert.NoError(err)
+}
+
+func TestTOTPCode(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		secret string
+		time   int64
+		output string
+		isErr  bool
+	}{
+		"invalid":    {secret: "1", isErr: true},
+		"59":         {secret: testTOTPSecret, time: 59, output: "287082"},
+		"1111111109": {secret: testTOTPSecret, time: 1111111109, output: "081804"},
+		"1234567890": {secret: testTOTPSecret, time: 1234567890, output: "005924"},
+		"lower":      {secret: strings.ToLower(testTOTPSecret), time: 2000000000, output: "279037"},
+	}
+
+	for _, t := range tests {
+		code, err := TOTPCode(t.secret, TOTPCounter(time.Unix(t.time, 0)))
+		assert.Equal(t.isErr, err != nil)
+		assert.Equal(t.output, code)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Unix(1111111109, 0)
+	tests := map[string]struct {
+		code    string
+		counter int64
+		ok      bool
+	}{
+		"empty":   {},
+		"invalid": {code: "000000"},
+		"now":     {code: "081804", counter: TOTPCounter(now), ok: true},
+		"before":  {code: "731029", counter: TOTPCounter(now) - 1, ok: true},
+	}
+
+	for _, t := range tests {
+		counter, ok := ValidateTOTP(testTOTPSecret, t.code, now)
+		assert.Equal(t.ok, ok)
+		assert.Equal(t.counter, counter)
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("otpauth://totp/grpc-vpn:allan?digits=6&issuer=grpc-vpn&period=30&secret="+testTOTPSecret,
+		TOTPURI("grpc-vpn", "allan", testTOTPSecret))
+}
@@ -26,6 +26,13 @@ function build_vpn_client
    GOOS=linux GOARCH=amd64 go build -trimpath -ldflags='-s -w' -o $CURRENT/../dist/vpn-client-linux
 }
 
+function build_vpnctl
+{
+   cd $CURRENT/../cmd/vpnctl
+   GOOS=darwin GOARCH=amd64 go build -trimpath -ldflags='-s -w' -o $CURRENT/../dist/vpnctl-darwin
+   GOOS=linux GOARCH=amd64 go build -trimpath -ldflags='-s -w' -o $CURRENT/../dist/vpnctl-linux
+}
+
 CMD=$1
 shift
 $CMD $*
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/pkg/errors"
+	"go.uber.org/atomic"
+)
+
+// ACLRule allows users, groups or roles to access destinations.
+// an empty field matches all.
+type ACLRule struct {
+	Users        []string // users(jwt audience)
+	Groups       []string // groups of users
+	Roles        []string // roles of users given by auth method(ex aws iam role)
+	Destinations []string // destination cidrs
+	Ports        []string // destination ports or port ranges(ex 443, 8000-8100)
+	Protocols    []string // protocols(tcp, udp, icmp or protocol number)
+}
+
+// ACLConfig is a config for network acl which is evaluated per packet from clients.
+type ACLConfig struct {
+	Rules        []*ACLRule // rules
+	DefaultAllow bool       // whether a packet which doesn't match any rules is allowed or not
+	LogDenied    bool       // whether denied packets are logged or not
+}
+
+type portRange struct {
+	from uint16
+	to   uint16
+}
+
+type aclRule struct {
+	users        map[string]bool // users
+	groups       map[string]bool // groups
+	roles        map[string]bool // roles
+	destinations []*net.IPNet    // destination cidrs
+	ports        []portRange     // destination ports
+	protocols    map[uint8]bool  // protocol numbers
+}
+
+type acl struct {
+	rules        []*aclRule     // rules
+	defaultAllow bool           // default action
+	logDenied    bool           // log denied packets
+	denied       *atomic.Uint64 // the number of denied packets
+}
+
+// match checks whether rule matches packet from client.
+func (r *aclRule) match(c *client, dest net.IP, protocol uint8, port uint16) bool {
+	if len(r.users) > 0 || len(r.groups) > 0 || len(r.roles) > 0 {
+		matched := r.users[c.user]
+		for _, group := range c.groups {
+			matched = matched || r.groups[group]
+		}
+		for _, role := range c.roles {
+			matched = matched || r.roles[role]
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(r.destinations) > 0 {
+		matched := false
+		for _, subnet := range r.destinations {
+			matched = matched || subnet.Contains(dest)
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(r.protocols) > 0 && !r.protocols[protocol] {
+		return false
+	}
+
+	if len(r.ports) > 0 {
+		matched := false
+		for _, pr := range r.ports {
+			matched = matched || (port != 0 && pr.from <= port && port <= pr.to)
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// allow checks whether packet from client is allowed, and counts denied packets.
+func (a *acl) allow(c *client, packet []byte) bool {
+	dest := internal.PacketDestination(packet)
+	if c != nil && dest != nil {
+		protocol := internal.PacketProtocol(packet)
+		port := internal.PacketDestinationPort(packet)
+		for _, rule := range a.rules {
+			if rule.match(c, dest, protocol, port) {
+				return true
+			}
+		}
+		if a.defaultAllow {
+			return true
+		}
+	}
+
+	a.denied.Inc()
+	if a.logDenied {
+		var user string
+		if c != nil {
+			user = c.user
+		}
+		defaultLogger.Warn(color.YellowString("[DENY] %s %s -> %s protocol(%d) port(%d)", user,
+			internal.PacketSource(packet), dest, internal.PacketProtocol(packet), internal.PacketDestinationPort(packet)))
+	}
+	return false
+}
+
+// newACL returns network acl.
+func newACL(cfg *ACLConfig) (*acl, error) {
+	if cfg == nil {
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: newACL")
+	}
+
+	a := &acl{
+		defaultAllow: cfg.DefaultAllow,
+		logDenied:    cfg.LogDenied,
+		denied:       atomic.NewUint64(0),
+	}
+
+	for _, rule := range cfg.Rules {
+		if rule == nil {
+			continue
+		}
+		r := &aclRule{
+			users:     map[string]bool{},
+			groups:    map[string]bool{},
+			roles:     map[string]bool{},
+			protocols: map[uint8]bool{},
+		}
+		for _, user := range rule.Users {
+			r.users[user] = true
+		}
+		for _, group := range rule.Groups {
+			r.groups[group] = true
+		}
+		for _, role := range rule.Roles {
+			r.roles[role] = true
+		}
+		for _, dest := range rule.Destinations {
+			_, subnet, err := net.ParseCIDR(dest)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Method: newACL")
+			}
+			r.destinations = append(r.destinations, subnet)
+		}
+		for _, port := range rule.Ports {
+			pr, err := parsePortRange(port)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Method: newACL")
+			}
+			r.ports = append(r.ports, pr)
+		}
+		for _, protocol := range rule.Protocols {
+			numbers, err := parseProtocol(protocol)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Method: newACL")
+			}
+			for _, number := range numbers {
+				r.protocols[number] = true
+			}
+		}
+		a.rules = append(a.rules, r)
+	}
+	return a, nil
+}
+
+// parsePortRange parses port(ex 443) or port range(ex 8000-8100).
+func parsePortRange(s string) (portRange, error) {
+	seps := strings.SplitN(strings.TrimSpace(s), "-", 2)
+	from, err := strconv.ParseUint(seps[0], 10, 16)
+	if err != nil {
+		return portRange{}, err
+	}
+	to := from
+	if len(seps) == 2 {
+		if to, err = strconv.ParseUint(seps[1], 10, 16); err != nil {
+			return portRange{}, err
+		}
+	}
+	if from == 0 || from > to {
+		return portRange{}, errors.Wrapf(internal.ErrorInvalidParams, "Method: parsePortRange %s", s)
+	}
+	return portRange{from: uint16(from), to: uint16(to)}, nil
+}
+
+// parseProtocol parses protocol name or number. icmp matches both ICMP and ICMPv6.
+func parseProtocol(s string) ([]uint8, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "tcp":
+		return []uint8{internal.ProtocolTCP}, nil
+	case "udp":
+		return []uint8{internal.ProtocolUDP}, nil
+	case "icmp":
+		return []uint8{internal.ProtocolICMP, internal.ProtocolICMPv6}, nil
+	default:
+		number, err := strconv.ParseUint(s, 10, 8)
+		if err != nil {
+			return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: parseProtocol %s", s)
+		}
+		return []uint8{uint8(number)}, nil
+	}
+}
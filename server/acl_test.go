@@ -0,0 +1,121 @@
+package server
+
+import (
+	"net"
+	"testing"
+
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPacket(src, dest string, protocol uint8, port uint16) []byte {
+	var packet []byte
+	if ip := net.ParseIP(src).To4(); ip != nil {
+		packet = make([]byte, internal.IPv4HeaderSize+8)
+		packet[0] = 0x45
+		packet[9] = protocol
+		copy(packet[12:16], ip)
+		copy(packet[16:20], net.ParseIP(dest).To4())
+	} else {
+		packet = make([]byte, internal.IPv6HeaderSize+8)
+		packet[0] = 0x60
+		packet[6] = protocol
+		copy(packet[8:24], net.ParseIP(src).To16())
+		copy(packet[24:40], net.ParseIP(dest).To16())
+	}
+	packet[len(packet)-6] = byte(port >> 8)
+	packet[len(packet)-5] = byte(port)
+	return packet
+}
+
+func TestNewACL(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		cfg   *ACLConfig
+		isErr bool
+	}{
+		"empty":            {isErr: true},
+		"invalid-cidr":     {cfg: &ACLConfig{Rules: []*ACLRule{{Destinations: []string{"allan"}}}}, isErr: true},
+		"invalid-port":     {cfg: &ACLConfig{Rules: []*ACLRule{{Ports: []string{"100-10"}}}}, isErr: true},
+		"invalid-protocol": {cfg: &ACLConfig{Rules: []*ACLRule{{Protocols: []string{"allan"}}}}, isErr: true},
+		"success": {cfg: &ACLConfig{Rules: []*ACLRule{{Users: []string{"allan"}, Destinations: []string{"10.0.0.0/8"},
+			Ports: []string{"22", "8000-8100"}, Protocols: []string{"tcp", "icmp", "132"}}}}},
+	}
+
+	for _, t := range tests {
+		_, err := newACL(t.cfg)
+		assert.Equal(t.isErr, err != nil)
+	}
+}
+
+func TestAcl_allow(t *testing.T) {
+	assert := assert.New(t)
+
+	a, err := newACL(&ACLConfig{Rules: []*ACLRule{
+		{Users: []string{"allan"}},
+		{Groups: []string{"dev"}, Destinations: []string{"10.0.0.0/8", "fd00:20::/64"}, Ports: []string{"22", "8000-8100"}, Protocols: []string{"tcp"}},
+		{Destinations: []string{"10.0.0.53/32"}, Protocols: []string{"udp"}, Ports: []string{"53"}},
+		{Roles: []string{"developer"}, Destinations: []string{"172.16.0.0/12"}},
+	}})
+	assert.NoError(err)
+
+	allan := &client{user: "allan"}
+	bob := &client{user: "bob", groups: []string{"dev"}}
+	carl := &client{user: "carl"}
+	dave := &client{user: "dave", roles: []string{"developer"}}
+
+	tests := map[string]struct {
+		client *client
+		packet []byte
+		allow  bool
+	}{
+		"unknown-client": {packet: testPacket("10.10.10.2", "10.0.0.1", internal.ProtocolTCP, 22)},
+		"user":           {client: allan, packet: testPacket("10.10.10.2", "8.8.8.8", internal.ProtocolUDP, 53), allow: true},
+		"group":          {client: bob, packet: testPacket("10.10.10.3", "10.0.0.1", internal.ProtocolTCP, 8080), allow: true},
+		"group-ipv6":     {client: bob, packet: testPacket("fd00::3", "fd00:20::1", internal.ProtocolTCP, 22), allow: true},
+		"group-port":     {client: bob, packet: testPacket("10.10.10.3", "10.0.0.1", internal.ProtocolTCP, 443)},
+		"group-protocol": {client: bob, packet: testPacket("10.10.10.3", "10.0.0.1", internal.ProtocolUDP, 22)},
+		"group-dest":     {client: bob, packet: testPacket("10.10.10.3", "172.16.0.1", internal.ProtocolTCP, 22)},
+		"everyone":       {client: carl, packet: testPacket("10.10.10.4", "10.0.0.53", internal.ProtocolUDP, 53), allow: true},
+		"deny":           {client: carl, packet: testPacket("10.10.10.4", "10.0.0.1", internal.ProtocolTCP, 22)},
+		"role":           {client: dave, packet: testPacket("10.10.10.5", "172.16.0.1", internal.ProtocolTCP, 22), allow: true},
+		"role-dest":      {client: dave, packet: testPacket("10.10.10.5", "10.0.0.1", internal.ProtocolTCP, 22)},
+	}
+
+	var denied uint64
+	for _, t := range tests {
+		assert.Equal(t.allow, a.allow(t.client, t.packet))
+		if !t.allow {
+			denied++
+		}
+	}
+	assert.Equal(denied, a.denied.Load())
+
+	// default allow
+	a, err = newACL(&ACLConfig{DefaultAllow: true})
+	assert.NoError(err)
+	assert.True(a.allow(carl, testPacket("10.10.10.4", "10.0.0.1", internal.ProtocolTCP, 22)))
+}
+
+func TestParsePortRange(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input  string
+		output portRange
+		isErr  bool
+	}{
+		"port":    {input: "22", output: portRange{from: 22, to: 22}},
+		"range":   {input: "8000-8100", output: portRange{from: 8000, to: 8100}},
+		"zero":    {input: "0", isErr: true},
+		"reverse": {input: "100-10", isErr: true},
+		"invalid": {input: "ssh", isErr: true},
+	}
+
+	for _, t := range tests {
+		pr, err := parsePortRange(t.input)
+		assert.Equal(t.isErr, err != nil)
+		assert.Equal(t.output, pr)
+	}
+}
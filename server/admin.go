@@ -0,0 +1,255 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/gjbae1212/grpc-vpn/auth"
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	adminMethodPrefix = "/vpn.Admin/"
+)
+
+type admin struct {
+	vpn *vpn // vpn
+}
+
+// ListSessions returns connected sessions, and it's GRPC METHOD.
+func (a *admin) ListSessions(ctx context.Context, req *protocol.ListSessionsRequest) (*protocol.ListSessionsResponse, error) {
+	_ = req
+	var sessions []*protocol.Session
+	for _, c := range a.vpn.sessions() {
+		session := &protocol.Session{
+			User:        c.user,
+			OriginIp:    c.originIP.String(),
+			ConnectedAt: c.connectedAt.Unix(),
+			BytesIn:     c.bytesIn.Load(),
+			BytesOut:    c.bytesOut.Load(),
+			Principal:   c.principal,
+			Groups:      c.groups,
+			Roles:       c.roles,
+			Attributes:  c.attributes,
+		}
+		if c.vpnIP != nil {
+			session.VpnIp = c.vpnIP.String()
+		}
+		if c.vpnIP6 != nil {
+			session.VpnIp6 = c.vpnIP6.String()
+		}
+		if token := c.getJwt(); token != nil {
+			if claims, ok := token.Claims.(*internal.VpnClaims); ok {
+				session.JwtId = claims.Id
+			}
+		}
+		sessions = append(sessions, session)
+	}
+
+	return &protocol.ListSessionsResponse{
+		ErrorCode: protocol.ErrorCode_EC_SUCCESS,
+		Sessions:  sessions,
+	}, nil
+}
+
+// KickSession kicks sessions by user or vpn ip, and it's GRPC METHOD.
+func (a *admin) KickSession(ctx context.Context, req *protocol.KickSessionRequest) (*protocol.KickSessionResponse, error) {
+	if req.User == "" && req.VpnIp == "" {
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: KickSession")
+	}
+
+	var vpnIP net.IP
+	if req.VpnIp != "" {
+		if vpnIP = net.ParseIP(req.VpnIp); vpnIP == nil {
+			return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: KickSession")
+		}
+	}
+
+	kicked := a.vpn.kick(func(c *client) bool {
+		if req.User != "" && c.user != req.User {
+			return false
+		}
+		if vpnIP != nil && !c.isVpnIP(vpnIP) {
+			return false
+		}
+		return true
+	})
+	defaultLogger.Info(color.YellowString("[ADMIN][KICK] user(%s) vpn IP(%s) kicked(%d)", req.User, req.VpnIp, kicked))
+
+	return &protocol.KickSessionResponse{
+		ErrorCode: protocol.ErrorCode_EC_SUCCESS,
+		Kicked:    int32(kicked),
+	}, nil
+}
+
+// RevokeUser refuses jwt of user which is issued until now, and kicks sessions of user, and it's GRPC METHOD.
+func (a *admin) RevokeUser(ctx context.Context, req *protocol.RevokeUserRequest) (*protocol.RevokeUserResponse, error) {
+	if req.User == "" {
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: RevokeUser")
+	}
+
+	if err := a.vpn.revocation.RevokeUser(req.User, time.Now()); err != nil {
+		return nil, errors.Wrapf(err, "Method: RevokeUser")
+	}
+	kicked := a.vpn.kick(func(c *client) bool {
+		return c.user == req.User
+	})
+	defaultLogger.Info(color.YellowString("[ADMIN][REVOKE] user(%s) kicked(%d)", req.User, kicked))
+
+	return &protocol.RevokeUserResponse{
+		ErrorCode: protocol.ErrorCode_EC_SUCCESS,
+		Kicked:    int32(kicked),
+	}, nil
+}
+
+// RevokeToken refuses jwt which has jwt id(jti), and kicks sessions using it, and it's GRPC METHOD.
+func (a *admin) RevokeToken(ctx context.Context, req *protocol.RevokeTokenRequest) (*protocol.RevokeTokenResponse, error) {
+	if req.JwtId == "" {
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: RevokeToken")
+	}
+
+	// jwt issued from now is expired before this time.
+	if err := a.vpn.revocation.RevokeToken(req.JwtId, time.Now().Add(a.vpn.jwtExpiration)); err != nil {
+		return nil, errors.Wrapf(err, "Method: RevokeToken")
+	}
+	kicked := a.vpn.kick(func(c *client) bool {
+		if token := c.getJwt(); token != nil {
+			if claims, ok := token.Claims.(*internal.VpnClaims); ok {
+				return claims.Id == req.JwtId
+			}
+		}
+		return false
+	})
+	defaultLogger.Info(color.YellowString("[ADMIN][REVOKE] jwt id(%s) kicked(%d)", req.JwtId, kicked))
+
+	return &protocol.RevokeTokenResponse{
+		ErrorCode: protocol.ErrorCode_EC_SUCCESS,
+		Kicked:    int32(kicked),
+	}, nil
+}
+
+// ListLeases returns vpn ip leases, and it's GRPC METHOD.
+func (a *admin) ListLeases(ctx context.Context, req *protocol.ListLeasesRequest) (*protocol.ListLeasesResponse, error) {
+	_ = req
+	var leases []*protocol.Lease
+	for _, l := range a.vpn.ipam.Leases() {
+		lease := &protocol.Lease{
+			User:     l.User,
+			Ip:       l.IP.String(),
+			Active:   l.Active,
+			Reserved: l.Reserved,
+		}
+		if !l.ExpiredAt.IsZero() {
+			lease.ExpiredAt = l.ExpiredAt.Unix()
+		}
+		leases = append(leases, lease)
+	}
+
+	return &protocol.ListLeasesResponse{
+		ErrorCode: protocol.ErrorCode_EC_SUCCESS,
+		Leases:    leases,
+	}, nil
+}
+
+// GetStats returns server statistics, and it's GRPC METHOD.
+func (a *admin) GetStats(ctx context.Context, req *protocol.GetStatsRequest) (*protocol.GetStatsResponse, error) {
+	_ = req
+	stats := &protocol.GetStatsResponse{
+		ErrorCode: protocol.ErrorCode_EC_SUCCESS,
+		StartedAt: a.vpn.startedAt.Unix(),
+	}
+
+	for _, c := range a.vpn.sessions() {
+		stats.Sessions++
+		stats.BytesIn += c.bytesIn.Load()
+		stats.BytesOut += c.bytesOut.Load()
+	}
+	for _, l := range a.vpn.ipam.Leases() {
+		if l.Active {
+			stats.ActiveLeases++
+		}
+	}
+	if a.vpn.acl != nil {
+		stats.AclDenied = a.vpn.acl.denied.Load()
+	}
+	return stats, nil
+}
+
+// newAdmin returns admin apis for vpn.
+func newAdmin(v VPN) (protocol.AdminServer, error) {
+	impl, ok := v.(*vpn)
+	if !ok {
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: newAdmin")
+	}
+	return &admin{vpn: impl}, nil
+}
+
+// sessions returns connected clients ordered by connected time.
+func (v *vpn) sessions() []*client {
+	v.clientsLock.RLock()
+	defer v.clientsLock.RUnlock()
+
+	// clients map has an entry per vpn ip.
+	var clients []*client
+	seen := map[*client]bool{}
+	for _, c := range v.clients {
+		if seen[c] {
+			continue
+		}
+		seen[c] = true
+		clients = append(clients, c)
+	}
+
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].connectedAt.Before(clients[j].connectedAt)
+	})
+	return clients
+}
+
+// kick kicks connected clients which are matched, and returns the number of kicked clients.
+func (v *vpn) kick(match func(c *client) bool) int {
+	var kicked int
+	for _, c := range v.sessions() {
+		if match(c) {
+			// session of kicked client isn't resumed.
+			c.kicked.Store(true)
+			c.kick()
+			kicked++
+		}
+	}
+	return kicked
+}
+
+// adminUnaryServerInterceptor returns unary server interceptor that checks admin token for admin apis.
+// rfc2617 (e.g. Authorization: bearer token)
+func adminUnaryServerInterceptor(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !strings.HasPrefix(info.FullMethod, adminMethodPrefix) {
+			return handler(ctx, req)
+		}
+
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok || len(md[auth.AuthorizationHeader]) == 0 {
+			return nil, errors.Wrapf(internal.ErrorUnauthorized, "Method: admin")
+		}
+
+		seps := strings.SplitN(md[auth.AuthorizationHeader][0], " ", 2)
+		if len(seps) != 2 || seps[0] != auth.Bearer {
+			return nil, errors.Wrapf(internal.ErrorUnauthorized, "Method: admin")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(seps[1]), []byte(token)) != 1 {
+			return nil, errors.Wrapf(internal.ErrorUnauthorized, "Method: admin")
+		}
+		return handler(ctx, req)
+	}
+}
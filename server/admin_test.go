@@ -0,0 +1,253 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gjbae1212/grpc-vpn/auth"
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func testAdminVPN(clients ...*client) *vpn {
+	v := &vpn{clients: map[string]*client{}, revocation: NewMemoryRevocationStore(), jwtExpiration: time.Hour}
+	for _, c := range clients {
+		c.exit = make(chan bool, 1)
+		c.bytesIn = atomic.NewUint64(10)
+		c.bytesOut = atomic.NewUint64(20)
+		c.originIP = net.ParseIP("1.1.1.1")
+		for _, ip := range c.vpnIPs() {
+			v.clients[ip.String()] = c
+		}
+	}
+	return v
+}
+
+func TestNewAdmin(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input VPN
+		isErr bool
+	}{
+		"empty":   {isErr: true},
+		"success": {input: &vpn{}},
+	}
+
+	for _, t := range tests {
+		_, err := newAdmin(t.input)
+		assert.Equal(t.isErr, err != nil)
+	}
+}
+
+func TestAdmin_ListSessions(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now()
+	allan := &client{user: "allan", vpnIP: net.ParseIP("10.10.10.2").To4(), vpnIP6: net.ParseIP("fd00::2"), connectedAt: now,
+		principal: "arn:aws:sts::123456789012:assumed-role/developer/allan", groups: []string{"dev"}, roles: []string{"developer"},
+		attributes: map[string]string{"account_id": "123456789012"}}
+	bob := &client{user: "bob", vpnIP: net.ParseIP("10.10.10.3").To4(), connectedAt: now.Add(-time.Minute)}
+	a := &admin{vpn: testAdminVPN(allan, bob)}
+
+	result, err := a.ListSessions(context.Background(), &protocol.ListSessionsRequest{})
+	assert.NoError(err)
+	assert.Equal(protocol.ErrorCode_EC_SUCCESS, result.ErrorCode)
+	assert.Len(result.Sessions, 2)
+	assert.Equal("bob", result.Sessions[0].User)
+	assert.Equal("10.10.10.3", result.Sessions[0].VpnIp)
+	assert.Equal("allan", result.Sessions[1].User)
+	assert.Equal("fd00::2", result.Sessions[1].VpnIp6)
+	assert.Equal("1.1.1.1", result.Sessions[1].OriginIp)
+	assert.Equal(now.Unix(), result.Sessions[1].ConnectedAt)
+	assert.Equal(uint64(10), result.Sessions[1].BytesIn)
+	assert.Equal(uint64(20), result.Sessions[1].BytesOut)
+	assert.Equal("arn:aws:sts::123456789012:assumed-role/developer/allan", result.Sessions[1].Principal)
+	assert.Equal([]string{"dev"}, result.Sessions[1].Groups)
+	assert.Equal([]string{"developer"}, result.Sessions[1].Roles)
+	assert.Equal(map[string]string{"account_id": "123456789012"}, result.Sessions[1].Attributes)
+	assert.Empty(result.Sessions[0].Groups)
+}
+
+func TestAdmin_KickSession(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		req    *protocol.KickSessionRequest
+		kicked int32
+		isErr  bool
+	}{
+		"empty":        {req: &protocol.KickSessionRequest{}, isErr: true},
+		"invalid-ip":   {req: &protocol.KickSessionRequest{VpnIp: "allan"}, isErr: true},
+		"user":         {req: &protocol.KickSessionRequest{User: "allan"}, kicked: 1},
+		"vpn-ip":       {req: &protocol.KickSessionRequest{VpnIp: "fd00::3"}, kicked: 1},
+		"mismatched":   {req: &protocol.KickSessionRequest{User: "allan", VpnIp: "10.10.10.3"}},
+		"unknown-user": {req: &protocol.KickSessionRequest{User: "carl"}},
+	}
+
+	for _, t := range tests {
+		allan := &client{user: "allan", vpnIP: net.ParseIP("10.10.10.2").To4()}
+		bob := &client{user: "bob", vpnIP: net.ParseIP("10.10.10.3").To4(), vpnIP6: net.ParseIP("fd00::3")}
+		a := &admin{vpn: testAdminVPN(allan, bob)}
+
+		result, err := a.KickSession(context.Background(), t.req)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Equal(t.kicked, result.Kicked)
+			assert.Equal(int(t.kicked), len(allan.exit)+len(bob.exit))
+		}
+	}
+}
+
+func TestAdmin_RevokeUser(t *testing.T) {
+	assert := assert.New(t)
+
+	allan := &client{user: "allan", vpnIP: net.ParseIP("10.10.10.2").To4()}
+	a := &admin{vpn: testAdminVPN(allan)}
+
+	_, err := a.RevokeUser(context.Background(), &protocol.RevokeUserRequest{})
+	assert.Error(err)
+
+	issued := &jwt.Token{Claims: &internal.VpnClaims{StandardClaims: jwt.StandardClaims{Audience: "allan", IssuedAt: time.Now().Unix()}}}
+	assert.False(a.vpn.isRevokedJwt(issued))
+
+	result, err := a.RevokeUser(context.Background(), &protocol.RevokeUserRequest{User: "allan"})
+	assert.NoError(err)
+	assert.Equal(int32(1), result.Kicked)
+	assert.Len(allan.exit, 1)
+
+	tests := map[string]struct {
+		token   *jwt.Token
+		revoked bool
+	}{
+		"issued":     {token: issued, revoked: true},
+		"reissued":   {token: &jwt.Token{Claims: &internal.VpnClaims{StandardClaims: jwt.StandardClaims{Audience: "allan", IssuedAt: time.Now().Add(time.Minute).Unix()}}}},
+		"other-user": {token: &jwt.Token{Claims: &internal.VpnClaims{StandardClaims: jwt.StandardClaims{Audience: "bob", IssuedAt: time.Now().Unix()}}}},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.revoked, a.vpn.isRevokedJwt(t.token))
+	}
+}
+
+func TestAdmin_RevokeToken(t *testing.T) {
+	assert := assert.New(t)
+
+	allan := &client{user: "allan", vpnIP: net.ParseIP("10.10.10.2").To4(),
+		jwt: &jwt.Token{Claims: &internal.VpnClaims{StandardClaims: jwt.StandardClaims{Id: "allan-jti", Audience: "allan"}}}}
+	bob := &client{user: "bob", vpnIP: net.ParseIP("10.10.10.3").To4(),
+		jwt: &jwt.Token{Claims: &internal.VpnClaims{StandardClaims: jwt.StandardClaims{Id: "bob-jti", Audience: "bob"}}}}
+	a := &admin{vpn: testAdminVPN(allan, bob)}
+
+	tests := map[string]struct {
+		input  *protocol.RevokeTokenRequest
+		kicked int32
+		isErr  bool
+	}{
+		"empty":   {input: &protocol.RevokeTokenRequest{}, isErr: true},
+		"unknown": {input: &protocol.RevokeTokenRequest{JwtId: "unknown"}},
+		"success": {input: &protocol.RevokeTokenRequest{JwtId: "allan-jti"}, kicked: 1},
+	}
+
+	for _, t := range tests {
+		result, err := a.RevokeToken(context.Background(), t.input)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Equal(t.kicked, result.Kicked)
+		}
+	}
+	assert.Len(allan.exit, 1)
+	assert.Len(bob.exit, 0)
+	assert.True(a.vpn.isRevokedJwt(allan.jwt))
+	assert.False(a.vpn.isRevokedJwt(bob.jwt))
+}
+
+func TestAdminUnaryServerInterceptor(t *testing.T) {
+	assert := assert.New(t)
+
+	interceptor := adminUnaryServerInterceptor("admin-token")
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	tests := map[string]struct {
+		method string
+		header string
+		isErr  bool
+	}{
+		"not-admin":     {method: "/vpn.VPN/Auth"},
+		"empty":         {method: "/vpn.Admin/ListSessions", isErr: true},
+		"invalid-type":  {method: "/vpn.Admin/ListSessions", header: auth.Basic + " admin-token", isErr: true},
+		"invalid-token": {method: "/vpn.Admin/ListSessions", header: auth.Bearer + " allan", isErr: true},
+		"success":       {method: "/vpn.Admin/ListSessions", header: auth.Bearer + " admin-token"},
+	}
+
+	for _, t := range tests {
+		ctx := context.Background()
+		if t.header != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(auth.AuthorizationHeader, t.header))
+		}
+		result, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: t.method}, handler)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Equal("ok", result)
+		}
+	}
+}
+
+func TestAdmin_ListLeases(t *testing.T) {
+	assert := assert.New(t)
+
+	gateway := net.ParseIP("10.10.10.1").To4()
+	_, subnet, _ := net.ParseCIDR("10.10.10.0/24")
+
+	v := testAdminVPN()
+	v.ipam = NewMemoryIPAM(time.Hour)
+	_, err := v.ipam.Allocate("allan", gateway, subnet)
+	assert.NoError(err)
+	bob, err := v.ipam.Allocate("bob", gateway, subnet)
+	assert.NoError(err)
+	assert.NoError(v.ipam.Release("bob", bob))
+
+	a := &admin{vpn: v}
+	result, err := a.ListLeases(context.Background(), &protocol.ListLeasesRequest{})
+	assert.NoError(err)
+	assert.Len(result.Leases, 2)
+	assert.Equal(&protocol.Lease{User: "allan", Ip: "10.10.10.2", Active: true}, result.Leases[0])
+	assert.Equal("bob", result.Leases[1].User)
+	assert.NotZero(result.Leases[1].ExpiredAt)
+}
+
+func TestAdmin_GetStats(t *testing.T) {
+	assert := assert.New(t)
+
+	gateway := net.ParseIP("10.10.10.1").To4()
+	_, subnet, _ := net.ParseCIDR("10.10.10.0/24")
+
+	v := testAdminVPN(&client{user: "allan", vpnIP: net.ParseIP("10.10.10.2").To4(), vpnIP6: net.ParseIP("fd00::2")},
+		&client{user: "bob", vpnIP: net.ParseIP("10.10.10.3").To4()})
+	v.startedAt = time.Now()
+	v.ipam = NewMemoryIPAM(time.Hour)
+	_, err := v.ipam.Allocate("allan", gateway, subnet)
+	assert.NoError(err)
+	v.acl, err = newACL(&ACLConfig{})
+	assert.NoError(err)
+	v.acl.denied.Add(3)
+
+	a := &admin{vpn: v}
+	result, err := a.GetStats(context.Background(), &protocol.GetStatsRequest{})
+	assert.NoError(err)
+	assert.Equal(v.startedAt.Unix(), result.StartedAt)
+	assert.Equal(int32(2), result.Sessions)
+	assert.Equal(int32(1), result.ActiveLeases)
+	assert.Equal(uint64(20), result.BytesIn)
+	assert.Equal(uint64(40), result.BytesOut)
+	assert.Equal(uint64(3), result.AclDenied)
+}
@@ -3,6 +3,7 @@ package server
 import (
 	"io"
 	"net"
+	"sync"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
@@ -10,22 +11,36 @@ import (
 	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
 	"github.com/gjbae1212/grpc-vpn/internal"
 	"github.com/pkg/errors"
-	"github.com/songgao/water/waterutil"
 	"go.uber.org/atomic"
 )
 
 const (
 	queueSizeForClientIn = 1000
+	jwtCheckInterval     = 5 * time.Minute // interval checking whether jwt of connected client is revoked or expired
 )
 
 type client struct {
-	user     string                      // user
-	originIP net.IP                      // user origin ip
-	vpnIP    net.IP                      // user vpn ip
-	jwt      *jwt.Token                  // user jwt token
-	stream   protocol.VPN_ExchangeServer // stream
-	loop     *atomic.Bool                // whether break loop or not
-	exit     chan bool                   // exit
+	user       string                      // user
+	originIP   net.IP                      // user origin ip
+	vpnIP      net.IP                      // user vpn ip
+	vpnIP6     net.IP                      // user vpn ipv6 (dual stack)
+	principal  string                      // user principal given by auth method
+	groups     []string                    // user groups
+	roles      []string                    // user roles given by auth method
+	attributes map[string]string           // user attributes given by auth method
+	jwt        *jwt.Token                  // user jwt token
+	jwtLock    sync.RWMutex                // user jwt token lock
+	renewer    jwtRenewer                  // renew jwt using refresh token
+	sessionID  string                      // session id for resuming session
+	revocation RevocationStore             // store of revoked jwt
+	stream     protocol.VPN_ExchangeServer // stream
+	loop       *atomic.Bool                // whether break loop or not
+	exit       chan bool                   // exit
+	kicked     atomic.Bool                 // whether client is kicked by admin(session of kicked client isn't held)
+
+	connectedAt time.Time      // connected time
+	bytesIn     *atomic.Uint64 // bytes from client
+	bytesOut    *atomic.Uint64 // bytes to client
 
 	out chan *protocol.IPPacket // out queue
 	in  chan *protocol.IPPacket // in queue
@@ -61,6 +76,9 @@ ReadLoop:
 		// check packet type
 		switch packet.PacketType {
 		case protocol.IPPacketType_IPPT_RAW:
+		case protocol.IPPacketType_IPPT_RENEW_JWT:
+			c.renewJwt(packet.Packet3)
+			continue
 		default:
 			defaultLogger.Error(color.RedString("[ERR] %s (%s, %s) %s",
 				c.user, c.originIP.String(), c.vpnIP.String(), internal.ErrorReceiveUnknownPacket.Error()))
@@ -76,14 +94,19 @@ ReadLoop:
 		}
 
 		// check source ip(equals vpn ip)
-		srcIP := waterutil.IPv4Source(raw.Raw)
-		if !srcIP.Equal(c.vpnIP) {
+		srcIP := internal.PacketSource(raw.Raw)
+		if srcIP != nil && srcIP.IsLinkLocalUnicast() && internal.IsIPv6Packet(raw.Raw) {
+			// ignore IPv6 link-local packets(neighbor discovery and so on).
+			continue
+		}
+		if !c.isVpnIP(srcIP) {
 			defaultLogger.Error(color.RedString("[ERR] %s (%s, %s) %s(%s)",
 				c.user, c.originIP.String(), c.vpnIP.String(), internal.ErrorReceiveUnknownPacket.Error(), srcIP))
 			break ReadLoop
 		}
 
 		// out to server
+		c.bytesIn.Add(uint64(len(raw.Raw)))
 		c.out <- packet
 	}
 
@@ -96,7 +119,7 @@ ReadLoop:
 // write packet
 func (c *client) processWriting() {
 	// make jwt checker
-	jwtChecker := time.NewTicker(5 * time.Minute)
+	jwtChecker := time.NewTicker(jwtCheckInterval)
 
 WriteLoop:
 	for c.loop.Load() {
@@ -107,13 +130,31 @@ WriteLoop:
 					c.user, c.originIP.String(), c.vpnIP.String(), err.Error()))
 				break WriteLoop
 			}
+			if packet.Packet1 != nil {
+				c.bytesOut.Add(uint64(len(packet.Packet1.Raw)))
+			}
 		case <-c.exit:
 			defaultLogger.Error(color.RedString("[ERR] %s (%s, %s) exit signal",
 				c.user, c.originIP.String(), c.vpnIP.String()))
 			break WriteLoop
 		case <-jwtChecker.C:
+			// if JWT is revoked, sending to error and break.
+			if isRevokedJwt(c.revocation, c.getJwt()) {
+				defaultLogger.Error(color.RedString("[ERR] %s (%s, %s) revoked JWT",
+					c.user, c.originIP.String(), c.vpnIP.String()))
+				packet := &protocol.IPPacket{
+					ErrorCode:  protocol.ErrorCode_EC_REVOKED_JWT,
+					PacketType: protocol.IPPacketType_IPPT_UNKNOWN,
+				}
+				if err := c.stream.Send(packet); err != nil {
+					defaultLogger.Error(color.RedString("[ERR] %s (%s, %s) %s",
+						c.user, c.originIP.String(), c.vpnIP.String(), err.Error()))
+				}
+				break WriteLoop
+			}
+
 			// if JWT is expired, sending to error and break.
-			if c.jwt.Claims.Valid() != nil {
+			if c.getJwt().Claims.Valid() != nil {
 				defaultLogger.Error(color.RedString("[ERR] %s (%s, %s) expired JWT",
 					c.user, c.originIP.String(), c.vpnIP.String()))
 				packet := &protocol.IPPacket{
@@ -134,9 +175,87 @@ WriteLoop:
 	c.loop.Store(false)
 }
 
+// renewJwt replaces jwt with new one issued by refresh token, and sends it to client.
+// failure of renewal isn't fatal, because current jwt is still valid until expired.
+func (c *client) renewJwt(renew *protocol.IPPacket_Renew) {
+	packet := &protocol.IPPacket{
+		ErrorCode:  protocol.ErrorCode_EC_INVALID_AUTHORIZATION,
+		PacketType: protocol.IPPacketType_IPPT_RENEW_JWT,
+	}
+
+	if renew == nil || c.renewer == nil {
+		defaultLogger.Error(color.RedString("[ERR] %s (%s, %s) %s",
+			c.user, c.originIP.String(), c.vpnIP.String(), internal.ErrorReceiveUnknownPacket.Error()))
+		c.in <- packet
+		return
+	}
+
+	token, renewed, err := c.renewer(c.user, renew.RefreshToken)
+	if err != nil {
+		defaultLogger.Error(color.RedString("[ERR] %s (%s, %s) renew JWT %s",
+			c.user, c.originIP.String(), c.vpnIP.String(), err.Error()))
+		c.in <- packet
+		return
+	}
+
+	c.setJwt(token)
+	defaultLogger.Info(color.GreenString("[RENEW] %s (%s, %s) JWT",
+		c.user, c.originIP.String(), c.vpnIP.String()))
+	packet.ErrorCode = protocol.ErrorCode_EC_SUCCESS
+	packet.Packet3 = renewed
+	c.in <- packet
+}
+
+// getJwt returns current jwt.
+func (c *client) getJwt() *jwt.Token {
+	c.jwtLock.RLock()
+	defer c.jwtLock.RUnlock()
+	return c.jwt
+}
+
+// setJwt replaces current jwt.
+func (c *client) setJwt(token *jwt.Token) {
+	c.jwtLock.Lock()
+	defer c.jwtLock.Unlock()
+	c.jwt = token
+}
+
+// kick signals client to exit.
+func (c *client) kick() {
+	select {
+	case c.exit <- true:
+	default:
+	}
+}
+
 // hasVpnIP is to check whether to be assigned vpn ip in client or not.
 func (c *client) hasVpnIP() bool {
-	return c.vpnIP != nil
+	return c.vpnIP != nil || c.vpnIP6 != nil
+}
+
+// isVpnIP is to check whether ip is one of vpn ips assigned to client.
+func (c *client) isVpnIP(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, vpnIP := range c.vpnIPs() {
+		if vpnIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// vpnIPs returns vpn ips assigned to client.
+func (c *client) vpnIPs() []net.IP {
+	var ips []net.IP
+	if c.vpnIP != nil {
+		ips = append(ips, c.vpnIP)
+	}
+	if c.vpnIP6 != nil {
+		ips = append(ips, c.vpnIP6)
+	}
+	return ips
 }
 
 // newClient is to create new client.
@@ -157,15 +276,22 @@ func newClient(stream protocol.VPN_ExchangeServer, clientToServer chan *protocol
 		return nil, errors.Wrapf(internal.ErrorInvalidContext, "Method: newClient")
 	}
 
+	claims := j.(*jwt.Token).Claims.(*internal.VpnClaims)
 	c := &client{
-		user:     j.(*jwt.Token).Claims.(*jwt.StandardClaims).Audience,
-		originIP: ip.(net.IP),
-		jwt:      j.(*jwt.Token),
-		stream:   stream,
-		loop:     atomic.NewBool(true),
-		exit:     make(chan bool, 1),
-		out:      clientToServer,                                      // vpn server  queue
-		in:       make(chan *protocol.IPPacket, queueSizeForClientIn), // only exclusive client queue
+		user:        claims.Audience,
+		principal:   claims.Principal,
+		roles:       claims.Roles,
+		attributes:  claims.Attributes,
+		originIP:    ip.(net.IP),
+		jwt:         j.(*jwt.Token),
+		stream:      stream,
+		loop:        atomic.NewBool(true),
+		exit:        make(chan bool, 1),
+		connectedAt: time.Now(),
+		bytesIn:     atomic.NewUint64(0),
+		bytesOut:    atomic.NewUint64(0),
+		out:         clientToServer,                                      // vpn server  queue
+		in:          make(chan *protocol.IPPacket, queueSizeForClientIn), // only exclusive client queue
 	}
 
 	return c, nil
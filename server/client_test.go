@@ -1 +1,69 @@
 package server
+
+import (
+	"net"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_isVpnIP(t *testing.T) {
+	assert := assert.New(t)
+
+	c := &client{vpnIP: net.ParseIP("10.10.10.2").To4(), vpnIP6: net.ParseIP("fd00:10::2")}
+	tests := map[string]struct {
+		input net.IP
+		ok    bool
+	}{
+		"empty":    {},
+		"other":    {input: net.ParseIP("10.10.10.3")},
+		"ipv4":     {input: net.ParseIP("10.10.10.2"), ok: true},
+		"ipv6":     {input: net.ParseIP("fd00:10::2"), ok: true},
+		"other-v6": {input: net.ParseIP("fd00:10::3")},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.ok, c.isVpnIP(t.input))
+	}
+}
+
+func TestClient_renewJwt(t *testing.T) {
+	assert := assert.New(t)
+
+	token := &jwt.Token{Claims: &internal.VpnClaims{StandardClaims: jwt.StandardClaims{Id: "old", Audience: "allan"}}}
+	renewedToken := &jwt.Token{Claims: &internal.VpnClaims{StandardClaims: jwt.StandardClaims{Id: "new", Audience: "allan"}}}
+	renewer := func(user, refreshToken string) (*jwt.Token, *protocol.IPPacket_Renew, error) {
+		if refreshToken != "refresh" {
+			return nil, nil, internal.ErrorInvalidJWT
+		}
+		return renewedToken, &protocol.IPPacket_Renew{Jwt: "jwt", RefreshToken: "new-refresh"}, nil
+	}
+
+	tests := map[string]struct {
+		renewer jwtRenewer
+		input   *protocol.IPPacket_Renew
+		output  protocol.ErrorCode
+		token   *jwt.Token
+	}{
+		"empty":    {renewer: renewer, output: protocol.ErrorCode_EC_INVALID_AUTHORIZATION, token: token},
+		"invalid":  {renewer: renewer, input: &protocol.IPPacket_Renew{RefreshToken: "invalid"}, output: protocol.ErrorCode_EC_INVALID_AUTHORIZATION, token: token},
+		"disabled": {input: &protocol.IPPacket_Renew{RefreshToken: "refresh"}, output: protocol.ErrorCode_EC_INVALID_AUTHORIZATION, token: token},
+		"success":  {renewer: renewer, input: &protocol.IPPacket_Renew{RefreshToken: "refresh"}, output: protocol.ErrorCode_EC_SUCCESS, token: renewedToken},
+	}
+
+	for _, t := range tests {
+		c := &client{user: "allan", originIP: net.ParseIP("1.1.1.1"), jwt: token, renewer: t.renewer,
+			in: make(chan *protocol.IPPacket, 1)}
+		c.renewJwt(t.input)
+		packet := <-c.in
+		assert.Equal(protocol.IPPacketType_IPPT_RENEW_JWT, packet.PacketType)
+		assert.Equal(t.output, packet.ErrorCode)
+		assert.Equal(t.token, c.getJwt())
+		if t.output == protocol.ErrorCode_EC_SUCCESS {
+			assert.Equal("new-refresh", packet.Packet3.RefreshToken)
+		}
+	}
+}
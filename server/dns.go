@@ -0,0 +1,277 @@
+package server
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/pkg/errors"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	defaultDNSPort     = "53"
+	defaultDNSUpstream = "8.8.8.8"
+	defaultDNSTimeout  = 5 * time.Second
+	defaultDNSTTL      = 60
+	maxDNSPacketSize   = 4096
+	maxDNSLabelSize    = 63
+)
+
+// DNSServerConfig is a config for dns forwarder which listens on vpn gateway ip.
+type DNSServerConfig struct {
+	Domain    string        // internal domain, connected clients are answered as <user>.vpn.<domain>
+	Upstreams []string      // upstream dns servers(ip or ip:port), default 8.8.8.8
+	Port      string        // listen port, default 53
+	Timeout   time.Duration // upstream timeout, default 5s
+}
+
+type dnsForwarder struct {
+	vpn       *vpn             // vpn
+	zone      string           // zone for clients(vpn.<domain>.)
+	port      string           // listen port
+	upstreams []string         // upstream dns servers(ip:port)
+	timeout   time.Duration    // upstream timeout
+	conns     []net.PacketConn // listened connections
+	lock      sync.Mutex       // conns lock
+}
+
+// newDNSForwarder returns dns forwarder for vpn.
+func newDNSForwarder(v *vpn, cfg *DNSServerConfig) (*dnsForwarder, error) {
+	if v == nil || cfg == nil {
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: newDNSForwarder")
+	}
+
+	f := &dnsForwarder{
+		vpn:     v,
+		port:    cfg.Port,
+		timeout: cfg.Timeout,
+	}
+	if f.port == "" {
+		f.port = defaultDNSPort
+	}
+	if f.timeout <= 0 {
+		f.timeout = defaultDNSTimeout
+	}
+
+	if domain := strings.Trim(strings.ToLower(cfg.Domain), "."); domain != "" {
+		f.zone = "vpn." + domain + "."
+	}
+
+	upstreams := cfg.Upstreams
+	if len(upstreams) == 0 {
+		upstreams = []string{defaultDNSUpstream}
+	}
+	for _, upstream := range upstreams {
+		if net.ParseIP(upstream) != nil {
+			upstream = net.JoinHostPort(upstream, defaultDNSPort)
+		}
+		if _, _, err := net.SplitHostPort(upstream); err != nil {
+			return nil, errors.Wrapf(err, "Method: newDNSForwarder")
+		}
+		f.upstreams = append(f.upstreams, upstream)
+	}
+	return f, nil
+}
+
+// listen starts to serve dns queries on ips.
+func (f *dnsForwarder) listen(ips ...net.IP) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, ip := range ips {
+		if ip == nil {
+			continue
+		}
+		conn, err := net.ListenPacket("udp", net.JoinHostPort(ip.String(), f.port))
+		if err != nil {
+			return errors.Wrapf(err, "Method: listen")
+		}
+		f.conns = append(f.conns, conn)
+		defaultLogger.Info(color.GreenString("[DNS] listen %s", conn.LocalAddr().String()))
+		go f.serve(conn)
+	}
+	return nil
+}
+
+// close stops to serve dns queries.
+func (f *dnsForwarder) close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for _, conn := range f.conns {
+		conn.Close()
+	}
+	f.conns = nil
+	return nil
+}
+
+// serve reads dns queries from conn until conn is closed.
+func (f *dnsForwarder) serve(conn net.PacketConn) {
+	for {
+		buf := make([]byte, maxDNSPacketSize)
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		go func(query []byte, addr net.Addr) {
+			response, err := f.handle(query)
+			if err != nil {
+				defaultLogger.Error(color.RedString("[ERR][DNS] %s %s", addr.String(), err.Error()))
+				return
+			}
+			conn.WriteTo(response, addr)
+		}(buf[:n], addr)
+	}
+}
+
+// handle answers a query in zone for clients, otherwise it forwards a query to upstream.
+func (f *dnsForwarder) handle(query []byte) ([]byte, error) {
+	var parser dnsmessage.Parser
+	header, err := parser.Start(query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: handle")
+	}
+	question, err := parser.Question()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: handle")
+	}
+
+	name := strings.ToLower(question.Name.String())
+	if f.zone != "" && strings.HasSuffix(name, "."+f.zone) {
+		return f.answer(header, question, strings.TrimSuffix(name, "."+f.zone))
+	}
+	return f.forward(query)
+}
+
+// answer makes a response for <host>.vpn.<domain> from connected clients.
+func (f *dnsForwarder) answer(header dnsmessage.Header, question dnsmessage.Question, host string) ([]byte, error) {
+	header.Response = true
+	header.Authoritative = true
+	header.RecursionAvailable = true
+
+	all := f.vpn.lookupClientIPs(host)
+	if len(all) == 0 {
+		header.RCode = dnsmessage.RCodeNameError
+	}
+
+	var ips []net.IP
+	for _, ip := range all {
+		switch {
+		case question.Type == dnsmessage.TypeA && ip.To4() != nil:
+			ips = append(ips, ip)
+		case question.Type == dnsmessage.TypeAAAA && ip.To4() == nil:
+			ips = append(ips, ip)
+		}
+	}
+
+	builder := dnsmessage.NewBuilder(make([]byte, 0, 512), header)
+	builder.EnableCompression()
+	if err := builder.StartQuestions(); err != nil {
+		return nil, errors.Wrapf(err, "Method: answer")
+	}
+	if err := builder.Question(question); err != nil {
+		return nil, errors.Wrapf(err, "Method: answer")
+	}
+	if err := builder.StartAnswers(); err != nil {
+		return nil, errors.Wrapf(err, "Method: answer")
+	}
+
+	for _, ip := range ips {
+		rh := dnsmessage.ResourceHeader{Name: question.Name, Class: dnsmessage.ClassINET, TTL: defaultDNSTTL}
+		if ip4 := ip.To4(); ip4 != nil {
+			var a [4]byte
+			copy(a[:], ip4)
+			if err := builder.AResource(rh, dnsmessage.AResource{A: a}); err != nil {
+				return nil, errors.Wrapf(err, "Method: answer")
+			}
+		} else {
+			var aaaa [16]byte
+			copy(aaaa[:], ip.To16())
+			if err := builder.AAAAResource(rh, dnsmessage.AAAAResource{AAAA: aaaa}); err != nil {
+				return nil, errors.Wrapf(err, "Method: answer")
+			}
+		}
+	}
+	return builder.Finish()
+}
+
+// forward sends a query to upstreams in order, and returns the first response.
+func (f *dnsForwarder) forward(query []byte) ([]byte, error) {
+	var lastErr error
+	for _, upstream := range f.upstreams {
+		response, err := f.exchange(upstream, query)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return response, nil
+	}
+	return nil, errors.Wrapf(lastErr, "Method: forward")
+}
+
+// exchange sends a query to upstream, and receives a response.
+func (f *dnsForwarder) exchange(upstream string, query []byte) ([]byte, error) {
+	conn, err := net.DialTimeout("udp", upstream, f.timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(f.timeout))
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, maxDNSPacketSize)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// lookupClientIPs returns vpn ips of connected clients whose host name is host.
+// if different users have the same host name, it returns nothing, because the name doesn't belong to one user.
+func (v *vpn) lookupClientIPs(host string) []net.IP {
+	v.clientsLock.RLock()
+	defer v.clientsLock.RUnlock()
+
+	var ips []net.IP
+	var user string
+	for key, c := range v.clients {
+		if dnsHostName(c.user) != host {
+			continue
+		}
+		if user != "" && user != c.user {
+			return nil
+		}
+		user = c.user
+
+		// clients map has an entry per vpn ip.
+		if ip := net.ParseIP(key); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
+// dnsHostName converts whole user(such as email, aws arn) to a dns label.
+// ex) allan@example.com -> allan-example-com, developer/allan -> developer-allan
+func dnsHostName(user string) string {
+	label := []byte(strings.ToLower(user))
+	for i, c := range label {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			label[i] = '-'
+		}
+	}
+	host := strings.Trim(string(label), "-")
+	if len(host) > maxDNSLabelSize {
+		host = strings.Trim(host[:maxDNSLabelSize], "-")
+	}
+	return host
+}
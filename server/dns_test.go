@@ -0,0 +1,163 @@
+package server
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func testDNSQuery(name string, qtype dnsmessage.Type) []byte {
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: 1, RecursionDesired: true})
+	builder.StartQuestions()
+	builder.Question(dnsmessage.Question{Name: dnsmessage.MustNewName(name), Type: qtype, Class: dnsmessage.ClassINET})
+	query, _ := builder.Finish()
+	return query
+}
+
+func TestNewDNSForwarder(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		cfg       *DNSServerConfig
+		zone      string
+		upstreams []string
+		isErr     bool
+	}{
+		"empty":            {isErr: true},
+		"default":          {cfg: &DNSServerConfig{}, upstreams: []string{"8.8.8.8:53"}},
+		"invalid-upstream": {cfg: &DNSServerConfig{Upstreams: []string{"allan"}}, isErr: true},
+		"success": {cfg: &DNSServerConfig{Domain: "Corp.Example.com.", Upstreams: []string{"10.0.0.2", "10.0.0.3:5353", "fd00::2"}},
+			zone: "vpn.corp.example.com.", upstreams: []string{"10.0.0.2:53", "10.0.0.3:5353", "[fd00::2]:53"}},
+	}
+
+	for _, t := range tests {
+		f, err := newDNSForwarder(&vpn{}, t.cfg)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Equal(t.zone, f.zone)
+			assert.Equal(t.upstreams, f.upstreams)
+			assert.Equal(defaultDNSPort, f.port)
+		}
+	}
+}
+
+func TestDNSForwarder_handle(t *testing.T) {
+	assert := assert.New(t)
+
+	// fake upstream
+	upstream, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(err)
+	defer upstream.Close()
+	go func() {
+		buf := make([]byte, maxDNSPacketSize)
+		for {
+			n, addr, err := upstream.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var parser dnsmessage.Parser
+			header, _ := parser.Start(buf[:n])
+			question, _ := parser.Question()
+			header.Response = true
+			builder := dnsmessage.NewBuilder(nil, header)
+			builder.StartQuestions()
+			builder.Question(question)
+			builder.StartAnswers()
+			builder.AResource(dnsmessage.ResourceHeader{Name: question.Name, Class: dnsmessage.ClassINET},
+				dnsmessage.AResource{A: [4]byte{1, 2, 3, 4}})
+			response, _ := builder.Finish()
+			upstream.WriteTo(response, addr)
+		}
+	}()
+
+	v := &vpn{clients: map[string]*client{}}
+	c := &client{user: "allan@example.com", vpnIP: net.ParseIP("10.10.10.2").To4(), vpnIP6: net.ParseIP("fd00::2")}
+	for _, ip := range c.vpnIPs() {
+		v.clients[ip.String()] = c
+	}
+
+	f, err := newDNSForwarder(v, &DNSServerConfig{Domain: "example.com", Upstreams: []string{upstream.LocalAddr().String()}})
+	assert.NoError(err)
+
+	tests := map[string]struct {
+		query   []byte
+		rcode   dnsmessage.RCode
+		answers []dnsmessage.ResourceBody
+	}{
+		"a":        {query: testDNSQuery("allan-example-com.vpn.example.com.", dnsmessage.TypeA), answers: []dnsmessage.ResourceBody{&dnsmessage.AResource{A: [4]byte{10, 10, 10, 2}}}},
+		"aaaa":     {query: testDNSQuery("Allan-Example-Com.vpn.example.com.", dnsmessage.TypeAAAA), answers: []dnsmessage.ResourceBody{&dnsmessage.AAAAResource{AAAA: [16]byte{0xfd, 15: 2}}}},
+		"local":    {query: testDNSQuery("allan.vpn.example.com.", dnsmessage.TypeA), rcode: dnsmessage.RCodeNameError},
+		"nxdomain": {query: testDNSQuery("bob.vpn.example.com.", dnsmessage.TypeA), rcode: dnsmessage.RCodeNameError},
+		"forward":  {query: testDNSQuery("www.example.com.", dnsmessage.TypeA), answers: []dnsmessage.ResourceBody{&dnsmessage.AResource{A: [4]byte{1, 2, 3, 4}}}},
+	}
+
+	for _, t := range tests {
+		response, err := f.handle(t.query)
+		assert.NoError(err)
+
+		var msg dnsmessage.Message
+		assert.NoError(msg.Unpack(response))
+		assert.Equal(uint16(1), msg.Header.ID)
+		assert.Equal(t.rcode, msg.Header.RCode)
+		assert.Len(msg.Answers, len(t.answers))
+		for i, answer := range msg.Answers {
+			assert.Equal(t.answers[i], answer.Body)
+		}
+	}
+}
+
+func TestVpn_LookupClientIPs(t *testing.T) {
+	assert := assert.New(t)
+
+	v := &vpn{clients: map[string]*client{}}
+	for _, c := range []*client{
+		{user: "allan@example.com", vpnIP: net.ParseIP("10.10.10.2").To4(), vpnIP6: net.ParseIP("fd00::2")},
+		{user: "bob.kim@example.com", vpnIP: net.ParseIP("10.10.10.3").To4()},
+		{user: "bob-kim@example.com", vpnIP: net.ParseIP("10.10.10.4").To4()},
+	} {
+		for _, ip := range c.vpnIPs() {
+			v.clients[ip.String()] = c
+		}
+	}
+
+	tests := map[string]struct {
+		host   string
+		output []string
+	}{
+		"dual-stack": {host: "allan-example-com", output: []string{"10.10.10.2", "fd00::2"}},
+		"collision":  {host: "bob-kim-example-com"},
+		"local-part": {host: "allan"},
+	}
+
+	for _, t := range tests {
+		var ips []string
+		for _, ip := range v.lookupClientIPs(t.host) {
+			ips = append(ips, ip.String())
+		}
+		sort.Strings(ips)
+		assert.Equal(t.output, ips)
+	}
+}
+
+func TestDnsHostName(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input  string
+		output string
+	}{
+		"email":        {input: "Allan.Kim@example.com", output: "allan-kim-example-com"},
+		"arn":          {input: "arn:aws:iam::123456789012:user/allan", output: "arn-aws-iam--123456789012-user-allan"},
+		"assumed-role": {input: "developer/allan", output: "developer-allan"},
+		"name":         {input: "allan_", output: "allan"},
+		"long":         {input: strings.Repeat("a", 70), output: strings.Repeat("a", maxDNSLabelSize)},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.output, dnsHostName(t.input))
+	}
+}
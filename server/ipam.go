@@ -0,0 +1,210 @@
+package server
+
+import (
+	"bytes"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/pkg/errors"
+)
+
+// IPAM is an interface for managing vpn ip addresses of users.
+type IPAM interface {
+	// Allocate returns vpn ip for user in subnet. gateway is never allocated.
+	Allocate(user string, gateway net.IP, subnet *net.IPNet) (net.IP, error)
+
+	// Release returns vpn ip of user when user is disconnected.
+	Release(user string, ip net.IP) error
+
+	// Lookup returns vpn ips which are leased to user.
+	Lookup(user string) []net.IP
+
+	// Reserve pins ip to user. reserved ip is never allocated to other users.
+	Reserve(user string, ip net.IP) error
+
+	// Leases returns leases which aren't expired.
+	Leases() []Lease
+}
+
+// Lease is a vpn ip leased to user.
+type Lease struct {
+	User      string    // user(jwt audience)
+	IP        net.IP    // leased ip
+	Active    bool      // whether ip is used or not
+	Reserved  bool      // whether ip is reserved to user or not
+	ExpiredAt time.Time // expired time after released
+}
+
+type lease struct {
+	user      string    // user(jwt audience)
+	ip        net.IP    // leased ip
+	active    bool      // whether ip is used or not
+	reserved  bool      // whether ip is reserved to user or not(never expired)
+	expiredAt time.Time // expired time after released
+}
+
+// isAvailable checks whether lease can be reused by user at now.
+func (l *lease) isAvailable(user string, now time.Time) bool {
+	if l.user != user || l.active {
+		return false
+	}
+	return l.reserved || !l.expiredAt.Before(now)
+}
+
+// isExpired checks whether lease can be issued to other user at now.
+func (l *lease) isExpired(now time.Time) bool {
+	return !l.active && !l.reserved && l.expiredAt.Before(now)
+}
+
+type memoryIPAM struct {
+	ttl    time.Duration     // lease ttl after released
+	leases map[string]*lease // leases(map[vpn-ip]*lease)
+	lock   sync.Mutex        // leases lock
+}
+
+// Allocate returns vpn ip for user.
+// if user has a reserved ip or a lease which isn't expired, it returns the same ip(sticky).
+func (m *memoryIPAM) Allocate(user string, gateway net.IP, subnet *net.IPNet) (net.IP, error) {
+	if gateway == nil || subnet == nil {
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: Allocate")
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := time.Now()
+
+	// reuse a reserved ip or a released lease of user.
+	var candidate *lease
+	for _, l := range m.leases {
+		if !subnet.Contains(l.ip) || !l.isAvailable(user, now) {
+			continue
+		}
+		if candidate == nil || (l.reserved && !candidate.reserved) {
+			candidate = l
+		}
+	}
+	if candidate != nil {
+		candidate.active = true
+		return candidate.ip, nil
+	}
+
+	// issue new ip.
+	for ip := gateway.Mask(subnet.Mask); subnet.Contains(ip); internal.IncreaseIP(ip) {
+		// continue when such as below conditions.
+		if ip.Equal(gateway) || internal.IsReservedIP(ip, subnet) {
+			continue
+		}
+
+		// continue if other user is using or other lease isn't expired.
+		if l, ok := m.leases[ip.String()]; ok && !l.isExpired(now) {
+			continue
+		}
+
+		leased := make(net.IP, len(ip))
+		copy(leased, ip)
+		m.leases[leased.String()] = &lease{user: user, ip: leased, active: true}
+		return leased, nil
+	}
+
+	return nil, errors.Wrapf(internal.ErrorExceedClientPool, "Method: Allocate")
+}
+
+// Release releases vpn ip of user, and the lease is kept until ttl.
+func (m *memoryIPAM) Release(user string, ip net.IP) error {
+	if ip == nil {
+		return errors.Wrapf(internal.ErrorInvalidParams, "Method: Release")
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	l, ok := m.leases[ip.String()]
+	if !ok || l.user != user {
+		return errors.Wrapf(internal.ErrorInvalidParams, "Method: Release")
+	}
+
+	if l.reserved {
+		l.active = false
+		return nil
+	}
+
+	if m.ttl <= 0 {
+		delete(m.leases, ip.String())
+		return nil
+	}
+	l.active = false
+	l.expiredAt = time.Now().Add(m.ttl)
+	return nil
+}
+
+// Lookup returns vpn ips which are leased to user.
+func (m *memoryIPAM) Lookup(user string) []net.IP {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := time.Now()
+	var ips []net.IP
+	for _, l := range m.leases {
+		if l.user != user {
+			continue
+		}
+		if l.isExpired(now) {
+			continue
+		}
+		ips = append(ips, l.ip)
+	}
+	return ips
+}
+
+// Reserve pins ip to user.
+func (m *memoryIPAM) Reserve(user string, ip net.IP) error {
+	if user == "" || ip == nil {
+		return errors.Wrapf(internal.ErrorInvalidParams, "Method: Reserve")
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if l, ok := m.leases[ip.String()]; ok && l.user != user && !l.isExpired(time.Now()) {
+		return errors.Wrapf(internal.ErrorInvalidReservation, "Method: Reserve %s", ip.String())
+	}
+
+	m.leases[ip.String()] = &lease{user: user, ip: ip, reserved: true}
+	return nil
+}
+
+// Leases returns leases which aren't expired ordered by ip.
+func (m *memoryIPAM) Leases() []Lease {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	now := time.Now()
+	var leases []Lease
+	for _, l := range m.leases {
+		if l.isExpired(now) {
+			continue
+		}
+		leases = append(leases, Lease{
+			User:      l.user,
+			IP:        l.ip,
+			Active:    l.active,
+			Reserved:  l.reserved,
+			ExpiredAt: l.expiredAt,
+		})
+	}
+
+	sort.Slice(leases, func(i, j int) bool {
+		return bytes.Compare(leases[i].IP.To16(), leases[j].IP.To16()) < 0
+	})
+	return leases
+}
+
+// NewMemoryIPAM returns IPAM which keeps leases on memory.
+// a released lease is kept for ttl, so user receives the same ip when reconnecting.
+func NewMemoryIPAM(ttl time.Duration) IPAM {
+	return &memoryIPAM{
+		ttl:    ttl,
+		leases: map[string]*lease{},
+	}
+}
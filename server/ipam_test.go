@@ -0,0 +1,216 @@
+package server
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMemoryIPAM(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		ttl time.Duration
+	}{
+		"success": {ttl: time.Hour},
+	}
+
+	for _, t := range tests {
+		ipam := NewMemoryIPAM(t.ttl)
+		assert.Equal(t.ttl, ipam.(*memoryIPAM).ttl)
+	}
+}
+
+func TestMemoryIPAM_Allocate(t *testing.T) {
+	assert := assert.New(t)
+
+	gateway := net.ParseIP("10.10.10.1").To4()
+	_, subnet, _ := net.ParseCIDR("10.10.10.0/29")
+
+	tests := map[string]struct {
+		ttl     time.Duration
+		users   []string
+		release bool
+		reuse   string
+		output  net.IP
+		isErr   bool
+	}{
+		"fail": {ttl: time.Hour, users: []string{"a", "b", "c", "d", "e"}, reuse: "f", isErr: true},
+		"new": {
+			ttl:    time.Hour,
+			users:  []string{"a", "b"},
+			reuse:  "c",
+			output: net.ParseIP("10.10.10.4"),
+		},
+		"same-user-connected": {
+			ttl:    time.Hour,
+			users:  []string{"a"},
+			reuse:  "a",
+			output: net.ParseIP("10.10.10.3"),
+		},
+		"sticky": {
+			ttl:     time.Hour,
+			users:   []string{"a", "b"},
+			release: true,
+			reuse:   "b",
+			output:  net.ParseIP("10.10.10.3"),
+		},
+		"sticky-other-user": {
+			ttl:     time.Hour,
+			users:   []string{"a", "b"},
+			release: true,
+			reuse:   "c",
+			output:  net.ParseIP("10.10.10.4"),
+		},
+		"expired": {
+			ttl:     -1,
+			users:   []string{"a", "b"},
+			release: true,
+			reuse:   "c",
+			output:  net.ParseIP("10.10.10.2"),
+		},
+	}
+
+	for _, t := range tests {
+		ipam := NewMemoryIPAM(t.ttl)
+		for _, user := range t.users {
+			ip, err := ipam.Allocate(user, gateway, subnet)
+			assert.NoError(err)
+			if t.release {
+				assert.NoError(ipam.Release(user, ip))
+			}
+		}
+
+		ip, err := ipam.Allocate(t.reuse, gateway, subnet)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.True(t.output.Equal(ip))
+		}
+	}
+}
+
+func TestMemoryIPAM_Release(t *testing.T) {
+	assert := assert.New(t)
+
+	gateway := net.ParseIP("fd00:10::1")
+	_, subnet, _ := net.ParseCIDR("fd00:10::/64")
+
+	tests := map[string]struct {
+		user  string
+		ip    net.IP
+		isErr bool
+	}{
+		"empty":      {user: "a", isErr: true},
+		"other-user": {user: "b", ip: net.ParseIP("fd00:10::2"), isErr: true},
+		"success":    {user: "a", ip: net.ParseIP("fd00:10::2")},
+	}
+
+	for _, t := range tests {
+		ipam := NewMemoryIPAM(time.Hour)
+		_, err := ipam.Allocate("a", gateway, subnet)
+		assert.NoError(err)
+		err = ipam.Release(t.user, t.ip)
+		assert.Equal(t.isErr, err != nil)
+	}
+}
+
+func TestMemoryIPAM_Lookup(t *testing.T) {
+	assert := assert.New(t)
+
+	gateway := net.ParseIP("10.10.10.1").To4()
+	_, subnet, _ := net.ParseCIDR("10.10.10.0/24")
+
+	tests := map[string]struct {
+		ttl     time.Duration
+		release bool
+		count   int
+	}{
+		"active":   {ttl: time.Hour, count: 1},
+		"released": {ttl: time.Hour, release: true, count: 1},
+		"expired":  {ttl: -1, release: true, count: 0},
+	}
+
+	for _, t := range tests {
+		ipam := NewMemoryIPAM(t.ttl)
+		ip, err := ipam.Allocate("a", gateway, subnet)
+		assert.NoError(err)
+		if t.release {
+			assert.NoError(ipam.Release("a", ip))
+		}
+		assert.Len(ipam.Lookup("a"), t.count)
+		assert.Len(ipam.Lookup("b"), 0)
+	}
+}
+
+func TestMemoryIPAM_Reserve(t *testing.T) {
+	assert := assert.New(t)
+
+	gateway := net.ParseIP("10.10.10.1").To4()
+	_, subnet, _ := net.ParseCIDR("10.10.10.0/30")
+
+	tests := map[string]struct {
+		user  string
+		ip    net.IP
+		isErr bool
+	}{
+		"empty":   {isErr: true},
+		"used":    {user: "b", ip: net.ParseIP("10.10.10.2"), isErr: true},
+		"success": {user: "a", ip: net.ParseIP("10.10.10.2")},
+	}
+
+	for _, t := range tests {
+		ipam := NewMemoryIPAM(time.Hour)
+		ip, err := ipam.Allocate("a", gateway, subnet)
+		assert.NoError(err)
+		assert.NoError(ipam.Release("a", ip))
+
+		err = ipam.Reserve(t.user, t.ip)
+		assert.Equal(t.isErr, err != nil)
+		if err != nil {
+			continue
+		}
+
+		// reserved ip is never allocated to other users.
+		_, err = ipam.Allocate("b", gateway, subnet)
+		assert.Error(err)
+
+		// reserved ip is kept after released.
+		ip, err = ipam.Allocate(t.user, gateway, subnet)
+		assert.NoError(err)
+		assert.True(t.ip.Equal(ip))
+		assert.NoError(ipam.Release(t.user, ip))
+		assert.Len(ipam.Lookup(t.user), 1)
+	}
+}
+
+func TestMemoryIPAM_Leases(t *testing.T) {
+	assert := assert.New(t)
+
+	gateway := net.ParseIP("10.10.10.1").To4()
+	_, subnet, _ := net.ParseCIDR("10.10.10.0/24")
+
+	ipam := NewMemoryIPAM(time.Hour)
+	assert.NoError(ipam.Reserve("c", net.ParseIP("10.10.10.100").To4()))
+	a, err := ipam.Allocate("a", gateway, subnet)
+	assert.NoError(err)
+	b, err := ipam.Allocate("b", gateway, subnet)
+	assert.NoError(err)
+	assert.NoError(ipam.Release("b", b))
+
+	leases := ipam.Leases()
+	assert.Len(leases, 3)
+	assert.Equal(Lease{User: "a", IP: a, Active: true}, leases[0])
+	assert.Equal("b", leases[1].User)
+	assert.False(leases[1].Active)
+	assert.False(leases[1].ExpiredAt.IsZero())
+	assert.Equal(Lease{User: "c", IP: net.ParseIP("10.10.10.100").To4(), Reserved: true}, leases[2])
+
+	// expired lease is excluded.
+	ipam = NewMemoryIPAM(-1)
+	b, err = ipam.Allocate("b", gateway, subnet)
+	assert.NoError(err)
+	assert.NoError(ipam.Release("b", b))
+	assert.Len(ipam.Leases(), 0)
+}
@@ -0,0 +1,282 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/pkg/errors"
+)
+
+const (
+	mfaChallengeExpiration = 5 * time.Minute
+	mfaMaxAttempts         = 5
+	mfaChallengeIdSize     = 16
+)
+
+// MFAStore is an interface for storing totp secrets of users.
+type MFAStore interface {
+	// Secret returns totp secret of user, ok is false if user isn't enrolled.
+	Secret(user string) (secret string, ok bool)
+
+	// Enroll generates new totp secret of user, and returns it.
+	Enroll(user string) (secret string, err error)
+
+	// Unenroll removes totp secret of user.
+	Unenroll(user string) error
+}
+
+type mfaSecrets struct {
+	Users map[string]string `json:"users"` // totp secrets(map[user]secret)
+}
+
+type memoryMFAStore struct {
+	secrets mfaSecrets   // secrets
+	lock    sync.RWMutex // secrets lock
+}
+
+// Secret returns totp secret of user.
+func (m *memoryMFAStore) Secret(user string) (string, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	secret, ok := m.secrets.Users[user]
+	return secret, ok
+}
+
+// Enroll generates new totp secret of user.
+func (m *memoryMFAStore) Enroll(user string) (string, error) {
+	if user == "" {
+		return "", errors.Wrapf(internal.ErrorInvalidParams, "Method: Enroll")
+	}
+	secret, err := internal.GenerateTOTPSecret()
+	if err != nil {
+		return "", errors.Wrapf(err, "Method: Enroll")
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.secrets.Users[user] = secret
+	return secret, nil
+}
+
+// Unenroll removes totp secret of user.
+func (m *memoryMFAStore) Unenroll(user string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if _, ok := m.secrets.Users[user]; !ok {
+		return errors.Wrapf(internal.ErrorInvalidParams, "Method: Unenroll")
+	}
+	delete(m.secrets.Users, user)
+	return nil
+}
+
+type fileMFAStore struct {
+	*memoryMFAStore
+	path    string    // file path
+	modTime time.Time // modified time of loaded file
+}
+
+// Secret returns totp secret of user, file is reloaded if it's modified(e.g. by enrollment command).
+func (f *fileMFAStore) Secret(user string) (string, bool) {
+	if err := f.reload(); err != nil {
+		defaultLogger.Error(errors.Wrapf(err, "Method: Secret").Error())
+	}
+	return f.memoryMFAStore.Secret(user)
+}
+
+// Enroll generates new totp secret of user, and writes secrets to file.
+func (f *fileMFAStore) Enroll(user string) (string, error) {
+	secret, err := f.memoryMFAStore.Enroll(user)
+	if err != nil {
+		return "", errors.Wrapf(err, "Method: Enroll")
+	}
+	if err := f.save(); err != nil {
+		return "", errors.Wrapf(err, "Method: Enroll")
+	}
+	return secret, nil
+}
+
+// Unenroll removes totp secret of user, and writes secrets to file.
+func (f *fileMFAStore) Unenroll(user string) error {
+	if err := f.memoryMFAStore.Unenroll(user); err != nil {
+		return errors.Wrapf(err, "Method: Unenroll")
+	}
+	if err := f.save(); err != nil {
+		return errors.Wrapf(err, "Method: Unenroll")
+	}
+	return nil
+}
+
+// reload reads secrets from file if it's modified after loaded.
+func (f *fileMFAStore) reload() error {
+	info, err := os.Stat(f.path)
+	switch {
+	case os.IsNotExist(err):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	f.lock.RLock()
+	modified := !info.ModTime().Equal(f.modTime)
+	f.lock.RUnlock()
+	if !modified {
+		return nil
+	}
+
+	buf, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+	secrets := mfaSecrets{}
+	if err := json.Unmarshal(buf, &secrets); err != nil {
+		return err
+	}
+	if secrets.Users == nil {
+		secrets.Users = map[string]string{}
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.secrets = secrets
+	f.modTime = info.ModTime()
+	return nil
+}
+
+// save writes secrets to file.
+func (f *fileMFAStore) save() error {
+	f.lock.RLock()
+	buf, err := json.Marshal(f.secrets)
+	f.lock.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	// write to temporary file and rename it, so file isn't broken.
+	tmp := f.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, buf, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, f.path); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return err
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.modTime = info.ModTime()
+	return nil
+}
+
+// NewMemoryMFAStore returns MFAStore which keeps totp secrets on memory.
+func NewMemoryMFAStore() MFAStore {
+	return newMemoryMFAStore()
+}
+
+// NewFileMFAStore returns MFAStore which keeps totp secrets on memory and file.
+// secrets in file are loaded when it's created, and reloaded when file is modified.
+func NewFileMFAStore(path string) (MFAStore, error) {
+	if path == "" {
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: NewFileMFAStore")
+	}
+
+	store := &fileMFAStore{memoryMFAStore: newMemoryMFAStore(), path: path}
+	if err := store.reload(); err != nil {
+		return nil, errors.Wrapf(err, "Method: NewFileMFAStore")
+	}
+	return store, nil
+}
+
+func newMemoryMFAStore() *memoryMFAStore {
+	return &memoryMFAStore{secrets: mfaSecrets{Users: map[string]string{}}}
+}
+
+// mfaChallenge is a pending authentication which waits for second factor.
+type mfaChallenge struct {
+	identity            // identity authenticated by primary auth method
+	expiredAt time.Time // expired time
+	attempts  int       // failed attempts
+}
+
+// challengeMFA returns challenge id if user must pass second factor, and ok is false if user can't be authenticated.
+// challenge id is empty if second factor isn't needed.
+func (v *vpn) challengeMFA(id identity) (string, bool) {
+	if v.mfaStore == nil {
+		return "", true
+	}
+	if _, ok := v.mfaStore.Secret(id.user); !ok {
+		// users who aren't enrolled can't be authenticated if second factor is required.
+		return "", !v.mfaRequired
+	}
+
+	buf := make([]byte, mfaChallengeIdSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", false
+	}
+	challengeId := hex.EncodeToString(buf)
+
+	v.mfaLock.Lock()
+	defer v.mfaLock.Unlock()
+	now := time.Now()
+	for expiredId, challenge := range v.mfaChallenges {
+		if challenge.expiredAt.Before(now) {
+			delete(v.mfaChallenges, expiredId)
+		}
+	}
+	v.mfaChallenges[challengeId] = &mfaChallenge{identity: id, expiredAt: now.Add(mfaChallengeExpiration)}
+	return challengeId, true
+}
+
+// verifyMFA checks totp code for challenge, and returns challenge if it's passed.
+// challenge is removed after it's passed, expired or failed too many times, and totp code can't be reused.
+func (v *vpn) verifyMFA(req *protocol.AuthRequest_Mfa) (*mfaChallenge, bool) {
+	if v.mfaStore == nil || req == nil {
+		return nil, false
+	}
+
+	v.mfaLock.Lock()
+	challenge, ok := v.mfaChallenges[req.ChallengeId]
+	if ok && challenge.expiredAt.Before(time.Now()) {
+		delete(v.mfaChallenges, req.ChallengeId)
+		ok = false
+	}
+	v.mfaLock.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	// secret is looked up without lock, because mfa store may be slow.
+	secret, enrolled := v.mfaStore.Secret(challenge.user)
+
+	v.mfaLock.Lock()
+	defer v.mfaLock.Unlock()
+	// challenge might be passed or removed by other request while secret is looked up.
+	if v.mfaChallenges[req.ChallengeId] != challenge {
+		return nil, false
+	}
+	if !enrolled {
+		delete(v.mfaChallenges, req.ChallengeId)
+		return nil, false
+	}
+	counter, ok := internal.ValidateTOTP(secret, req.Code, time.Now())
+	if !ok || counter <= v.mfaUsedCounters[challenge.user] {
+		challenge.attempts++
+		if challenge.attempts >= mfaMaxAttempts {
+			delete(v.mfaChallenges, req.ChallengeId)
+		}
+		return nil, false
+	}
+
+	v.mfaUsedCounters[challenge.user] = counter
+	delete(v.mfaChallenges, req.ChallengeId)
+	return challenge, true
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gjbae1212/grpc-vpn/auth"
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryMFAStore(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewMemoryMFAStore()
+	_, err := store.Enroll("")
+	assert.Error(err)
+
+	secret, err := store.Enroll("allan")
+	assert.NoError(err)
+	stored, ok := store.Secret("allan")
+	assert.True(ok)
+	assert.Equal(secret, stored)
+	_, ok = store.Secret("bob")
+	assert.False(ok)
+
+	assert.NoError(store.Unenroll("allan"))
+	assert.Error(store.Unenroll("allan"))
+	_, ok = store.Secret("allan")
+	assert.False(ok)
+}
+
+func TestNewFileMFAStore(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "mfa")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	broken := filepath.Join(dir, "broken.json")
+	assert.NoError(ioutil.WriteFile(broken, []byte("{"), 0600))
+
+	tests := map[string]struct {
+		path  string
+		isErr bool
+	}{
+		"empty":     {isErr: true},
+		"broken":    {path: broken, isErr: true},
+		"not-exist": {path: filepath.Join(dir, "mfa.json")},
+	}
+
+	for _, t := range tests {
+		_, err := NewFileMFAStore(t.path)
+		assert.Equal(t.isErr, err != nil)
+	}
+}
+
+func TestFileMFAStore_Reload(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "mfa")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mfa.json")
+
+	// server keeps store, and enrollment command writes other store on same file.
+	serverStore, err := NewFileMFAStore(path)
+	assert.NoError(err)
+	commandStore, err := NewFileMFAStore(path)
+	assert.NoError(err)
+
+	secret, err := commandStore.Enroll("allan")
+	assert.NoError(err)
+	stored, ok := serverStore.Secret("allan")
+	assert.True(ok)
+	assert.Equal(secret, stored)
+
+	// secrets are kept after restarting.
+	reloaded, err := NewFileMFAStore(path)
+	assert.NoError(err)
+	stored, ok = reloaded.Secret("allan")
+	assert.True(ok)
+	assert.Equal(secret, stored)
+}
+
+func TestVpn_Auth_MFA(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewMemoryMFAStore()
+	secret, err := store.Enroll("allan")
+	assert.NoError(err)
+	code := func(offset int64) string {
+		c, _ := internal.TOTPCode(secret, internal.TOTPCounter(time.Now())+offset)
+		return c
+	}
+
+	// vpn is built in closure, because t is shadowed in test loop.
+	newVPN := func() *vpn {
+		return testVPN(t, nil)
+	}
+	auth := func(v *vpn, user string) *protocol.AuthResponse {
+		ctx := context.WithValue(context.Background(), ipCtxName, net.ParseIP("1.1.1.1"))
+		ctx = context.WithValue(ctx, auth.UserCtxName, user)
+		ctx = context.WithValue(ctx, auth.GroupsCtxName, []string{"dev"})
+		ctx = context.WithValue(ctx, auth.RolesCtxName, []string{"developer"})
+		resp, err := v.Auth(ctx, &protocol.AuthRequest{AuthType: protocol.AuthType_AT_TEST})
+		assert.NoError(err)
+		return resp
+	}
+	verify := func(v *vpn, challengeId, code string) *protocol.AuthResponse {
+		ctx := context.WithValue(context.Background(), ipCtxName, net.ParseIP("1.1.1.1"))
+		resp, err := v.Auth(ctx, &protocol.AuthRequest{AuthType: protocol.AuthType_AT_MFA,
+			Mfa: &protocol.AuthRequest_Mfa{ChallengeId: challengeId, Code: code}})
+		assert.NoError(err)
+		return resp
+	}
+
+	tests := map[string]struct {
+		store    MFAStore
+		required bool
+		user     string
+		output   protocol.ErrorCode
+	}{
+		"disabled":     {user: "allan", output: protocol.ErrorCode_EC_SUCCESS},
+		"not-enrolled": {store: store, user: "bob", output: protocol.ErrorCode_EC_SUCCESS},
+		"required":     {store: store, required: true, user: "bob", output: protocol.ErrorCode_EC_INVALID_AUTHORIZATION},
+		"enrolled":     {store: store, user: "allan", output: protocol.ErrorCode_EC_MFA_REQUIRED},
+	}
+
+	for _, t := range tests {
+		v := newVPN()
+		v.mfaStore = t.store
+		v.mfaRequired = t.required
+		resp := auth(v, t.user)
+		assert.Equal(t.output, resp.ErrorCode)
+		assert.Equal(t.output == protocol.ErrorCode_EC_SUCCESS, resp.Jwt != "")
+		assert.Equal(t.output == protocol.ErrorCode_EC_MFA_REQUIRED, resp.MfaChallengeId != "")
+	}
+
+	v := newVPN()
+	v.mfaStore = store
+
+	// wrong code and unknown challenge.
+	challengeId := auth(v, "allan").MfaChallengeId
+	assert.Equal(protocol.ErrorCode_EC_INVALID_AUTHORIZATION, verify(v, challengeId, "000000x").ErrorCode)
+	assert.Equal(protocol.ErrorCode_EC_INVALID_AUTHORIZATION, verify(v, "unknown", code(0)).ErrorCode)
+
+	// jwt is issued with claims of primary authentication, and challenge is used only once.
+	resp := verify(v, challengeId, code(0))
+	assert.Equal(protocol.ErrorCode_EC_SUCCESS, resp.ErrorCode)
+	token, err := v.DecodeJwt(resp.Jwt)
+	assert.NoError(err)
+	assert.Equal("allan", token.Claims.(*internal.VpnClaims).Audience)
+	assert.Equal([]string{"dev"}, token.Claims.(*internal.VpnClaims).Groups)
+	assert.Equal([]string{"developer"}, token.Claims.(*internal.VpnClaims).Roles)
+	assert.Equal(protocol.ErrorCode_EC_INVALID_AUTHORIZATION, verify(v, challengeId, code(0)).ErrorCode)
+
+	// used code can't be replayed.
+	challengeId = auth(v, "allan").MfaChallengeId
+	assert.Equal(protocol.ErrorCode_EC_INVALID_AUTHORIZATION, verify(v, challengeId, code(0)).ErrorCode)
+	assert.Equal(protocol.ErrorCode_EC_SUCCESS, verify(v, challengeId, code(1)).ErrorCode)
+
+	// challenge is removed after too many failures.
+	challengeId = auth(v, "allan").MfaChallengeId
+	for i := 0; i < mfaMaxAttempts; i++ {
+		assert.Equal(protocol.ErrorCode_EC_INVALID_AUTHORIZATION, verify(v, challengeId, "000000").ErrorCode)
+	}
+	v.mfaUsedCounters = map[string]int64{}
+	assert.Equal(protocol.ErrorCode_EC_INVALID_AUTHORIZATION, verify(v, challengeId, code(0)).ErrorCode)
+
+	// expired challenge.
+	challengeId = auth(v, "allan").MfaChallengeId
+	v.mfaChallenges[challengeId].expiredAt = time.Now().Add(-time.Second)
+	assert.Equal(protocol.ErrorCode_EC_INVALID_AUTHORIZATION, verify(v, challengeId, code(0)).ErrorCode)
+}
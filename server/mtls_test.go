@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/gjbae1212/grpc-vpn/internal/testcert"
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVpn_Auth_MTLS(t *testing.T) {
+	assert := assert.New(t)
+
+	ca, err := testcert.New(&x509.Certificate{Subject: pkix.Name{CommonName: "ca"}}, nil)
+	assert.NoError(err)
+	cert, err := testcert.New(&x509.Certificate{Subject: pkix.Name{CommonName: "allan"}}, ca)
+	assert.NoError(err)
+
+	tests := map[string]struct {
+		clientCA string
+		ctx      context.Context
+		authType protocol.AuthType
+		code     protocol.ErrorCode
+		isErr    bool
+	}{
+		"disabled":     {ctx: testcert.PeerContext(cert, ca), authType: protocol.AuthType_AT_MTLS, isErr: true},
+		"other-type":   {clientCA: "ca", ctx: testcert.PeerContext(cert, ca), authType: protocol.AuthType_AT_TEST, code: protocol.ErrorCode_EC_INVALID_AUTHORIZATION},
+		"without-cert": {clientCA: "ca", ctx: testcert.PeerContext(), authType: protocol.AuthType_AT_MTLS, isErr: true},
+		"success":      {clientCA: "ca", ctx: testcert.PeerContext(cert, ca), authType: protocol.AuthType_AT_MTLS, code: protocol.ErrorCode_EC_SUCCESS},
+	}
+
+	v := testVPN(t, nil)
+	for _, t := range tests {
+		interceptors, err := authUnaryServerInterceptors(&config{grpcTlsClientCA: t.clientCA})
+		if err != nil {
+			assert.True(t.isErr)
+			continue
+		}
+		ctx := context.WithValue(t.ctx, ipCtxName, net.ParseIP("1.1.1.1"))
+		resp, err := grpc_middleware.ChainUnaryServer(interceptors...)(ctx, &protocol.AuthRequest{AuthType: t.authType}, nil,
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return v.Auth(ctx, req.(*protocol.AuthRequest))
+			})
+		assert.Equal(t.isErr, err != nil)
+		if err != nil {
+			continue
+		}
+		assert.Equal(t.code, resp.(*protocol.AuthResponse).ErrorCode)
+		if resp.(*protocol.AuthResponse).ErrorCode == protocol.ErrorCode_EC_SUCCESS {
+			token, err := v.DecodeJwt(resp.(*protocol.AuthResponse).Jwt)
+			assert.NoError(err)
+			assert.Equal("allan", token.Claims.(*internal.VpnClaims).Audience)
+		}
+	}
+}
+
+func TestNewVpnServer_ClientCA(t *testing.T) {
+	assert := assert.New(t)
+
+	ca, err := testcert.New(&x509.Certificate{Subject: pkix.Name{CommonName: "ca"}}, nil)
+	assert.NoError(err)
+	cert, err := testcert.New(&x509.Certificate{DNSNames: []string{"localhost"}}, ca)
+	assert.NoError(err)
+	keyPEM, err := cert.KeyPEM()
+	assert.NoError(err)
+	caPEM, certPEM := ca.PEM, cert.PEM
+
+	tests := map[string]struct {
+		opts  []Option
+		isErr bool
+	}{
+		"without-tls": {opts: []Option{WithGrpcTlsClientCA(caPEM)}, isErr: true},
+		"invalid-ca":  {opts: []Option{WithGrpcTlsCertification(certPEM), WithGrpcTlsPem(keyPEM), WithGrpcTlsClientCA("invalid")}, isErr: true},
+		"success":     {opts: []Option{WithGrpcTlsCertification(certPEM), WithGrpcTlsPem(keyPEM), WithGrpcTlsClientCA(caPEM)}},
+	}
+
+	for _, t := range tests {
+		s, err := NewVpnServer(t.opts...)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			// mtls auth method is inserted instead of test auth method.
+			assert.Len(s.(*vpnServer).config.grpcUnaryInterceptors, 2)
+		}
+	}
+}
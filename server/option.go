@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/gjbae1212/grpc-vpn/auth"
+	"github.com/gjbae1212/grpc-vpn/internal"
 
 	"google.golang.org/grpc"
 )
@@ -14,16 +15,38 @@ type Option interface {
 }
 
 type config struct {
-	vpnSubNet              string
-	vpnJwtSalt             string
-	vpnJwtExpiration       time.Duration
-	grpcPort               string
-	grpcTlsCertification   string
-	grpcTlsPem             string
-	grpcUnaryInterceptors  []grpc.UnaryServerInterceptor
-	grpcStreamInterceptors []grpc.StreamServerInterceptor
-	grpcOptions            []grpc.ServerOption
-	grpcAuthMethods        []auth.ServerAuthMethod
+	vpnSubNet                 string
+	vpnSubNet6                string
+	vpnJwtSalt                string
+	vpnJwtKeySet              *internal.JWTKeySet
+	vpnRefreshTokenExpiration time.Duration
+	vpnSessionGracePeriod     time.Duration
+	vpnJwtExpiration          time.Duration
+	vpnRoutes                 []string
+	vpnFullTunnel             bool
+	vpnDNSServers             []string
+	vpnDNSSearchDomains       []string
+	dnsServer                 *DNSServerConfig
+	userGroups                map[string][]string
+	acl                       *ACLConfig
+	clientIsolation           ClientIsolation
+	adminToken                string
+	revocation                RevocationStore
+	mfaStore                  MFAStore
+	mfaRequired               bool
+	ipam                      IPAM
+	ipamLeaseTTL              time.Duration
+	ipReservations            map[string][]string
+	grpcPort                  string
+	grpcTlsCertification      string
+	grpcTlsPem                string
+	grpcTlsClientCA           string
+	grpcUnaryInterceptors     []grpc.UnaryServerInterceptor
+	grpcStreamInterceptors    []grpc.StreamServerInterceptor
+	grpcOptions               []grpc.ServerOption
+	grpcAuthMethods           []auth.ServerAuthMethod
+	authPolicy                *auth.Policy
+	authTestMode              bool
 }
 
 // OptionFunc is a function for Option interface.
@@ -38,6 +61,13 @@ func WithVpnSubNet(vpnSubNet string) OptionFunc {
 	}
 }
 
+// WithVpnSubNet6 returns OptionFunc for inserting VPN IPv6 SUBNET(dual stack).
+func WithVpnSubNet6(vpnSubNet6 string) OptionFunc {
+	return func(c *config) {
+		c.vpnSubNet6 = vpnSubNet6
+	}
+}
+
 // WithVpnJwtSalt returns OptionFunc for inserting VPN JWT SALT.
 func WithVpnJwtSalt(vpnJwtSalt string) OptionFunc {
 	return func(c *config) {
@@ -45,6 +75,14 @@ func WithVpnJwtSalt(vpnJwtSalt string) OptionFunc {
 	}
 }
 
+// WithVpnJwtKeySet returns OptionFunc for inserting keys signing VPN JWT asymmetrically.
+// if it exists, VPN JWT SALT isn't used.
+func WithVpnJwtKeySet(keySet *internal.JWTKeySet) OptionFunc {
+	return func(c *config) {
+		c.vpnJwtKeySet = keySet
+	}
+}
+
 // WithVpnJwtExpiration returns OptionFunc for inserting VPN expiration time.
 func WithVpnJwtExpiration(exp time.Duration) OptionFunc {
 	return func(c *config) {
@@ -52,6 +90,131 @@ func WithVpnJwtExpiration(exp time.Duration) OptionFunc {
 	}
 }
 
+// WithVpnRefreshTokenExpiration returns OptionFunc for inserting expiration time of refresh token renewing VPN JWT.
+// if it's 0, refresh token isn't issued.
+func WithVpnRefreshTokenExpiration(exp time.Duration) OptionFunc {
+	return func(c *config) {
+		c.vpnRefreshTokenExpiration = exp
+	}
+}
+
+// WithVpnSessionGracePeriod returns OptionFunc for inserting how long vpn ips of disconnected session are held.
+// clients can resume session with same vpn ips during grace period, and if it's 0, session isn't resumed.
+func WithVpnSessionGracePeriod(period time.Duration) OptionFunc {
+	return func(c *config) {
+		c.vpnSessionGracePeriod = period
+	}
+}
+
+// WithVpnRoutes returns OptionFunc for inserting routes(cidr) which are pushed to clients(split tunneling).
+func WithVpnRoutes(routes []string) OptionFunc {
+	return func(c *config) {
+		c.vpnRoutes = routes
+	}
+}
+
+// WithVpnFullTunnel returns OptionFunc for inserting whether all traffic of clients flows through VPN.
+// if routes aren't inserted, VPN always works as full tunnel.
+func WithVpnFullTunnel(fullTunnel bool) OptionFunc {
+	return func(c *config) {
+		c.vpnFullTunnel = fullTunnel
+	}
+}
+
+// WithVpnDNSServers returns OptionFunc for inserting dns servers which are pushed to clients.
+func WithVpnDNSServers(servers []string) OptionFunc {
+	return func(c *config) {
+		c.vpnDNSServers = servers
+	}
+}
+
+// WithVpnDNSSearchDomains returns OptionFunc for inserting dns search domains which are pushed to clients.
+func WithVpnDNSSearchDomains(domains []string) OptionFunc {
+	return func(c *config) {
+		c.vpnDNSSearchDomains = domains
+	}
+}
+
+// WithDNSServer returns OptionFunc for inserting dns forwarder config.
+// if it's inserted, dns forwarder listens on vpn gateway ip.
+func WithDNSServer(dnsServer *DNSServerConfig) OptionFunc {
+	return func(c *config) {
+		c.dnsServer = dnsServer
+	}
+}
+
+// WithUserGroups returns OptionFunc for inserting groups of users(map[group][]user).
+func WithUserGroups(groups map[string][]string) OptionFunc {
+	return func(c *config) {
+		c.userGroups = groups
+	}
+}
+
+// WithACL returns OptionFunc for inserting network acl which is evaluated per packet from clients.
+func WithACL(acl *ACLConfig) OptionFunc {
+	return func(c *config) {
+		c.acl = acl
+	}
+}
+
+// WithClientIsolation returns OptionFunc for inserting whether traffic between clients is allowed or not.
+func WithClientIsolation(isolation ClientIsolation) OptionFunc {
+	return func(c *config) {
+		c.clientIsolation = isolation
+	}
+}
+
+// WithAdminToken returns OptionFunc for inserting admin token.
+// if it's inserted, admin apis are served and they are protected by the token(Authorization: bearer token).
+func WithAdminToken(token string) OptionFunc {
+	return func(c *config) {
+		c.adminToken = token
+	}
+}
+
+// WithRevocationStore returns OptionFunc for inserting store of revoked jwt.
+func WithRevocationStore(store RevocationStore) OptionFunc {
+	return func(c *config) {
+		c.revocation = store
+	}
+}
+
+// WithMFAStore returns OptionFunc for inserting store of totp secrets.
+// if it's set, enrolled users must pass totp after primary authentication.
+func WithMFAStore(store MFAStore) OptionFunc {
+	return func(c *config) {
+		c.mfaStore = store
+	}
+}
+
+// WithMFARequired returns OptionFunc for inserting whether users who aren't enrolled are refused or not.
+func WithMFARequired(b bool) OptionFunc {
+	return func(c *config) {
+		c.mfaRequired = b
+	}
+}
+
+// WithIPAM returns OptionFunc for inserting IP address manager.
+func WithIPAM(ipam IPAM) OptionFunc {
+	return func(c *config) {
+		c.ipam = ipam
+	}
+}
+
+// WithIPAMLeaseTTL returns OptionFunc for inserting lease ttl in default IP address manager.
+func WithIPAMLeaseTTL(ttl time.Duration) OptionFunc {
+	return func(c *config) {
+		c.ipamLeaseTTL = ttl
+	}
+}
+
+// WithIPReservations returns OptionFunc for inserting static vpn ips per user(map[user][]vpn-ip).
+func WithIPReservations(reservations map[string][]string) OptionFunc {
+	return func(c *config) {
+		c.ipReservations = reservations
+	}
+}
+
 // WithGrpcPort returns OptionFunc for inserting GRPC PORT.
 func WithGrpcPort(port string) OptionFunc {
 	return func(c *config) {
@@ -73,13 +236,40 @@ func WithGrpcTlsPem(pem string) OptionFunc {
 	}
 }
 
+// WithGrpcTlsClientCA returns OptionFunc for inserting CA bundle(PEM) which verifies client certificates.
+// if it's set, clients can authenticate with client certificate(mutual tls).
+func WithGrpcTlsClientCA(ca string) OptionFunc {
+	return func(c *config) {
+		c.grpcTlsClientCA = ca
+	}
+}
+
 // WithAuthMethods returns OptionFunc for inserting GRPC authentication method.
+// methods are chained, and each of them authenticates its own auth type.
+//
+// Deprecated: use WithAuthPolicy, which decides enabled auth types and combinations of them explicitly.
 func WithAuthMethods(methods []auth.ServerAuthMethod) OptionFunc {
 	return func(c *config) {
 		c.grpcAuthMethods = methods
 	}
 }
 
+// WithAuthPolicy returns OptionFunc for inserting auth policy which authenticates users.
+func WithAuthPolicy(policy *auth.Policy) OptionFunc {
+	return func(c *config) {
+		c.authPolicy = policy
+	}
+}
+
+// WithAuthTestMode returns OptionFunc for inserting test mode.
+// in test mode, if any auth method isn't configured, test auth which accepts anyone is used.
+// otherwise vpn server can't be created without auth method.
+func WithAuthTestMode(b bool) OptionFunc {
+	return func(c *config) {
+		c.authTestMode = b
+	}
+}
+
 // WithGrpcUnaryInterceptors returns OptionFunc for inserting GRPC Unary Interceptors( such as auth(Google OpenId, AWS IAM) )
 func WithGrpcUnaryInterceptors(interceptors []grpc.UnaryServerInterceptor) OptionFunc {
 	return func(c *config) {
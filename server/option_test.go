@@ -2,11 +2,15 @@ package server
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/gjbae1212/grpc-vpn/auth"
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal"
 
 	"google.golang.org/grpc"
 
@@ -32,6 +36,25 @@ func TestWithVpnSubNet(t *testing.T) {
 	}
 }
 
+func TestWithVpnSubNet6(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input string
+	}{
+		"success": {
+			input: "fd00:10::1/64",
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithVpnSubNet6(t.input)
+		f(c)
+		assert.Equal(t.input, c.vpnSubNet6)
+	}
+}
+
 func TestWithJwtSalt(t *testing.T) {
 	assert := assert.New(t)
 
@@ -51,6 +74,32 @@ func TestWithJwtSalt(t *testing.T) {
 	}
 }
 
+func TestWithVpnJwtKeySet(t *testing.T) {
+	assert := assert.New(t)
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	keySet, err := internal.NewJWTKeySet("kid", []*internal.JWTKey{
+		{Id: "kid", Method: internal.SigningMethodEdDSA, PrivateKey: private, PublicKey: private.Public()},
+	})
+	assert.NoError(err)
+
+	tests := map[string]struct {
+		input *internal.JWTKeySet
+	}{
+		"success": {
+			input: keySet,
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithVpnJwtKeySet(t.input)
+		f(c)
+		assert.Equal(t.input, c.vpnJwtKeySet)
+	}
+}
+
 func TestWithVpnJwtExpiration(t *testing.T) {
 	assert := assert.New(t)
 
@@ -70,6 +119,329 @@ func TestWithVpnJwtExpiration(t *testing.T) {
 	}
 }
 
+func TestWithVpnRefreshTokenExpiration(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input time.Duration
+	}{
+		"success": {
+			input: 7 * 24 * time.Hour,
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithVpnRefreshTokenExpiration(t.input)
+		f(c)
+		assert.Equal(t.input, c.vpnRefreshTokenExpiration)
+	}
+}
+
+func TestWithVpnSessionGracePeriod(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input time.Duration
+	}{
+		"success": {
+			input: 2 * time.Minute,
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithVpnSessionGracePeriod(t.input)
+		f(c)
+		assert.Equal(t.input, c.vpnSessionGracePeriod)
+	}
+}
+
+func TestWithVpnRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input []string
+	}{
+		"success": {
+			input: []string{"172.16.0.0/16"},
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithVpnRoutes(t.input)
+		f(c)
+		assert.Equal(t.input, c.vpnRoutes)
+	}
+}
+
+func TestWithVpnFullTunnel(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input bool
+	}{
+		"success": {
+			input: true,
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithVpnFullTunnel(t.input)
+		f(c)
+		assert.Equal(t.input, c.vpnFullTunnel)
+	}
+}
+
+func TestWithVpnDNSServers(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input []string
+	}{
+		"success": {
+			input: []string{"10.10.10.1", "8.8.8.8"},
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithVpnDNSServers(t.input)
+		f(c)
+		assert.Equal(t.input, c.vpnDNSServers)
+	}
+}
+
+func TestWithVpnDNSSearchDomains(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input []string
+	}{
+		"success": {
+			input: []string{"corp.example.com"},
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithVpnDNSSearchDomains(t.input)
+		f(c)
+		assert.Equal(t.input, c.vpnDNSSearchDomains)
+	}
+}
+
+func TestWithDNSServer(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input *DNSServerConfig
+	}{
+		"success": {
+			input: &DNSServerConfig{Domain: "example.com", Upstreams: []string{"10.0.0.2"}},
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithDNSServer(t.input)
+		f(c)
+		assert.Equal(t.input, c.dnsServer)
+	}
+}
+
+func TestWithUserGroups(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input map[string][]string
+	}{
+		"success": {
+			input: map[string][]string{"dev": {"allan@example.com"}},
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithUserGroups(t.input)
+		f(c)
+		assert.Equal(t.input, c.userGroups)
+	}
+}
+
+func TestWithACL(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input *ACLConfig
+	}{
+		"success": {
+			input: &ACLConfig{Rules: []*ACLRule{{Groups: []string{"dev"}, Destinations: []string{"10.0.0.0/8"}}}},
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithACL(t.input)
+		f(c)
+		assert.Equal(t.input, c.acl)
+	}
+}
+
+func TestWithClientIsolation(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input ClientIsolation
+	}{
+		"success": {
+			input: ClientIsolationSameGroup,
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithClientIsolation(t.input)
+		f(c)
+		assert.Equal(t.input, c.clientIsolation)
+	}
+}
+
+func TestWithAdminToken(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input string
+	}{
+		"success": {
+			input: "admin-token",
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithAdminToken(t.input)
+		f(c)
+		assert.Equal(t.input, c.adminToken)
+	}
+}
+
+func TestWithRevocationStore(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input RevocationStore
+	}{
+		"success": {
+			input: NewMemoryRevocationStore(),
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithRevocationStore(t.input)
+		f(c)
+		assert.Equal(t.input, c.revocation)
+	}
+}
+
+func TestWithMFAStore(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input MFAStore
+	}{
+		"success": {
+			input: NewMemoryMFAStore(),
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithMFAStore(t.input)
+		f(c)
+		assert.Equal(t.input, c.mfaStore)
+	}
+}
+
+func TestWithMFARequired(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input bool
+	}{
+		"success": {
+			input: true,
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithMFARequired(t.input)
+		f(c)
+		assert.Equal(t.input, c.mfaRequired)
+	}
+}
+
+func TestWithIPAM(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input IPAM
+	}{
+		"success": {
+			input: NewMemoryIPAM(time.Hour),
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithIPAM(t.input)
+		f(c)
+		assert.Equal(t.input, c.ipam)
+	}
+}
+
+func TestWithIPAMLeaseTTL(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input time.Duration
+	}{
+		"success": {
+			input: time.Hour,
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithIPAMLeaseTTL(t.input)
+		f(c)
+		assert.Equal(t.input, c.ipamLeaseTTL)
+	}
+}
+
+func TestWithIPReservations(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input map[string][]string
+	}{
+		"success": {
+			input: map[string][]string{"allan": {"10.10.10.2"}},
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithIPReservations(t.input)
+		f(c)
+		assert.Equal(t.input, c.ipReservations)
+	}
+}
+
 func TestWithGrpcPort(t *testing.T) {
 	assert := assert.New(t)
 
@@ -127,6 +499,25 @@ func TestWithGrpcTlsPem(t *testing.T) {
 	}
 }
 
+func TestWithGrpcTlsClientCA(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input string
+	}{
+		"success": {
+			input: "allan",
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithGrpcTlsClientCA(t.input)
+		f(c)
+		assert.Equal(t.input, c.grpcTlsClientCA)
+	}
+}
+
 func TestWithGrpcUnaryInterceptors(t *testing.T) {
 	assert := assert.New(t)
 
@@ -219,3 +610,47 @@ func TestWithAuthMethods(t *testing.T) {
 		assert.True(reflect.DeepEqual(t.input, c.grpcAuthMethods))
 	}
 }
+
+func TestWithAuthPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	f := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		return nil, nil
+	}
+	policy, err := auth.NewPolicy(map[protocol.AuthType]auth.ServerAuthMethod{protocol.AuthType_AT_LDAP: f}, nil)
+	assert.NoError(err)
+
+	tests := map[string]struct {
+		input *auth.Policy
+	}{
+		"success": {
+			input: policy,
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithAuthPolicy(t.input)
+		f(c)
+		assert.Equal(t.input, c.authPolicy)
+	}
+}
+
+func TestWithAuthTestMode(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		input bool
+	}{
+		"success": {
+			input: true,
+		},
+	}
+
+	for _, t := range tests {
+		c := &config{}
+		f := WithAuthTestMode(t.input)
+		f(c)
+		assert.Equal(t.input, c.authTestMode)
+	}
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/pkg/errors"
+)
+
+// RevocationStore is an interface for storing revoked jwt.
+type RevocationStore interface {
+	// RevokeToken refuses jwt which has jwt id(jti). expiredAt is the expired time of jwt.
+	RevokeToken(jti string, expiredAt time.Time) error
+
+	// RevokeUser refuses jwt of user which is issued before at.
+	// iat of jwt is seconds, so jwt issued within the same second as at is refused unless at is a whole second.
+	RevokeUser(user string, at time.Time) error
+
+	// IsRevoked checks whether jwt is revoked or not.
+	IsRevoked(claims *jwt.StandardClaims) bool
+}
+
+type revocations struct {
+	Tokens map[string]time.Time `json:"tokens"` // revoked tokens(map[jti]expired time)
+	Users  map[string]time.Time `json:"users"`  // revoked users(map[user]revoked time)
+}
+
+type memoryRevocationStore struct {
+	revocations revocations  // revocations
+	lock        sync.RWMutex // revocations lock
+}
+
+// RevokeToken refuses jwt which has jwt id(jti).
+func (m *memoryRevocationStore) RevokeToken(jti string, expiredAt time.Time) error {
+	if jti == "" {
+		return errors.Wrapf(internal.ErrorInvalidParams, "Method: RevokeToken")
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	// remove revoked tokens which are already expired.
+	now := time.Now()
+	for id, exp := range m.revocations.Tokens {
+		if exp.Before(now) {
+			delete(m.revocations.Tokens, id)
+		}
+	}
+	m.revocations.Tokens[jti] = expiredAt
+	return nil
+}
+
+// RevokeUser refuses jwt of user which is issued before at.
+func (m *memoryRevocationStore) RevokeUser(user string, at time.Time) error {
+	if user == "" {
+		return errors.Wrapf(internal.ErrorInvalidParams, "Method: RevokeUser")
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.revocations.Users[user] = at
+	return nil
+}
+
+// IsRevoked checks whether jwt is revoked or not.
+func (m *memoryRevocationStore) IsRevoked(claims *jwt.StandardClaims) bool {
+	if claims == nil {
+		return true
+	}
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if _, ok := m.revocations.Tokens[claims.Id]; ok && claims.Id != "" {
+		return true
+	}
+	if at, ok := m.revocations.Users[claims.Audience]; ok && time.Unix(claims.IssuedAt, 0).Before(at) {
+		return true
+	}
+	return false
+}
+
+type fileRevocationStore struct {
+	*memoryRevocationStore
+	path string // file path
+}
+
+// RevokeToken refuses jwt which has jwt id(jti), and writes revocations to file.
+func (f *fileRevocationStore) RevokeToken(jti string, expiredAt time.Time) error {
+	if err := f.memoryRevocationStore.RevokeToken(jti, expiredAt); err != nil {
+		return errors.Wrapf(err, "Method: RevokeToken")
+	}
+	if err := f.save(); err != nil {
+		return errors.Wrapf(err, "Method: RevokeToken")
+	}
+	return nil
+}
+
+// RevokeUser refuses jwt of user which is issued before at, and writes revocations to file.
+func (f *fileRevocationStore) RevokeUser(user string, at time.Time) error {
+	if err := f.memoryRevocationStore.RevokeUser(user, at); err != nil {
+		return errors.Wrapf(err, "Method: RevokeUser")
+	}
+	if err := f.save(); err != nil {
+		return errors.Wrapf(err, "Method: RevokeUser")
+	}
+	return nil
+}
+
+// save writes revocations to file.
+func (f *fileRevocationStore) save() error {
+	f.lock.RLock()
+	buf, err := json.Marshal(f.revocations)
+	f.lock.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	// write to temporary file and rename it, so file isn't broken.
+	tmp := f.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, buf, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+// NewMemoryRevocationStore returns RevocationStore which keeps revocations on memory.
+func NewMemoryRevocationStore() RevocationStore {
+	return newMemoryRevocationStore()
+}
+
+// NewFileRevocationStore returns RevocationStore which keeps revocations on memory and file.
+// revocations in file are loaded when it's created, so they are kept after restarting server.
+func NewFileRevocationStore(path string) (RevocationStore, error) {
+	if path == "" {
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: NewFileRevocationStore")
+	}
+
+	store := &fileRevocationStore{memoryRevocationStore: newMemoryRevocationStore(), path: path}
+	buf, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return store, nil
+	case err != nil:
+		return nil, errors.Wrapf(err, "Method: NewFileRevocationStore")
+	}
+
+	if err := json.Unmarshal(buf, &store.revocations); err != nil {
+		return nil, errors.Wrapf(err, "Method: NewFileRevocationStore")
+	}
+	if store.revocations.Tokens == nil {
+		store.revocations.Tokens = map[string]time.Time{}
+	}
+	if store.revocations.Users == nil {
+		store.revocations.Users = map[string]time.Time{}
+	}
+	return store, nil
+}
+
+func newMemoryRevocationStore() *memoryRevocationStore {
+	return &memoryRevocationStore{
+		revocations: revocations{
+			Tokens: map[string]time.Time{},
+			Users:  map[string]time.Time{},
+		},
+	}
+}
+
+// isRevokedJwt checks whether jwt is revoked or not.
+func (v *vpn) isRevokedJwt(token *jwt.Token) bool {
+	return isRevokedJwt(v.revocation, token)
+}
+
+// isRevokedJwt checks whether jwt is revoked in store or not.
+func isRevokedJwt(store RevocationStore, token *jwt.Token) bool {
+	if store == nil || token == nil {
+		return false
+	}
+	claims, ok := token.Claims.(*internal.VpnClaims)
+	if !ok {
+		return true
+	}
+	return store.IsRevoked(&claims.StandardClaims)
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRevocationStore_RevokeToken(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewMemoryRevocationStore()
+	assert.Error(store.RevokeToken("", time.Now().Add(time.Hour)))
+	assert.NoError(store.RevokeToken("expired-jti", time.Now().Add(-time.Hour)))
+	assert.NoError(store.RevokeToken("allan-jti", time.Now().Add(time.Hour)))
+
+	// expired tokens are removed when a token is revoked.
+	_, ok := store.(*memoryRevocationStore).revocations.Tokens["expired-jti"]
+	assert.False(ok)
+
+	tests := map[string]struct {
+		claims  *jwt.StandardClaims
+		revoked bool
+	}{
+		"nil":     {revoked: true},
+		"revoked": {claims: &jwt.StandardClaims{Id: "allan-jti", Audience: "allan"}, revoked: true},
+		"other":   {claims: &jwt.StandardClaims{Id: "bob-jti", Audience: "bob"}},
+		"no-jti":  {claims: &jwt.StandardClaims{Audience: "allan"}},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.revoked, store.IsRevoked(t.claims))
+	}
+}
+
+func TestMemoryRevocationStore_RevokeUser(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewMemoryRevocationStore()
+	assert.Error(store.RevokeUser("", time.Now()))
+	assert.NoError(store.RevokeUser("allan", time.Unix(1000, 0)))
+	assert.NoError(store.RevokeUser("bob", time.Unix(1000, 500*int64(time.Millisecond))))
+
+	tests := map[string]struct {
+		claims  *jwt.StandardClaims
+		revoked bool
+	}{
+		"issued-before":      {claims: &jwt.StandardClaims{Audience: "allan", IssuedAt: 999}, revoked: true},
+		"issued-at":          {claims: &jwt.StandardClaims{Audience: "allan", IssuedAt: 1000}},
+		"issued-same-second": {claims: &jwt.StandardClaims{Audience: "bob", IssuedAt: 1000}, revoked: true},
+		"reissued":           {claims: &jwt.StandardClaims{Audience: "bob", IssuedAt: 1001}},
+		"other-user":         {claims: &jwt.StandardClaims{Audience: "carl", IssuedAt: 999}},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.revoked, store.IsRevoked(t.claims))
+	}
+}
+
+func TestNewFileRevocationStore(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "revocation")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	broken := filepath.Join(dir, "broken.json")
+	assert.NoError(ioutil.WriteFile(broken, []byte("{"), 0600))
+
+	tests := map[string]struct {
+		path  string
+		isErr bool
+	}{
+		"empty":     {isErr: true},
+		"broken":    {path: broken, isErr: true},
+		"not-exist": {path: filepath.Join(dir, "revocation.json")},
+	}
+
+	for _, t := range tests {
+		_, err := NewFileRevocationStore(t.path)
+		assert.Equal(t.isErr, err != nil)
+	}
+}
+
+func TestFileRevocationStore(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "revocation")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	path := filepath.Join(dir, "revocation.json")
+	store, err := NewFileRevocationStore(path)
+	assert.NoError(err)
+	assert.NoError(store.RevokeToken("allan-jti", now.Add(time.Hour)))
+	assert.NoError(store.RevokeUser("bob", now))
+
+	// revocations are kept after reloading.
+	reloaded, err := NewFileRevocationStore(path)
+	assert.NoError(err)
+	assert.True(reloaded.IsRevoked(&jwt.StandardClaims{Id: "allan-jti", Audience: "allan"}))
+	assert.True(reloaded.IsRevoked(&jwt.StandardClaims{Audience: "bob", IssuedAt: now.Unix()}))
+	assert.False(reloaded.IsRevoked(&jwt.StandardClaims{Id: "other-jti", Audience: "allan"}))
+}
+
+func TestVpn_Exchange_Revoked(t *testing.T) {
+	assert := assert.New(t)
+
+	v := testVPN(t, nil)
+	encode, _, err := v.issueJwt(identity{user: "allan"})
+	assert.NoError(err)
+	token, err := v.DecodeJwt(encode)
+	assert.NoError(err)
+
+	// user is revoked while connecting, after jwt is checked by interceptor.
+	assert.NoError(v.revocation.RevokeUser("allan", time.Now().Add(time.Second)))
+	ctx := context.WithValue(context.Background(), ipCtxName, net.ParseIP("1.1.1.1"))
+	ctx = context.WithValue(ctx, jwtCtxName, token)
+	err = v.Exchange(&mockExchangeServer{ctx: ctx})
+	assert.Equal(internal.ErrorRevokedJWT, errors.Cause(err))
+	assert.Empty(v.sessions())
+	for _, l := range v.ipam.Leases() {
+		assert.False(l.Active)
+	}
+}
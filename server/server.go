@@ -10,6 +10,7 @@ package server
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net"
 	"os"
@@ -51,6 +52,10 @@ var (
 		WithVpnJwtSalt(internal.GenerateRandomString(16)),
 		WithGrpcPort("8080"),
 		WithVpnJwtExpiration(24 * time.Hour),
+		WithVpnRefreshTokenExpiration(7 * 24 * time.Hour),
+		WithVpnSessionGracePeriod(2 * time.Minute),
+		WithIPAMLeaseTTL(24 * time.Hour),
+		WithClientIsolation(ClientIsolationAllowAll),
 	}
 
 	defaultLogger *logrus.Logger
@@ -87,16 +92,14 @@ func NewVpnServer(opts ...Option) (VpnServer, error) {
 	}
 
 	// add authentication method to interceptors
-	unaryInterceptors := []grpc.UnaryServerInterceptor{}
-	for _, method := range cfg.grpcAuthMethods {
-		unaryInterceptors = append(unaryInterceptors, grpc.UnaryServerInterceptor(method))
+	unaryInterceptors, err := authUnaryServerInterceptors(cfg)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: NewVpnServer")
 	}
 
-	// if auth methods isn't to exist, inserting auth method for test.
-	if len(unaryInterceptors) == 0 {
-		authManager, _ := auth.NewServerManagerForTest()
-		method, _ := authManager.ServerAuth()
-		unaryInterceptors = append(unaryInterceptors, grpc.UnaryServerInterceptor(method))
+	// admin apis are protected by admin token.
+	if cfg.adminToken != "" {
+		unaryInterceptors = append(unaryInterceptors, adminUnaryServerInterceptor(cfg.adminToken))
 	}
 	unaryInterceptors = append(unaryInterceptors, cfg.grpcUnaryInterceptors...)
 
@@ -122,8 +125,21 @@ func NewVpnServer(opts ...Option) (VpnServer, error) {
 		if err != nil {
 			return nil, errors.Wrapf(err, "Method: NewVpnServer")
 		}
-		cred := credentials.NewServerTLSFromCert(&cert)
-		cfg.grpcOptions = append([]grpc.ServerOption{grpc.Creds(cred)}, cfg.grpcOptions...)
+		tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}}
+
+		// client certificate is optional, because clients may use other authentications.
+		if cfg.grpcTlsClientCA != "" {
+			clientCAs := x509.NewCertPool()
+			if ok := clientCAs.AppendCertsFromPEM([]byte(cfg.grpcTlsClientCA)); !ok {
+				return nil, errors.Wrapf(internal.ErrorInvalidParams, "Client CA Invalid Method: NewVpnServer")
+			}
+			tlsConfig.ClientCAs = clientCAs
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+		cfg.grpcOptions = append([]grpc.ServerOption{grpc.Creds(credentials.NewTLS(tlsConfig))}, cfg.grpcOptions...)
+	} else if cfg.grpcTlsClientCA != "" {
+		// mutual tls needs tls.
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Client CA Without TLS Method: NewVpnServer")
 	}
 
 	// merge all of GRPC interceptors
@@ -140,7 +156,7 @@ func NewVpnServer(opts ...Option) (VpnServer, error) {
 	}
 
 	// make vpn
-	vpn, err := newVPN(cfg.vpnSubNet, cfg.vpnJwtSalt, cfg.vpnJwtExpiration)
+	vpn, err := newVPN(cfg)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Method: NewVpnServer")
 	}
@@ -149,6 +165,15 @@ func NewVpnServer(opts ...Option) (VpnServer, error) {
 	// register api
 	protocol.RegisterVPNServer(server.grpc, vpn)
 
+	// register admin api, if admin token exists.
+	if cfg.adminToken != "" {
+		admin, err := newAdmin(vpn)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Method: NewVpnServer")
+		}
+		protocol.RegisterAdminServer(server.grpc, admin)
+	}
+
 	// register health check handler
 	health_pb.RegisterHealthServer(server.grpc, grpchealth.NewServer())
 
@@ -217,6 +242,43 @@ func defaultStreamServerInterceptors() grpc.StreamServerInterceptor {
 	}
 }
 
+// authUnaryServerInterceptors returns interceptors authenticating users with auth policy(or deprecated chained auth methods).
+// vpn server must not accept anyone silently, so test auth is only used in test mode.
+func authUnaryServerInterceptors(cfg *config) ([]grpc.UnaryServerInterceptor, error) {
+	var methods []auth.ServerAuthMethod
+	if cfg.authPolicy != nil {
+		if len(cfg.grpcAuthMethods) > 0 {
+			return nil, errors.Wrapf(internal.ErrorInvalidParams, "Auth Policy With Auth Methods Method: authUnaryServerInterceptors")
+		}
+		method, _ := cfg.authPolicy.ServerAuth()
+		methods = append(methods, method)
+		defaultLogger.Info(color.GreenString("[AUTH] policy %s", cfg.authPolicy))
+	} else {
+		methods = append(methods, cfg.grpcAuthMethods...)
+		// clients can authenticate with client certificate if client CA exists.
+		if cfg.grpcTlsClientCA != "" {
+			method, _ := (&auth.MTLSConfig{}).ServerAuth()
+			methods = append(methods, method)
+		}
+	}
+
+	if len(methods) == 0 {
+		if !cfg.authTestMode {
+			return nil, errors.Wrapf(internal.ErrorNotFoundAuthMethod, "Method: authUnaryServerInterceptors")
+		}
+		defaultLogger.Warn(color.YellowString("[AUTH] test mode, anyone can be authenticated"))
+		authManager, _ := auth.NewServerManagerForTest()
+		method, _ := authManager.ServerAuth()
+		methods = append(methods, method)
+	}
+
+	var interceptors []grpc.UnaryServerInterceptor
+	for _, method := range methods {
+		interceptors = append(interceptors, grpc.UnaryServerInterceptor(method))
+	}
+	return interceptors, nil
+}
+
 func defaultUnaryServerInterceptors() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		defer func() {
@@ -271,11 +333,16 @@ func checkJwt(srv interface{}, stream grpc.ServerStream) (*jwt.Token, error) {
 		return nil, errors.Wrapf(internal.ErrorUnauthorized, "Method: auth")
 	}
 
-	jwt, err := internal.DecodeJWT(seps[1], []byte(srv.(VPN).GetJwtSalt()))
+	jwt, err := srv.(VPN).DecodeJwt(seps[1])
 	if err != nil {
 		return nil, errors.Wrapf(internal.ErrorInvalidJWT, "Method: auth")
 	}
 
+	// check whether jwt is revoked.
+	if v, ok := srv.(*vpn); ok && v.isRevokedJwt(jwt) {
+		return nil, errors.Wrapf(internal.ErrorRevokedJWT, "Method: auth")
+	}
+
 	return jwt, nil
 }
 
@@ -3,7 +3,10 @@ package server
 import (
 	"context"
 	"fmt"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/gjbae1212/grpc-vpn/auth"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"testing"
 	"time"
@@ -31,6 +34,9 @@ func (m *mockVPN) Close() error {
 }
 func (m *mockVPN) Exchange(stream protocol.VPN_ExchangeServer) error { return nil }
 func (m *mockVPN) GetJwtSalt() string                                { return "mock" }
+func (m *mockVPN) DecodeJwt(data string) (*jwt.Token, error) {
+	return internal.DecodeJWT(data, []byte("mock"))
+}
 func (m *mockVPN) Auth(ctx context.Context, req *protocol.AuthRequest) (*protocol.AuthResponse, error) {
 	return nil, nil
 }
@@ -41,13 +47,19 @@ func TestNewVpnServer(t *testing.T) {
 	authManger, err := auth.NewServerManagerForTest()
 	assert.NoError(err)
 	authMethod, _ := authManger.ServerAuth()
+	policy, err := auth.NewPolicy(map[protocol.AuthType]auth.ServerAuthMethod{protocol.AuthType_AT_TEST: authMethod}, nil)
+	assert.NoError(err)
 
 	tests := map[string]struct {
 		opts  []Option
 		check *config
 		err   error
 	}{
+		"without-auth": {
+			err: internal.ErrorNotFoundAuthMethod,
+		},
 		"default": {
+			opts: []Option{WithAuthTestMode(true)},
 			check: &config{
 				vpnSubNet: "10.10.10.1/24",
 				grpcPort:  "8080",
@@ -100,11 +112,35 @@ func TestNewVpnServer(t *testing.T) {
 					})},
 			},
 		},
+		"policy": {
+			opts: []Option{WithAuthPolicy(policy)},
+			check: &config{
+				vpnSubNet: "10.10.10.1/24",
+				grpcPort:  "8080",
+				grpcOptions: []grpc.ServerOption{
+					grpc.MaxRecvMsgSize(maxGRPCMsgSize),
+					grpc.MaxSendMsgSize(maxGRPCMsgSize),
+					grpc.KeepaliveParams(keepalive.ServerParameters{
+						Time:    5 * time.Minute,  // keepalive 5 min
+						Timeout: 20 * time.Second, // keepalive timeout
+					})},
+				grpcUnaryInterceptors: []grpc.UnaryServerInterceptor{
+					grpc.UnaryServerInterceptor(authMethod),
+				},
+			},
+		},
+		"policy-with-auth-methods": {
+			opts: []Option{WithAuthPolicy(policy), WithAuthMethods([]auth.ServerAuthMethod{authMethod})},
+			err:  internal.ErrorInvalidParams,
+		},
 	}
 
 	for _, t := range tests {
 		s, err := NewVpnServer(t.opts...)
-		assert.Equal(t.err, err)
+		assert.Equal(t.err, errors.Cause(err))
+		if err != nil {
+			continue
+		}
 		vpn := s.(*vpnServer)
 		assert.Equal(t.check.vpnSubNet, vpn.config.vpnSubNet)
 		assert.Equal(t.check.grpcPort, vpn.config.grpcPort)
@@ -121,7 +157,7 @@ func TestVpnServer_Run(t *testing.T) {
 		input []Option
 		isErr bool
 	}{
-		"success": {},
+		"success": {input: []Option{WithAuthTestMode(true)}},
 	}
 
 	for _, t := range tests {
@@ -144,6 +180,7 @@ func TestVpnServer_Run(t *testing.T) {
 
 func TestSetDefaultLogger(t *testing.T) {
 	assert := assert.New(t)
+	defer SetDefaultLogger(defaultLogger)
 	tests := map[string]struct {
 		input *logrus.Logger
 	}{
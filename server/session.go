@@ -0,0 +1,122 @@
+package server
+
+import (
+	"net"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/gjbae1212/grpc-vpn/auth"
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	sessionIDLength = 32
+)
+
+// session is a disconnected session whose vpn ips are held during grace period, so client can resume it.
+type session struct {
+	id     string      // session id
+	user   string      // user
+	vpnIP  net.IP      // held vpn ip
+	vpnIP6 net.IP      // held vpn ipv6
+	timer  *time.Timer // timer releasing vpn ips when grace period is over
+}
+
+// requestedSessionID extracts session id which client wants to resume.
+func requestedSessionID(stream protocol.VPN_ExchangeServer) string {
+	md, ok := metadata.FromIncomingContext(stream.Context())
+	if !ok || len(md[auth.SessionIDHeader]) == 0 {
+		return ""
+	}
+	return md[auth.SessionIDHeader][0]
+}
+
+// resumeClient assigns vpn ips of session to client, and returns whether session is resumed or not.
+// if session is still connected because server doesn't notice disconnection yet, old connection is kicked.
+func (v *vpn) resumeClient(c *client, id string) bool {
+	if c == nil || id == "" || v.sessionGracePeriod <= 0 {
+		return false
+	}
+	v.clientsLock.Lock()
+	defer v.clientsLock.Unlock()
+
+	var vpnIP, vpnIP6 net.IP
+	if s, ok := v.detached[id]; ok && s.user == c.user {
+		s.timer.Stop()
+		delete(v.detached, id)
+		vpnIP, vpnIP6 = s.vpnIP, s.vpnIP6
+	} else if old := v.findClientBySession(id); old != nil && old.user == c.user {
+		old.kick()
+		vpnIP, vpnIP6 = old.vpnIP, old.vpnIP6
+	} else {
+		return false
+	}
+
+	c.sessionID = id
+	c.vpnIP = vpnIP
+	c.vpnIP6 = vpnIP6
+	for _, ip := range c.vpnIPs() {
+		v.clients[ip.String()] = c
+	}
+	return true
+}
+
+// detachClient unregisters client, and holds vpn ips of client during grace period.
+// if grace period is disabled or client is kicked by admin, vpn ips are released immediately.
+func (v *vpn) detachClient(c *client) error {
+	if c == nil || c.sessionID == "" || v.sessionGracePeriod <= 0 || c.kicked.Load() {
+		return v.deleteClient(c)
+	}
+	v.clientsLock.Lock()
+	defer v.clientsLock.Unlock()
+
+	// vpn ips which are taken over by resumed client aren't held.
+	var owned bool
+	for _, ip := range c.vpnIPs() {
+		if cli, ok := v.clients[ip.String()]; ok && cli == c {
+			delete(v.clients, ip.String())
+			owned = true
+		}
+	}
+	if !owned {
+		return nil
+	}
+
+	s := &session{id: c.sessionID, user: c.user, vpnIP: c.vpnIP, vpnIP6: c.vpnIP6}
+	s.timer = time.AfterFunc(v.sessionGracePeriod, func() {
+		v.expireSession(s)
+	})
+	v.detached[s.id] = s
+	return nil
+}
+
+// expireSession releases vpn ips of session which isn't resumed during grace period.
+func (v *vpn) expireSession(s *session) {
+	v.clientsLock.Lock()
+	defer v.clientsLock.Unlock()
+
+	// session is already resumed.
+	if v.detached[s.id] != s {
+		return
+	}
+	delete(v.detached, s.id)
+
+	for _, ip := range []net.IP{s.vpnIP, s.vpnIP6} {
+		if ip != nil {
+			_ = v.ipam.Release(s.user, ip)
+		}
+	}
+	defaultLogger.Info(color.YellowString("[EXPIRE] %s session vpn IP(%s) vpn IP6(%s)",
+		s.user, s.vpnIP.String(), s.vpnIP6.String()))
+}
+
+// findClientBySession returns connected client which has session id, clientsLock must be held.
+func (v *vpn) findClientBySession(id string) *client {
+	for _, c := range v.clients {
+		if c.sessionID == id {
+			return c
+		}
+	}
+	return nil
+}
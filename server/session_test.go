@@ -0,0 +1,127 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gjbae1212/grpc-vpn/auth"
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+type mockExchangeServer struct {
+	protocol.VPN_ExchangeServer
+	ctx context.Context
+}
+
+func (m *mockExchangeServer) Context() context.Context { return m.ctx }
+
+func testSessionVPN(t *testing.T, grace time.Duration) *vpn {
+	return testVPN(t, func(cfg *config) { cfg.vpnSessionGracePeriod = grace })
+}
+
+func TestRequestedSessionID(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		ctx    context.Context
+		output string
+	}{
+		"empty":   {ctx: context.Background()},
+		"success": {ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(auth.SessionIDHeader, "session")), output: "session"},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.output, requestedSessionID(&mockExchangeServer{ctx: t.ctx}))
+	}
+}
+
+func TestVpn_resumeClient(t *testing.T) {
+	assert := assert.New(t)
+
+	v := testSessionVPN(t, time.Hour)
+	old := &client{user: "allan", sessionID: "session", exit: make(chan bool, 1)}
+	assert.NoError(v.addClient(old))
+
+	// resume session which is still connected, old connection is kicked.
+	taken := &client{user: "allan"}
+	assert.True(v.resumeClient(taken, "session"))
+	assert.Len(old.exit, 1)
+	assert.True(old.vpnIP.Equal(taken.vpnIP))
+	assert.True(old.vpnIP6.Equal(taken.vpnIP6))
+	assert.Equal(taken, v.getClient(taken.vpnIP))
+
+	// old connection doesn't hold vpn ips which are taken over.
+	assert.NoError(v.detachClient(old))
+	assert.Len(v.detached, 0)
+	assert.Equal(taken, v.getClient(taken.vpnIP))
+
+	// resume session which is disconnected.
+	assert.NoError(v.detachClient(taken))
+	assert.Len(v.detached, 1)
+	assert.Nil(v.getClient(taken.vpnIP))
+
+	tests := map[string]struct {
+		client *client
+		id     string
+		ok     bool
+	}{
+		"empty":      {client: &client{user: "allan"}},
+		"unknown":    {client: &client{user: "allan"}, id: "unknown"},
+		"other-user": {client: &client{user: "bob"}, id: "session"},
+	}
+
+	for _, t := range tests {
+		assert.Equal(t.ok, v.resumeClient(t.client, t.id))
+	}
+
+	resumed := &client{user: "allan"}
+	assert.True(v.resumeClient(resumed, "session"))
+	assert.Equal("session", resumed.sessionID)
+	assert.True(taken.vpnIP.Equal(resumed.vpnIP))
+	assert.Equal(resumed, v.getClient(resumed.vpnIP6))
+	assert.Len(v.detached, 0)
+
+	// session isn't resumed if grace period is disabled.
+	v.sessionGracePeriod = 0
+	assert.False(v.resumeClient(&client{user: "allan"}, "session"))
+}
+
+func TestVpn_detachClient_Kicked(t *testing.T) {
+	assert := assert.New(t)
+
+	v := testSessionVPN(t, time.Hour)
+	c := &client{user: "allan", sessionID: "session", exit: make(chan bool, 1)}
+	assert.NoError(v.addClient(c))
+
+	// kicked session isn't held, so client gets a fresh session.
+	assert.Equal(1, v.kick(func(c *client) bool { return c.user == "allan" }))
+	assert.Len(c.exit, 1)
+	assert.NoError(v.detachClient(c))
+	assert.Len(v.detached, 0)
+	for _, lease := range v.ipam.Leases() {
+		assert.False(lease.Active)
+	}
+	assert.False(v.resumeClient(&client{user: "allan"}, "session"))
+}
+
+func TestVpn_expireSession(t *testing.T) {
+	assert := assert.New(t)
+
+	v := testSessionVPN(t, 10*time.Millisecond)
+	c := &client{user: "allan", sessionID: "session"}
+	assert.NoError(v.addClient(c))
+	assert.NoError(v.detachClient(c))
+
+	// vpn ips are released after grace period.
+	time.Sleep(100 * time.Millisecond)
+	v.clientsLock.RLock()
+	assert.Len(v.detached, 0)
+	v.clientsLock.RUnlock()
+	for _, lease := range v.ipam.Leases() {
+		assert.False(lease.Active)
+	}
+	assert.False(v.resumeClient(&client{user: "allan"}, "session"))
+}
@@ -5,6 +5,7 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"sort"
 	"strings"
 	"sync"
 	"syscall"
@@ -13,7 +14,6 @@ import (
 	"github.com/dgrijalva/jwt-go"
 	"github.com/fatih/color"
 	"github.com/gjbae1212/grpc-vpn/auth"
-	"github.com/songgao/water/waterutil"
 
 	"github.com/gjbae1212/grpc-vpn/internal"
 	"github.com/pkg/errors"
@@ -25,6 +25,30 @@ import (
 const (
 	queueSizeForClientToServer = 10000
 	queueSizeForServerToClient = 10000
+	jwtIdLength                = 32
+)
+
+const (
+	jwtIssuer           = "grpc-vpn"
+	jwtSubject          = "grpc-vpn-auth"
+	refreshTokenSubject = "grpc-vpn-refresh"
+)
+
+// jwtRenewer renews jwt of user using refresh token.
+type jwtRenewer func(user, refreshToken string) (*jwt.Token, *protocol.IPPacket_Renew, error)
+
+// ClientIsolation is a mode for traffic between clients.
+type ClientIsolation string
+
+const (
+	// ClientIsolationAllowAll allows traffic between all clients.
+	ClientIsolationAllowAll ClientIsolation = "allow_all"
+
+	// ClientIsolationDenyAll denies traffic between clients.
+	ClientIsolationDenyAll ClientIsolation = "deny_all"
+
+	// ClientIsolationSameGroup allows traffic between clients only in the same group.
+	ClientIsolationSameGroup ClientIsolation = "same_group"
 )
 
 type VPN interface {
@@ -36,6 +60,9 @@ type VPN interface {
 
 	GetJwtSalt() string
 
+	// DecodeJwt decodes and verifies jwt issued by VPN.
+	DecodeJwt(data string) (*jwt.Token, error)
+
 	// GRPC METHODS
 	Exchange(stream protocol.VPN_ExchangeServer) error
 	Auth(ctx context.Context, req *protocol.AuthRequest) (*protocol.AuthResponse, error)
@@ -47,14 +74,46 @@ type vpn struct {
 	localIP      net.IP     // vpn server ip
 	localNetmask *net.IPNet // vpn server netmask
 
+	localIP6      net.IP     // vpn server ipv6 (dual stack)
+	localNetmask6 *net.IPNet // vpn server ipv6 netmask (dual stack)
+
 	clients     map[string]*client // clients(map[vpn-ip]*client)
 	clientsLock sync.RWMutex       // clients lock
 
+	ipam         IPAM              // ip address manager
+	reservations map[string]string // reserved ips(map[vpn-ip]user)
+
 	clientToServer chan *protocol.IPPacket // packets which flow from client to server.
 	serverToClient chan *protocol.IPPacket // packets which flow from server to client.
 
-	jwtSalt       string        // JWT Salt
-	jwtExpiration time.Duration // JWT Expiration
+	jwtSalt                string              // JWT Salt
+	jwtKeySet              *internal.JWTKeySet // JWT asymmetric keys
+	jwtExpiration          time.Duration       // JWT Expiration
+	refreshTokenExpiration time.Duration       // refresh token expiration(0 is disabled)
+
+	routes           []string      // routes(cidr) pushed to clients
+	fullTunnel       bool          // whether all traffic of clients flows through vpn or not
+	dnsServers       []string      // dns servers pushed to clients
+	dnsSearchDomains []string      // dns search domains pushed to clients
+	dns              *dnsForwarder // dns forwarder on vpn gateway ip
+
+	groups map[string][]string // groups of users(map[user][]group)
+	acl    *acl                // network acl
+
+	clientIsolation ClientIsolation // mode for traffic between clients
+
+	revocation RevocationStore // revoked jwt
+
+	mfaStore        MFAStore                 // totp secrets of users
+	mfaRequired     bool                     // whether users who aren't enrolled are refused or not
+	mfaChallenges   map[string]*mfaChallenge // pending challenges(map[challenge id]challenge)
+	mfaUsedCounters map[string]int64         // last used totp counters(map[user]counter)
+	mfaLock         sync.Mutex               // mfa lock
+
+	sessionGracePeriod time.Duration       // how long vpn ips of disconnected session are held
+	detached           map[string]*session // disconnected sessions(map[session id]session)
+
+	startedAt time.Time // started time
 
 	exit     chan bool // exit channel
 	stopping bool
@@ -65,37 +124,152 @@ func (v *vpn) Auth(ctx context.Context, req *protocol.AuthRequest) (*protocol.Au
 	if v.stopping {
 		return nil, errors.Wrapf(internal.ErrorStoppingServer, "Method: Auth")
 	}
-	_ = req
 
-	var user string
 	ip := ctx.Value(ipCtxName).(net.IP)
-	if ctx.Value(auth.UserCtxName) == nil { // fail
+
+	// second factor for challenge of primary authentication.
+	if req.GetAuthType() == protocol.AuthType_AT_MFA {
+		challenge, ok := v.verifyMFA(req.GetMfa())
+		if !ok {
+			defaultLogger.Info(color.RedString("[NOT-ISSUE][MFA] origin IP(%s)", ip.String()))
+			return &protocol.AuthResponse{ErrorCode: protocol.ErrorCode_EC_INVALID_AUTHORIZATION}, nil
+		}
+		return v.issueAuthResponse(challenge.identity, ip)
+	}
+
+	id := identityFromContext(ctx)
+	if id.user == "" { // fail
 		defaultLogger.Info(color.RedString("[NOT-ISSUE] origin IP(%s)", ip.String()))
 		return &protocol.AuthResponse{ErrorCode: protocol.ErrorCode_EC_INVALID_AUTHORIZATION}, nil
-	} else { // success
-		user = ctx.Value(auth.UserCtxName).(string)
 	}
 
-	// make jwt token
-	claims := &jwt.StandardClaims{
-		Audience:  user,
-		Subject:   "grpc-vpn-auth",
-		ExpiresAt: time.Now().Add(v.jwtExpiration).Unix(),
-		IssuedAt:  time.Now().Unix(),
-		Issuer:    "grpc-vpn",
+	// jwt is issued after second factor if user is enrolled.
+	challengeId, ok := v.challengeMFA(id)
+	if !ok {
+		defaultLogger.Info(color.RedString("[NOT-ISSUE][MFA] %s isn't enrolled origin IP(%s)", id.user, ip.String()))
+		return &protocol.AuthResponse{ErrorCode: protocol.ErrorCode_EC_INVALID_AUTHORIZATION}, nil
+	}
+	if challengeId != "" {
+		defaultLogger.Info(color.YellowString("[MFA-REQUIRED] %s origin IP(%s)", id.user, ip.String()))
+		return &protocol.AuthResponse{ErrorCode: protocol.ErrorCode_EC_MFA_REQUIRED, MfaChallengeId: challengeId}, nil
 	}
-	encode, err := internal.EncodeJWT(claims, []byte(v.jwtSalt))
+	return v.issueAuthResponse(id, ip)
+}
+
+// identity is user and claims given by auth method, which are carried in jwt.
+type identity struct {
+	user       string            // user
+	principal  string            // authenticated principal(e.g. aws iam arn, ldap dn)
+	groups     []string          // vpn groups(e.g. aws iam role groups, mapped oidc or ldap groups)
+	roles      []string          // roles(e.g. aws iam role)
+	attributes map[string]string // attributes(e.g. google hosted domain, aws account id)
+}
+
+// identityFromContext returns identity which auth method injected to context.
+func identityFromContext(ctx context.Context) identity {
+	var id identity
+	id.user, _ = ctx.Value(auth.UserCtxName).(string)
+	id.principal, _ = ctx.Value(auth.PrincipalCtxName).(string)
+	id.groups, _ = ctx.Value(auth.GroupsCtxName).([]string)
+	id.roles, _ = ctx.Value(auth.RolesCtxName).([]string)
+	id.attributes, _ = ctx.Value(auth.AttributesCtxName).(map[string]string)
+	return id
+}
+
+// issueAuthResponse returns response having jwt of authenticated user.
+func (v *vpn) issueAuthResponse(id identity, ip net.IP) (*protocol.AuthResponse, error) {
+	encode, refreshToken, err := v.issueJwt(id)
 	if err != nil {
-		return nil, errors.Wrapf(err, "Method: Auth")
+		return nil, errors.Wrapf(err, "Method: issueAuthResponse")
 	}
-	defaultLogger.Info(color.GreenString("[ISSUE] %s origin IP(%s)", user, ip.String()))
+	defaultLogger.Info(color.GreenString("[ISSUE] %s origin IP(%s) groups(%s) roles(%s)", id.user, ip.String(),
+		strings.Join(id.groups, ","), strings.Join(id.roles, ",")))
 
 	return &protocol.AuthResponse{
-		ErrorCode: protocol.ErrorCode_EC_SUCCESS,
-		Jwt:       encode,
+		ErrorCode:    protocol.ErrorCode_EC_SUCCESS,
+		Jwt:          encode,
+		RefreshToken: refreshToken,
 	}, nil
 }
 
+// issueJwt makes jwt and refresh token of user, and refresh token is empty if it's disabled.
+// both have jwt id(jti) which is used to revoke, and principal, groups, roles and attributes given by auth method.
+func (v *vpn) issueJwt(id identity) (string, string, error) {
+	now := time.Now()
+	claims := &internal.VpnClaims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        internal.GenerateRandomString(jwtIdLength),
+			Audience:  id.user,
+			Subject:   jwtSubject,
+			ExpiresAt: now.Add(v.jwtExpiration).Unix(),
+			IssuedAt:  now.Unix(),
+			Issuer:    jwtIssuer,
+		},
+		Principal:  id.principal,
+		Groups:     id.groups,
+		Roles:      id.roles,
+		Attributes: id.attributes,
+	}
+	encode, err := v.encodeJwt(claims)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "Method: issueJwt")
+	}
+	if v.refreshTokenExpiration <= 0 {
+		return encode, "", nil
+	}
+
+	refreshClaims := &internal.VpnClaims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        internal.GenerateRandomString(jwtIdLength),
+			Audience:  id.user,
+			Subject:   refreshTokenSubject,
+			ExpiresAt: now.Add(v.refreshTokenExpiration).Unix(),
+			IssuedAt:  now.Unix(),
+			Issuer:    jwtIssuer,
+		},
+		Principal:  id.principal,
+		Groups:     id.groups,
+		Roles:      id.roles,
+		Attributes: id.attributes,
+	}
+	refreshToken, err := v.encodeJwt(refreshClaims)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "Method: issueJwt")
+	}
+	return encode, refreshToken, nil
+}
+
+// renewJwt issues new jwt and refresh token of user using refresh token.
+// refresh token is used only once, so it's revoked after renewal.
+func (v *vpn) renewJwt(user, refreshToken string) (*jwt.Token, *protocol.IPPacket_Renew, error) {
+	token, err := v.decodeJwt(refreshToken)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Method: renewJwt")
+	}
+
+	claims := token.Claims.(*internal.VpnClaims)
+	if claims.Subject != refreshTokenSubject || claims.Audience != user {
+		return nil, nil, errors.Wrapf(internal.ErrorInvalidJWT, "Method: renewJwt")
+	}
+	if v.isRevokedJwt(token) {
+		return nil, nil, errors.Wrapf(internal.ErrorRevokedJWT, "Method: renewJwt")
+	}
+	if err := v.revocation.RevokeToken(claims.Id, time.Unix(claims.ExpiresAt, 0)); err != nil {
+		return nil, nil, errors.Wrapf(err, "Method: renewJwt")
+	}
+
+	encode, newRefreshToken, err := v.issueJwt(identity{user: user, principal: claims.Principal, groups: claims.Groups,
+		roles: claims.Roles, attributes: claims.Attributes})
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Method: renewJwt")
+	}
+	renewed, err := v.DecodeJwt(encode)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Method: renewJwt")
+	}
+	return renewed, &protocol.IPPacket_Renew{Jwt: encode, RefreshToken: newRefreshToken}, nil
+}
+
 // Exchange is to exchange packets, and it's GRPC METHOD.
 func (v *vpn) Exchange(stream protocol.VPN_ExchangeServer) error {
 	if v.stopping {
@@ -106,29 +280,65 @@ func (v *vpn) Exchange(stream protocol.VPN_ExchangeServer) error {
 	if err != nil {
 		return errors.Wrapf(err, "Method: Exchange")
 	}
+	cli.groups = internal.MergeStrings(v.groups[cli.user], cli.getJwt().Claims.(*internal.VpnClaims).Groups)
+	cli.revocation = v.revocation
+	cli.renewer = v.renewJwt
+
+	// resume session, or add client as new session.
+	resumed := v.resumeClient(cli, requestedSessionID(stream))
+	if !resumed {
+		if err := v.addClient(cli); err != nil {
+			return errors.Wrapf(err, "Method: Exchange")
+		}
+		if v.sessionGracePeriod > 0 {
+			cli.sessionID = internal.GenerateRandomString(sessionIDLength)
+		}
+	}
 
-	// add client
-	if err := v.addClient(cli); err != nil {
-		return errors.Wrapf(err, "Method: Exchange")
+	// check revocation again after client is registered, because user might be revoked and kicked while connecting.
+	if v.isRevokedJwt(cli.getJwt()) {
+		_ = v.deleteClient(cli)
+		return errors.Wrapf(internal.ErrorRevokedJWT, "Method: Exchange")
 	}
 
 	// assign vpn ip to client.
+	assign := &protocol.IPPacket_Vpn{}
+	if cli.vpnIP != nil {
+		assign.VpnAssignedIp = cli.vpnIP
+		assign.VpnGateway = v.localIP
+		assign.VpnSubnetIp = v.localNetmask.IP
+		assign.VpnSubnetMask = v.localNetmask.Mask
+	}
+	if cli.vpnIP6 != nil {
+		assign.VpnAssignedIp6 = cli.vpnIP6
+		assign.VpnGateway6 = v.localIP6
+		assign.VpnSubnetIp6 = v.localNetmask6.IP
+		assign.VpnSubnetMask6 = v.localNetmask6.Mask
+	}
+	assign.Routes = v.routes
+	assign.FullTunnel = v.fullTunnel
+	assign.DnsServers = v.dnsServers
+	assign.DnsSearchDomains = v.dnsSearchDomains
+	assign.SessionId = cli.sessionID
+	assign.Resumed = resumed
 	packet := &protocol.IPPacket{
 		ErrorCode:  protocol.ErrorCode_EC_SUCCESS,
 		PacketType: protocol.IPPacketType_IPPT_VPN_ASSIGN,
-		Packet2: &protocol.IPPacket_Vpn{
-			VpnAssignedIp: cli.vpnIP,
-			VpnGateway:    v.localIP,
-			VpnSubnetIp:   v.localNetmask.IP,
-			VpnSubnetMask: v.localNetmask.Mask,
-		},
+		Packet2:    assign,
 	}
 	if err := stream.Send(packet); err != nil {
+		_ = v.detachClient(cli)
 		return errors.Wrapf(err, "Method: Exchange")
 	}
 
-	defaultLogger.Info(color.GreenString("[LOGIN] %s origin IP(%s) vpn IP(%s)",
-		cli.user, cli.originIP.String(), cli.vpnIP.String()))
+	if resumed {
+		defaultLogger.Info(color.GreenString("[RESUME] %s origin IP(%s) vpn IP(%s) vpn IP6(%s)",
+			cli.user, cli.originIP.String(), cli.vpnIP.String(), cli.vpnIP6.String()))
+	} else {
+		defaultLogger.Info(color.GreenString("[LOGIN] %s origin IP(%s) vpn IP(%s) vpn IP6(%s) groups(%s) roles(%s)",
+			cli.user, cli.originIP.String(), cli.vpnIP.String(), cli.vpnIP6.String(),
+			strings.Join(cli.groups, ","), strings.Join(cli.roles, ",")))
+	}
 
 	// receive packets
 	go cli.processReading()
@@ -136,8 +346,8 @@ func (v *vpn) Exchange(stream protocol.VPN_ExchangeServer) error {
 	// write packets and block
 	cli.processWriting()
 
-	// delete client
-	_ = v.deleteClient(cli)
+	// delete client, and vpn ips are held during grace period for resuming session.
+	_ = v.detachClient(cli)
 
 	defaultLogger.Info(color.GreenString("[logout] %s (%s, %s)",
 		cli.user, cli.originIP.String(), cli.vpnIP.String()))
@@ -156,13 +366,33 @@ func (v *vpn) Run() error {
 	v.tun = tun
 
 	// set ip to tun device
-	if err := internal.SetTunIP(v.tun.Name(), v.localIP, v.localNetmask); err != nil {
-		return errors.Wrapf(err, "Method: %s", "newVPN")
+	if v.localIP != nil {
+		if err := internal.SetTunIP(v.tun.Name(), v.localIP, v.localNetmask); err != nil {
+			return errors.Wrapf(err, "Method: %s", "newVPN")
+		}
+	}
+	if v.localIP6 != nil {
+		if err := internal.SetTunIP6(v.tun.Name(), v.localIP6, v.localNetmask6); err != nil {
+			return errors.Wrapf(err, "Method: %s", "newVPN")
+		}
 	}
 
 	// enable network settings
-	internal.SetPacketForward(true)
-	internal.SetPostRoutingMasquerade(true)
+	if v.localIP != nil {
+		internal.SetPacketForward(true)
+		internal.SetPostRoutingMasquerade(true)
+	}
+	if v.localIP6 != nil {
+		internal.SetPacketForward6(true)
+		internal.SetPostRoutingMasquerade6(true)
+	}
+
+	// run dns forwarder on vpn gateway ip
+	if v.dns != nil {
+		if err := v.dns.listen(v.localIP, v.localIP6); err != nil {
+			return errors.Wrapf(err, "Method: %s", "Run")
+		}
+	}
 
 	// read packets from TUN
 	go v.loopReadFromTun()
@@ -182,13 +412,51 @@ func (v *vpn) GetJwtSalt() string {
 	return v.jwtSalt
 }
 
+// DecodeJwt decodes and verifies jwt issued by VPN.
+// refresh token isn't accepted as jwt.
+func (v *vpn) DecodeJwt(data string) (*jwt.Token, error) {
+	token, err := v.decodeJwt(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Method: DecodeJwt")
+	}
+	if token.Claims.(*internal.VpnClaims).Subject != jwtSubject {
+		return nil, errors.Wrapf(internal.ErrorInvalidJWT, "Method: DecodeJwt")
+	}
+	return token, nil
+}
+
+// decodeJwt verifies jwt with asymmetric keys if they exist, otherwise with JWT Salt.
+func (v *vpn) decodeJwt(data string) (*jwt.Token, error) {
+	if v.jwtKeySet != nil {
+		return v.jwtKeySet.DecodeJWT(data)
+	}
+	return internal.DecodeJWT(data, []byte(v.jwtSalt))
+}
+
+// encodeJwt signs jwt with asymmetric keys if they exist, otherwise with JWT Salt.
+func (v *vpn) encodeJwt(claims *internal.VpnClaims) (string, error) {
+	if v.jwtKeySet != nil {
+		return v.jwtKeySet.EncodeJWT(claims)
+	}
+	return internal.EncodeJWT(claims, []byte(v.jwtSalt))
+}
+
 func (v *vpn) Close() error {
+	if v.dns != nil {
+		v.dns.close()
+	}
 	if err := v.tun.Close(); err != nil {
 		defaultLogger.Error(color.RedString("[err] Close %s", err.Error()))
 	}
 	// disable network settings
-	internal.SetPacketForward(false)
-	internal.SetPostRoutingMasquerade(false)
+	if v.localIP != nil {
+		internal.SetPacketForward(false)
+		internal.SetPostRoutingMasquerade(false)
+	}
+	if v.localIP6 != nil {
+		internal.SetPacketForward6(false)
+		internal.SetPostRoutingMasquerade6(false)
+	}
 	return nil
 }
 
@@ -201,28 +469,49 @@ func (v *vpn) addClient(c *client) error {
 	defer v.clientsLock.Unlock()
 
 	// issue vpn ip.
-	for ip := v.localIP.Mask(v.localNetmask.Mask); v.localNetmask.Contains(ip); internal.IncreaseIP(ip) {
-		// continue when such as below conditions.
-		if ip.String() == v.localIP.String() || strings.HasSuffix(ip.String(), ".0") ||
-			strings.HasSuffix(ip.String(), ".255") || strings.HasSuffix(ip.String(), ":") {
-			continue
+	var vpnIP, vpnIP6 net.IP
+	if v.localIP != nil {
+		ip, err := v.allocateIP(c.user, v.localIP, v.localNetmask)
+		if err != nil {
+			return errors.Wrapf(err, "Method: addClient")
 		}
-
-		// continue if other user is used.
-		if _, ok := v.clients[ip.String()]; ok {
-			continue
+		vpnIP = ip
+	}
+	if v.localIP6 != nil {
+		ip, err := v.allocateIP(c.user, v.localIP6, v.localNetmask6)
+		if err != nil {
+			if vpnIP != nil {
+				_ = v.ipam.Release(c.user, vpnIP)
+			}
+			return errors.Wrapf(err, "Method: addClient")
 		}
+		vpnIP6 = ip
+	}
 
-		// assign vpn ip
-		c.vpnIP = ip
+	// assign vpn ip
+	c.vpnIP = vpnIP
+	c.vpnIP6 = vpnIP6
 
-		// register
+	// register
+	for _, ip := range c.vpnIPs() {
 		v.clients[ip.String()] = c
+	}
+	return nil
+}
 
-		return nil
+// allocateIP allocates vpn ip to user from ipam, and refuses an ip which is reserved to other user.
+func (v *vpn) allocateIP(user string, gateway net.IP, subnet *net.IPNet) (net.IP, error) {
+	ip, err := v.ipam.Allocate(user, gateway, subnet)
+	if err != nil {
+		return nil, err
 	}
 
-	return errors.Wrapf(internal.ErrorExceedClientPool, "Method: addClient")
+	// memoryIPAM never allocates ip reserved to other user, it's a safety net for custom IPAM implementations.
+	if owner, ok := v.reservations[ip.String()]; ok && owner != user {
+		_ = v.ipam.Release(user, ip)
+		return nil, errors.Wrapf(internal.ErrorInvalidReservation, "Method: allocateIP %s", ip.String())
+	}
+	return ip, nil
 }
 
 // deleteClient is to delete client to map.
@@ -234,8 +523,11 @@ func (v *vpn) deleteClient(c *client) error {
 	defer v.clientsLock.Unlock()
 
 	// delete
-	if _, ok := v.clients[c.vpnIP.String()]; ok {
-		delete(v.clients, c.vpnIP.String())
+	for _, ip := range c.vpnIPs() {
+		if cli, ok := v.clients[ip.String()]; ok && cli == c {
+			delete(v.clients, ip.String())
+			_ = v.ipam.Release(c.user, ip)
+		}
 	}
 
 	return nil
@@ -282,16 +574,25 @@ ClientToServerExit:
 			}
 
 			// extract destination
-			dest := waterutil.IPv4Destination(packet.Packet1.Raw)
+			dest := internal.PacketDestination(packet.Packet1.Raw)
+
+			// ignore invalid packet and multicast
+			if dest == nil || dest.IsMulticast() {
+				continue
+			}
 
-			// ignore multicast
-			if dest.IsMulticast() {
+			// check network acl of source client.
+			srcVpnClient := v.getClient(internal.PacketSource(packet.Packet1.Raw))
+			if v.acl != nil && !v.acl.allow(srcVpnClient, packet.Packet1.Raw) {
 				continue
 			}
 
 			// if destination is a vpn client.
 			innerVpnClient := v.getClient(dest)
 			if innerVpnClient != nil {
+				if v.isIsolated(srcVpnClient, innerVpnClient) {
+					continue
+				}
 				innerVpnClient.in <- packet
 				continue
 			}
@@ -323,10 +624,10 @@ func (v *vpn) loopServerToClient() {
 			}
 
 			// extract destination
-			dest := waterutil.IPv4Destination(packet.Packet1.Raw)
+			dest := internal.PacketDestination(packet.Packet1.Raw)
 
-			// ignore multicast
-			if dest.IsMulticast() {
+			// ignore invalid packet and multicast
+			if dest == nil || dest.IsMulticast() {
 				continue
 			}
 
@@ -342,38 +643,218 @@ func (v *vpn) loopServerToClient() {
 	v.exit <- true
 }
 
-// newVPN return new vpn object.
-func newVPN(subnet, jwtSalt string, jwtExpiration time.Duration) (VPN, error) {
-	if subnet == "" || jwtSalt == "" {
+// newVPN return new vpn object from config.
+// vpn subnets can include an IPv4 subnet and an IPv6 subnet for dual stack.
+// ip reservations of config are pinned to users in ipam, so they must be in vpn subnets.
+func newVPN(cfg *config) (VPN, error) {
+	if cfg == nil || cfg.vpnJwtSalt == "" {
 		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: %s", "newVPN")
 	}
 
+	// make ip address manager
+	ipam := cfg.ipam
+	if ipam == nil {
+		ipam = NewMemoryIPAM(cfg.ipamLeaseTTL)
+	}
+
+	// make revocation store
+	revocation := cfg.revocation
+	if revocation == nil {
+		revocation = NewMemoryRevocationStore()
+	}
+
 	v := &vpn{
-		clients:        map[string]*client{},
-		clientToServer: make(chan *protocol.IPPacket, queueSizeForClientToServer),
-		serverToClient: make(chan *protocol.IPPacket, queueSizeForServerToClient),
-		jwtSalt:        jwtSalt,
-		jwtExpiration:  jwtExpiration,
-		exit:           make(chan bool, 1),
+		clients:                map[string]*client{},
+		clientToServer:         make(chan *protocol.IPPacket, queueSizeForClientToServer),
+		serverToClient:         make(chan *protocol.IPPacket, queueSizeForServerToClient),
+		jwtSalt:                cfg.vpnJwtSalt,
+		jwtKeySet:              cfg.vpnJwtKeySet,
+		jwtExpiration:          cfg.vpnJwtExpiration,
+		refreshTokenExpiration: cfg.vpnRefreshTokenExpiration,
+		ipam:                   ipam,
+		reservations:           map[string]string{},
+		groups:                 map[string][]string{},
+		clientIsolation:        cfg.clientIsolation,
+		revocation:             revocation,
+		sessionGracePeriod:     cfg.vpnSessionGracePeriod,
+		mfaStore:               cfg.mfaStore,
+		mfaRequired:            cfg.mfaRequired,
+		mfaChallenges:          map[string]*mfaChallenge{},
+		mfaUsedCounters:        map[string]int64{},
+		detached:               map[string]*session{},
+		startedAt:              time.Now(),
+		fullTunnel:             cfg.vpnFullTunnel || len(cfg.vpnRoutes) == 0,
+		dnsSearchDomains:       cfg.vpnDNSSearchDomains,
+		exit:                   make(chan bool, 1),
 	}
 
-	// parse ip and netmask from subnet.
-	ip, netmask, err := net.ParseCIDR(subnet)
-	if err != nil {
-		return nil, errors.Wrapf(err, "Method: %s", "newVPN")
+	for _, subnet := range []string{cfg.vpnSubNet, cfg.vpnSubNet6} {
+		if subnet == "" {
+			continue
+		}
+
+		// parse ip and netmask from subnet.
+		ip, netmask, err := net.ParseCIDR(subnet)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Method: %s", "newVPN")
+		}
+
+		// if ip is a network address(such as .0), ip increase +1.
+		if ip.To4() != nil {
+			ip = ip.To4()
+		}
+		if ip.Equal(netmask.IP) {
+			internal.IncreaseIP(ip)
+		}
+
+		// only one subnet per ip version.
+		if ip.To4() != nil {
+			if v.localIP != nil {
+				return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: %s", "newVPN")
+			}
+			v.localIP = ip
+			v.localNetmask = netmask
+		} else {
+			if v.localIP6 != nil {
+				return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: %s", "newVPN")
+			}
+			v.localIP6 = ip
+			v.localNetmask6 = netmask
+		}
 	}
 
-	// if suffix of ip is .0, ip increase +1.
-	if strings.HasSuffix(ip.String(), ".0") {
-		internal.IncreaseIP(ip)
+	if v.localIP == nil && v.localIP6 == nil {
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: %s", "newVPN")
 	}
 
-	v.localIP = ip
-	v.localNetmask = netmask
+	// reserve static ips.
+	for user, ips := range cfg.ipReservations {
+		for _, raw := range ips {
+			ip := net.ParseIP(raw)
+			if ip == nil || !v.isReservableIP(ip) {
+				return nil, errors.Wrapf(internal.ErrorInvalidReservation, "Method: newVPN %s %s", user, raw)
+			}
+			if ip.To4() != nil {
+				ip = ip.To4()
+			}
+			if owner, ok := v.reservations[ip.String()]; ok && owner != user {
+				return nil, errors.Wrapf(internal.ErrorInvalidReservation, "Method: newVPN %s %s", user, raw)
+			}
+			if err := v.ipam.Reserve(user, ip); err != nil {
+				return nil, errors.Wrapf(err, "Method: newVPN")
+			}
+			v.reservations[ip.String()] = user
+		}
+	}
+
+	// routes pushed to clients(split tunneling).
+	for _, route := range cfg.vpnRoutes {
+		_, subnet, err := net.ParseCIDR(route)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Method: %s", "newVPN")
+		}
+		v.routes = append(v.routes, subnet.String())
+	}
+
+	// dns servers pushed to clients.
+	for _, server := range cfg.vpnDNSServers {
+		ip := net.ParseIP(server)
+		if ip == nil {
+			return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: newVPN %s", server)
+		}
+		v.dnsServers = append(v.dnsServers, ip.String())
+	}
+
+	// groups of users.
+	for group, users := range cfg.userGroups {
+		for _, user := range users {
+			v.groups[user] = append(v.groups[user], group)
+		}
+	}
+	for _, groups := range v.groups {
+		sort.Strings(groups)
+	}
+
+	// traffic between clients.
+	switch v.clientIsolation {
+	case "":
+		v.clientIsolation = ClientIsolationAllowAll
+	case ClientIsolationAllowAll, ClientIsolationDenyAll, ClientIsolationSameGroup:
+	default:
+		return nil, errors.Wrapf(internal.ErrorInvalidParams, "Method: newVPN %s", v.clientIsolation)
+	}
+
+	// network acl.
+	if cfg.acl != nil {
+		acl, err := newACL(cfg.acl)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Method: newVPN")
+		}
+		v.acl = acl
+	}
+
+	// dns forwarder on vpn gateway ip.
+	if cfg.dnsServer != nil {
+		dns, err := newDNSForwarder(v, cfg.dnsServer)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Method: newVPN")
+		}
+		v.dns = dns
+
+		// if dns isn't inserted, clients use dns forwarder.
+		if len(v.dnsServers) == 0 {
+			for _, ip := range []net.IP{v.localIP, v.localIP6} {
+				if ip != nil {
+					v.dnsServers = append(v.dnsServers, ip.String())
+				}
+			}
+		}
+		if len(v.dnsSearchDomains) == 0 && dns.zone != "" {
+			v.dnsSearchDomains = []string{strings.TrimSuffix(dns.zone, ".")}
+		}
+	}
 
 	return v, nil
 }
 
+// isIsolated checks whether traffic from src client to dest client is denied or not.
+func (v *vpn) isIsolated(src, dest *client) bool {
+	switch v.clientIsolation {
+	case ClientIsolationAllowAll:
+		return false
+	case ClientIsolationSameGroup:
+		if src == nil || dest == nil {
+			return true
+		}
+		// the same user has always access to own clients.
+		if src.user == dest.user {
+			return false
+		}
+		for _, srcGroup := range src.groups {
+			for _, destGroup := range dest.groups {
+				if srcGroup == destGroup {
+					return false
+				}
+			}
+		}
+		return true
+	default:
+		return true
+	}
+}
+
+// isReservableIP checks whether ip is a host ip in vpn subnets or not.
+func (v *vpn) isReservableIP(ip net.IP) bool {
+	switch {
+	case v.localIP != nil && v.localNetmask.Contains(ip):
+		return !ip.Equal(v.localIP) && !internal.IsReservedIP(ip, v.localNetmask)
+	case v.localIP6 != nil && v.localNetmask6.Contains(ip):
+		return !ip.Equal(v.localIP6) && !internal.IsReservedIP(ip, v.localNetmask6)
+	default:
+		return false
+	}
+}
+
 // trap signal
 func (v *vpn) trapSignal() {
 	sig := make(chan os.Signal, 2)
@@ -393,10 +874,8 @@ func (v *vpn) trapSignal() {
 		v.stopping = true
 	}
 
-	// close clients.
-	v.clientsLock.Lock()
-	defer v.clientsLock.Unlock()
-	for _, c := range v.clients {
+	// close clients, sessions are used because dual-stack client has an entry per vpn ip in clients map.
+	for _, c := range v.sessions() {
 		go func(c *client) {
 			c.exit <- true
 		}(c)
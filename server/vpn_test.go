@@ -1 +1,476 @@
 package server
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gjbae1212/grpc-vpn/auth"
+	protocol "github.com/gjbae1212/grpc-vpn/grpc/go"
+	"github.com/gjbae1212/grpc-vpn/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+// testVPN returns dual stack vpn for tests, configure can modify config before vpn is created.
+func testVPN(t *testing.T, configure func(cfg *config)) *vpn {
+	cfg := &config{vpnSubNet: "10.10.10.1/24", vpnSubNet6: "fd00:10::1/64", vpnJwtSalt: "salt", ipam: NewMemoryIPAM(time.Hour)}
+	if configure != nil {
+		configure(cfg)
+	}
+	v, err := newVPN(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v.(*vpn)
+}
+
+func TestNewVPN(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		cfg   *config
+		ip    net.IP
+		ip6   net.IP
+		isErr bool
+	}{
+		"empty":          {isErr: true},
+		"empty-salt":     {cfg: &config{vpnSubNet: "10.10.10.1/24"}, isErr: true},
+		"empty-subnet":   {cfg: &config{vpnJwtSalt: "salt"}, isErr: true},
+		"invalid-subnet": {cfg: &config{vpnSubNet: "10.10.10.1", vpnJwtSalt: "salt"}, isErr: true},
+		"duplicated":     {cfg: &config{vpnSubNet: "10.10.10.1/24", vpnSubNet6: "10.10.20.1/24", vpnJwtSalt: "salt"}, isErr: true},
+		"invalid-route":  {cfg: &config{vpnSubNet: "10.10.10.1/24", vpnJwtSalt: "salt", vpnRoutes: []string{"allan"}}, isErr: true},
+		"invalid-dns":    {cfg: &config{vpnSubNet: "10.10.10.1/24", vpnJwtSalt: "salt", vpnDNSServers: []string{"allan"}}, isErr: true},
+		"ipv4":           {cfg: &config{vpnSubNet: "10.10.10.0/24", vpnJwtSalt: "salt"}, ip: net.ParseIP("10.10.10.1")},
+		"ipv6":           {cfg: &config{vpnSubNet: "fd00:10::/64", vpnJwtSalt: "salt"}, ip6: net.ParseIP("fd00:10::1")},
+		"dual-stack": {
+			cfg: &config{vpnSubNet: "10.10.10.1/24", vpnSubNet6: "fd00:10::1/64", vpnJwtSalt: "salt"},
+			ip:  net.ParseIP("10.10.10.1"), ip6: net.ParseIP("fd00:10::1"),
+		},
+	}
+
+	for _, t := range tests {
+		v, err := newVPN(t.cfg)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.True(t.ip.Equal(v.(*vpn).localIP))
+			assert.True(t.ip6.Equal(v.(*vpn).localIP6))
+		}
+	}
+}
+
+func TestVpn_DecodeJwt(t *testing.T) {
+	assert := assert.New(t)
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(err)
+	keySet, err := internal.NewJWTKeySet("kid", []*internal.JWTKey{
+		{Id: "kid", Method: internal.SigningMethodEdDSA, PrivateKey: private, PublicKey: private.Public()},
+	})
+	assert.NoError(err)
+
+	claims := &internal.VpnClaims{StandardClaims: jwt.StandardClaims{Audience: "allan", Subject: jwtSubject, ExpiresAt: time.Now().Add(time.Hour).Unix()}}
+	hs256, err := internal.EncodeJWT(claims, []byte("salt"))
+	assert.NoError(err)
+	eddsa, err := keySet.EncodeJWT(claims)
+	assert.NoError(err)
+
+	tests := map[string]struct {
+		cfg   *config
+		token string
+		isErr bool
+	}{
+		"salt":          {cfg: &config{vpnSubNet: "10.10.10.1/24", vpnJwtSalt: "salt"}, token: hs256},
+		"salt-eddsa":    {cfg: &config{vpnSubNet: "10.10.10.1/24", vpnJwtSalt: "salt"}, token: eddsa, isErr: true},
+		"key-set":       {cfg: &config{vpnSubNet: "10.10.10.1/24", vpnJwtSalt: "salt", vpnJwtKeySet: keySet}, token: eddsa},
+		"key-set-hs256": {cfg: &config{vpnSubNet: "10.10.10.1/24", vpnJwtSalt: "salt", vpnJwtKeySet: keySet}, token: hs256, isErr: true},
+	}
+
+	for _, t := range tests {
+		v, err := newVPN(t.cfg)
+		assert.NoError(err)
+
+		// jwt issued by vpn is verified by vpn.
+		issued, err := v.(*vpn).encodeJwt(claims)
+		assert.NoError(err)
+		_, err = v.DecodeJwt(issued)
+		assert.NoError(err)
+
+		token, err := v.DecodeJwt(t.token)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Equal("allan", token.Claims.(*internal.VpnClaims).Audience)
+		}
+	}
+}
+
+func TestVpn_renewJwt(t *testing.T) {
+	assert := assert.New(t)
+
+	v, err := newVPN(&config{vpnSubNet: "10.10.10.1/24", vpnJwtSalt: "salt",
+		vpnJwtExpiration: time.Hour, vpnRefreshTokenExpiration: 24 * time.Hour})
+	assert.NoError(err)
+	impl := v.(*vpn)
+
+	access, refresh, err := impl.issueJwt(identity{user: "allan", principal: "arn:aws:sts::123456789012:assumed-role/developer/allan",
+		groups: []string{"dev"}, roles: []string{"developer"}, attributes: map[string]string{"account_id": "123456789012"}})
+	assert.NoError(err)
+	assert.NotEmpty(refresh)
+
+	// refresh token isn't accepted as jwt.
+	_, err = impl.DecodeJwt(refresh)
+	assert.Error(err)
+
+	tests := map[string]struct {
+		user         string
+		refreshToken string
+	}{
+		"access-token": {user: "allan", refreshToken: access},
+		"other-user":   {user: "bob", refreshToken: refresh},
+	}
+
+	for _, t := range tests {
+		_, _, err := impl.renewJwt(t.user, t.refreshToken)
+		assert.Error(err)
+	}
+
+	token, renewed, err := impl.renewJwt("allan", refresh)
+	assert.NoError(err)
+	assert.Equal("allan", token.Claims.(*internal.VpnClaims).Audience)
+	// principal, groups, roles and attributes are kept after renewal.
+	assert.Equal("arn:aws:sts::123456789012:assumed-role/developer/allan", token.Claims.(*internal.VpnClaims).Principal)
+	assert.Equal([]string{"dev"}, token.Claims.(*internal.VpnClaims).Groups)
+	assert.Equal([]string{"developer"}, token.Claims.(*internal.VpnClaims).Roles)
+	assert.Equal(map[string]string{"account_id": "123456789012"}, token.Claims.(*internal.VpnClaims).Attributes)
+	assert.NotEqual(access, renewed.Jwt)
+	assert.NotEqual(refresh, renewed.RefreshToken)
+	_, err = impl.DecodeJwt(renewed.Jwt)
+	assert.NoError(err)
+
+	// refresh token is used only once.
+	_, _, err = impl.renewJwt("allan", refresh)
+	assert.Error(err)
+	_, _, err = impl.renewJwt("allan", renewed.RefreshToken)
+	assert.NoError(err)
+
+	// refresh token isn't issued if it's disabled.
+	impl.refreshTokenExpiration = 0
+	_, refresh, err = impl.issueJwt(identity{user: "allan"})
+	assert.NoError(err)
+	assert.Empty(refresh)
+}
+
+func TestVpn_addClient(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		subnets []string
+		count   int
+		ips     []net.IP
+		ip6s    []net.IP
+		isErr   bool
+	}{
+		"ipv4": {
+			subnets: []string{"10.10.10.1/30", ""},
+			count:   1,
+			ips:     []net.IP{net.ParseIP("10.10.10.2")},
+		},
+		"ipv4-exceed": {
+			subnets: []string{"10.10.10.1/30", ""},
+			count:   2,
+			isErr:   true,
+		},
+		"dual-stack": {
+			subnets: []string{"10.10.10.1/24", "fd00:10::1/64"},
+			count:   2,
+			ips:     []net.IP{net.ParseIP("10.10.10.2"), net.ParseIP("10.10.10.3")},
+			ip6s:    []net.IP{net.ParseIP("fd00:10::2"), net.ParseIP("fd00:10::3")},
+		},
+	}
+
+	for _, t := range tests {
+		v, err := newVPN(&config{vpnSubNet: t.subnets[0], vpnSubNet6: t.subnets[1], vpnJwtSalt: "salt",
+			ipam: NewMemoryIPAM(time.Hour)})
+		assert.NoError(err)
+
+		var clients []*client
+		for i := 0; i < t.count; i++ {
+			c := &client{}
+			err = v.(*vpn).addClient(c)
+			clients = append(clients, c)
+		}
+		assert.Equal(t.isErr, err != nil)
+		if t.isErr {
+			continue
+		}
+
+		for i, c := range clients {
+			if len(t.ips) > 0 {
+				assert.True(t.ips[i].Equal(c.vpnIP))
+				assert.Equal(c, v.(*vpn).getClient(t.ips[i]))
+			}
+			if len(t.ip6s) > 0 {
+				assert.True(t.ip6s[i].Equal(c.vpnIP6))
+				assert.Equal(c, v.(*vpn).getClient(t.ip6s[i]))
+			}
+			assert.NoError(v.(*vpn).deleteClient(c))
+		}
+		assert.Len(v.(*vpn).clients, 0)
+	}
+}
+
+func TestNewVPN_Reservations(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		subnets      []string
+		reservations map[string][]string
+		isErr        bool
+	}{
+		"invalid-ip":      {subnets: []string{"10.10.10.1/24", ""}, reservations: map[string][]string{"a": {"allan"}}, isErr: true},
+		"outside-subnet":  {subnets: []string{"10.10.10.1/24", ""}, reservations: map[string][]string{"a": {"10.10.20.2"}}, isErr: true},
+		"gateway":         {subnets: []string{"10.10.10.1/24", ""}, reservations: map[string][]string{"a": {"10.10.10.1"}}, isErr: true},
+		"broadcast":       {subnets: []string{"10.10.10.1/24", ""}, reservations: map[string][]string{"a": {"10.10.10.255"}}, isErr: true},
+		"outside-subnet6": {subnets: []string{"10.10.10.1/24", ""}, reservations: map[string][]string{"a": {"fd00:10::2"}}, isErr: true},
+		"duplicated": {
+			subnets:      []string{"10.10.10.1/24", ""},
+			reservations: map[string][]string{"a": {"10.10.10.2"}, "b": {"10.10.10.2"}},
+			isErr:        true,
+		},
+		"success": {
+			subnets:      []string{"10.10.10.1/24", "fd00:10::1/64"},
+			reservations: map[string][]string{"a": {"10.10.10.2", "fd00:10::2"}, "b": {"10.10.10.3"}},
+		},
+	}
+
+	for _, t := range tests {
+		_, err := newVPN(&config{vpnSubNet: t.subnets[0], vpnSubNet6: t.subnets[1], vpnJwtSalt: "salt",
+			ipReservations: t.reservations})
+		assert.Equal(t.isErr, err != nil)
+	}
+}
+
+func TestVpn_addClient_Reservations(t *testing.T) {
+	assert := assert.New(t)
+
+	reservations := map[string][]string{"reserved": {"10.10.10.2", "fd00:10::2"}}
+	tests := map[string]struct {
+		users []string
+		user  string
+		ip    net.IP
+		ip6   net.IP
+	}{
+		"reserved-user": {
+			users: []string{"a", "b"},
+			user:  "reserved",
+			ip:    net.ParseIP("10.10.10.2"),
+			ip6:   net.ParseIP("fd00:10::2"),
+		},
+		"other-user": {
+			user: "a",
+			ip:   net.ParseIP("10.10.10.3"),
+			ip6:  net.ParseIP("fd00:10::3"),
+		},
+	}
+
+	for _, t := range tests {
+		v, err := newVPN(&config{vpnSubNet: "10.10.10.1/24", vpnSubNet6: "fd00:10::1/64", vpnJwtSalt: "salt",
+			ipReservations: reservations})
+		assert.NoError(err)
+
+		for _, user := range t.users {
+			assert.NoError(v.(*vpn).addClient(&client{user: user}))
+		}
+
+		c := &client{user: t.user}
+		assert.NoError(v.(*vpn).addClient(c))
+		assert.True(t.ip.Equal(c.vpnIP))
+		assert.True(t.ip6.Equal(c.vpnIP6))
+	}
+}
+
+func TestNewVPN_Routes(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		routes     []string
+		fullTunnel bool
+		output     []string
+		full       bool
+	}{
+		"empty":       {full: true},
+		"split":       {routes: []string{"172.16.0.10/16", "fd00:20::/64"}, output: []string{"172.16.0.0/16", "fd00:20::/64"}},
+		"full-tunnel": {routes: []string{"172.16.0.0/16"}, fullTunnel: true, output: []string{"172.16.0.0/16"}, full: true},
+	}
+
+	for _, t := range tests {
+		v, err := newVPN(&config{vpnSubNet: "10.10.10.1/24", vpnJwtSalt: "salt",
+			vpnRoutes: t.routes, vpnFullTunnel: t.fullTunnel})
+		assert.NoError(err)
+		assert.Equal(t.output, v.(*vpn).routes)
+		assert.Equal(t.full, v.(*vpn).fullTunnel)
+	}
+}
+
+func TestNewVPN_DNS(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		servers []string
+		domains []string
+		output  []string
+	}{
+		"empty": {},
+		"dns":   {servers: []string{"10.10.10.1", "fd00::1"}, domains: []string{"corp.example.com"}, output: []string{"10.10.10.1", "fd00::1"}},
+	}
+
+	for _, t := range tests {
+		v, err := newVPN(&config{vpnSubNet: "10.10.10.1/24", vpnJwtSalt: "salt",
+			vpnDNSServers: t.servers, vpnDNSSearchDomains: t.domains})
+		assert.NoError(err)
+		assert.Equal(t.output, v.(*vpn).dnsServers)
+		assert.Equal(t.domains, v.(*vpn).dnsSearchDomains)
+	}
+}
+
+func TestNewVPN_DNSServer(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		cfg     *config
+		servers []string
+		domains []string
+	}{
+		"forwarder": {
+			cfg:     &config{vpnSubNet: "10.10.10.1/24", vpnSubNet6: "fd00::1/64", vpnJwtSalt: "salt", dnsServer: &DNSServerConfig{Domain: "example.com"}},
+			servers: []string{"10.10.10.1", "fd00::1"},
+			domains: []string{"vpn.example.com"},
+		},
+		"custom": {
+			cfg: &config{vpnSubNet: "10.10.10.1/24", vpnJwtSalt: "salt", dnsServer: &DNSServerConfig{Domain: "example.com"},
+				vpnDNSServers: []string{"8.8.8.8"}, vpnDNSSearchDomains: []string{"example.com"}},
+			servers: []string{"8.8.8.8"},
+			domains: []string{"example.com"},
+		},
+	}
+
+	for _, t := range tests {
+		v, err := newVPN(t.cfg)
+		assert.NoError(err)
+		assert.NotNil(v.(*vpn).dns)
+		assert.Equal(t.servers, v.(*vpn).dnsServers)
+		assert.Equal(t.domains, v.(*vpn).dnsSearchDomains)
+	}
+}
+
+func TestNewVPN_ACL(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		cfg    *config
+		groups map[string][]string
+		acl    bool
+		isErr  bool
+	}{
+		"groups": {
+			cfg: &config{vpnSubNet: "10.10.10.1/24", vpnJwtSalt: "salt",
+				userGroups: map[string][]string{"dev": {"allan", "bob"}, "admin": {"allan"}}},
+			groups: map[string][]string{"allan": {"admin", "dev"}, "bob": {"dev"}},
+		},
+		"acl": {
+			cfg:    &config{vpnSubNet: "10.10.10.1/24", vpnJwtSalt: "salt", acl: &ACLConfig{}},
+			groups: map[string][]string{},
+			acl:    true,
+		},
+		"invalid-acl": {
+			cfg:   &config{vpnSubNet: "10.10.10.1/24", vpnJwtSalt: "salt", acl: &ACLConfig{Rules: []*ACLRule{{Ports: []string{"ssh"}}}}},
+			isErr: true,
+		},
+	}
+
+	for _, t := range tests {
+		v, err := newVPN(t.cfg)
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Equal(t.groups, v.(*vpn).groups)
+			assert.Equal(t.acl, v.(*vpn).acl != nil)
+		}
+	}
+}
+
+func TestVpn_isIsolated(t *testing.T) {
+	assert := assert.New(t)
+
+	allan := &client{user: "allan", groups: []string{"dev"}}
+	bob := &client{user: "bob", groups: []string{"admin", "dev"}}
+	carl := &client{user: "carl", groups: []string{"admin"}}
+	dave := &client{user: "dave"}
+
+	tests := map[string]struct {
+		isolation ClientIsolation
+		src       *client
+		dest      *client
+		isolated  bool
+	}{
+		"allow-all":   {isolation: ClientIsolationAllowAll, src: allan, dest: dave},
+		"deny-all":    {isolation: ClientIsolationDenyAll, src: allan, dest: bob, isolated: true},
+		"same-group":  {isolation: ClientIsolationSameGroup, src: allan, dest: bob},
+		"other-group": {isolation: ClientIsolationSameGroup, src: allan, dest: carl, isolated: true},
+		"no-group":    {isolation: ClientIsolationSameGroup, src: dave, dest: allan, isolated: true},
+		"same-user":   {isolation: ClientIsolationSameGroup, src: dave, dest: &client{user: "dave"}},
+		"unknown-src": {isolation: ClientIsolationSameGroup, dest: allan, isolated: true},
+	}
+
+	for _, t := range tests {
+		v := &vpn{clientIsolation: t.isolation}
+		assert.Equal(t.isolated, v.isIsolated(t.src, t.dest))
+	}
+}
+
+func TestVpn_Auth_Claims(t *testing.T) {
+	assert := assert.New(t)
+
+	v := testVPN(t, nil)
+	ctx := context.WithValue(context.Background(), ipCtxName, net.ParseIP("1.1.1.1"))
+	ctx = context.WithValue(ctx, auth.UserCtxName, "developer/allan")
+	ctx = context.WithValue(ctx, auth.PrincipalCtxName, "arn:aws:sts::123456789012:assumed-role/developer/allan")
+	ctx = context.WithValue(ctx, auth.GroupsCtxName, []string{"dev"})
+	ctx = context.WithValue(ctx, auth.RolesCtxName, []string{"developer"})
+	ctx = context.WithValue(ctx, auth.AttributesCtxName, map[string]string{"account_id": "123456789012"})
+
+	resp, err := v.Auth(ctx, &protocol.AuthRequest{AuthType: protocol.AuthType_AT_AWS_IAM})
+	assert.NoError(err)
+	token, err := v.DecodeJwt(resp.Jwt)
+	assert.NoError(err)
+	claims := token.Claims.(*internal.VpnClaims)
+	assert.Equal("developer/allan", claims.Audience)
+	assert.Equal("arn:aws:sts::123456789012:assumed-role/developer/allan", claims.Principal)
+	assert.Equal([]string{"dev"}, claims.Groups)
+	assert.Equal([]string{"developer"}, claims.Roles)
+	assert.Equal(map[string]string{"account_id": "123456789012"}, claims.Attributes)
+}
+
+func TestNewVPN_ClientIsolation(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := map[string]struct {
+		isolation ClientIsolation
+		output    ClientIsolation
+		isErr     bool
+	}{
+		"empty":      {output: ClientIsolationAllowAll},
+		"same-group": {isolation: ClientIsolationSameGroup, output: ClientIsolationSameGroup},
+		"invalid":    {isolation: "allan", isErr: true},
+	}
+
+	for _, t := range tests {
+		v, err := newVPN(&config{vpnSubNet: "10.10.10.1/24", vpnJwtSalt: "salt", clientIsolation: t.isolation})
+		assert.Equal(t.isErr, err != nil)
+		if err == nil {
+			assert.Equal(t.output, v.(*vpn).clientIsolation)
+		}
+	}
+}